@@ -19,8 +19,10 @@ package main
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"strings"
 
 	"github.com/urfave/cli/v2"
@@ -29,6 +31,8 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/bls"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/vote"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/bls/blst"
 	blsCommon "github.com/ethereum/go-ethereum/crypto/bls/common"
@@ -279,8 +283,46 @@ The keyfile is assumed to contain an unencrypted private key in hexadecimal form
 You must input either keyfile or a pair of walletpath and passwordpath.
 `,
 			},
+			{
+				Name:  "bls-protection",
+				Usage: "Manage the slashing protection records of BLS finality votes",
+				Subcommands: []*cli.Command{
+					{
+						Name:      "import",
+						Usage:     "Import the slashing protection records from an interchange file",
+						Action:    blsProtectionImport,
+						Flags:     blsProtectionFlags,
+						ArgsUsage: "<interchangeFile>",
+						Description: `
+    ronin account bls-protection import <interchangeFile>
+
+Merges the finality votes signed by the BLS keys in the interchange file into the
+slashing protection records of the node. The node refuses to sign any vote that
+conflicts with the imported ones. The interchange file must belong to the same
+genesis as the node's database.`,
+					},
+					{
+						Name:      "export",
+						Usage:     "Export the slashing protection records to an interchange file",
+						Action:    blsProtectionExport,
+						Flags:     blsProtectionFlags,
+						ArgsUsage: "<interchangeFile>",
+						Description: `
+    ronin account bls-protection export <interchangeFile>
+
+Writes the finality votes signed by the BLS keys of the node to the interchange
+file, so that they can be imported into another node before it starts voting.`,
+					},
+				},
+			},
 		},
 	}
+
+	blsProtectionFlags = []cli.Flag{
+		utils.DataDirFlag,
+		utils.DBEngineFlag,
+		utils.AncientFlag,
+	}
 )
 
 func accountList(ctx *cli.Context) error {
@@ -696,3 +738,53 @@ func blsProofGenerate(ctx *cli.Context) error {
 
 	return nil
 }
+
+func blsProtectionImport(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	data, err := os.ReadFile(ctx.Args().First())
+	if err != nil {
+		utils.Fatalf("Failed to read interchange file, err %s", err)
+	}
+	interchange := new(vote.Interchange)
+	if err := json.Unmarshal(data, interchange); err != nil {
+		utils.Fatalf("Failed to decode interchange file, err %s", err)
+	}
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	genesisHash := rawdb.ReadCanonicalHash(db, 0)
+	if err := vote.NewSlashingProtection(db).Import(interchange, genesisHash); err != nil {
+		utils.Fatalf("Failed to import slashing protection records, err %s", err)
+	}
+	fmt.Printf("Imported slashing protection records of %d BLS keys\n", len(interchange.Data))
+	return nil
+}
+
+func blsProtectionExport(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	interchange := vote.NewSlashingProtection(db).Export(rawdb.ReadCanonicalHash(db, 0))
+	data, err := json.MarshalIndent(interchange, "", "  ")
+	if err != nil {
+		utils.Fatalf("Failed to encode slashing protection records, err %s", err)
+	}
+	if err := os.WriteFile(ctx.Args().First(), data, 0600); err != nil {
+		utils.Fatalf("Failed to write interchange file, err %s", err)
+	}
+	fmt.Printf("Exported slashing protection records of %d BLS keys\n", len(interchange.Data))
+	return nil
+}
@@ -18,6 +18,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -33,8 +34,12 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/forkid"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p"
@@ -75,6 +80,7 @@ Remove blockchain and state databases`,
 			dbImportCmd,
 			dbExportCmd,
 			dbInspectEnodeDBCmd,
+			dbBackfillInternalTxsCmd,
 		},
 	}
 	dbInspectCmd = &cli.Command{
@@ -250,6 +256,48 @@ WARNING: This is a low-level operation which may cause database corruption!`,
 		},
 		Description: "Exports the specified chain data to an RLP encoded stream, optionally gzip-compressed.",
 	}
+	dbBackfillInternalTxsCmd = &cli.Command{
+		Action: backfillInternalTxs,
+		Name:   "backfill-internaltxs",
+		Usage:  "Re-execute historical blocks to populate their internal transactions",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.DBEngineFlag,
+			utils.AncientFlag,
+			utils.SyncModeFlag,
+			utils.GCModeFlag,
+			utils.StateSchemeFlag,
+			utils.CacheFlag,
+			utils.CacheDatabaseFlag,
+			utils.MainnetFlag,
+			utils.RopstenFlag,
+			utils.SepoliaFlag,
+			utils.RinkebyFlag,
+			utils.GoerliFlag,
+			&cli.Uint64Flag{
+				Name:  "from",
+				Usage: "First block of the range to backfill",
+				Value: 1,
+			},
+			&cli.Uint64Flag{
+				Name:  "to",
+				Usage: "Last block of the range to backfill (default = current head)",
+			},
+			&cli.Uint64Flag{
+				Name:  "reexec",
+				Usage: "Maximum number of blocks to re-execute to regenerate the state of the first block",
+				Value: 128,
+			},
+			&cli.BoolFlag{
+				Name:  "restart",
+				Usage: "Ignore the progress marker of a previously interrupted backfill",
+			},
+		},
+		Description: `This command replays the given block range on top of the historical state
+and stores the internal transactions emitted while processing each block. The
+progress is persisted, so an interrupted backfill of the same range resumes from
+the last written block.`,
+	}
 	dbInspectEnodeDBCmd = &cli.Command{
 		Action: dbInspectEnodeDB,
 		Name:   "inspect-enodedb",
@@ -814,3 +862,142 @@ func exportChaindata(ctx *cli.Context) error {
 	db := utils.MakeChainDatabase(ctx, stack, true)
 	return utils.ExportChaindata(ctx.Args().Get(1), kind, exporter(db), stop)
 }
+
+func backfillInternalTxs(ctx *cli.Context) error {
+	var (
+		stack, _  = makeConfigNode(ctx)
+		interrupt = make(chan os.Signal, 1)
+		stop      = make(chan struct{})
+	)
+	defer stack.Close()
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+	defer close(interrupt)
+	go func() {
+		if _, ok := <-interrupt; ok {
+			log.Info("Interrupted during internal transactions backfill, stopping at next block")
+		}
+		close(stop)
+	}()
+
+	chain, db := utils.MakeChain(ctx, stack)
+	defer db.Close()
+	defer chain.Stop()
+
+	var (
+		from = ctx.Uint64("from")
+		to   = chain.CurrentBlock().NumberU64()
+	)
+	if ctx.IsSet("to") {
+		if ctx.Uint64("to") > to {
+			return fmt.Errorf("block #%d is above the current head #%d", ctx.Uint64("to"), to)
+		}
+		to = ctx.Uint64("to")
+	}
+	if from == 0 {
+		from = 1 // Genesis has no transactions to replay
+	}
+	if from > to {
+		return fmt.Errorf("invalid block range [%d, %d]", from, to)
+	}
+	if !rawdb.ReadStoreInternalTransactionsEnabled(db) {
+		log.Warn("Internal transactions of new blocks are not stored", "flag", utils.StoreInternalTransactions.Name)
+	}
+	return backfillInternalTxsRange(chain, db, from, to, ctx.Uint64("reexec"), ctx.Bool("restart"), stop)
+}
+
+// backfillInternalTxsRange re-executes the blocks of the range and stores their
+// internal transactions. The progress is persisted along the way, so that an
+// interrupted backfill of the same range is resumed unless restarted. The
+// progress is kept once the range is done, running it again is a no-op.
+func backfillInternalTxsRange(chain *core.BlockChain, db ethdb.Database, from, to, reexec uint64, restart bool, stop <-chan struct{}) error {
+	start := from
+	if progressFrom, next, ok := rawdb.ReadInternalTxsBackfillProgress(db); ok && !restart && progressFrom == from {
+		if next > to {
+			log.Info("Internal transactions already backfilled", "from", from, "to", to)
+			return nil
+		}
+		log.Info("Resuming internal transactions backfill", "from", from, "next", next)
+		start = next
+	}
+
+	// Regenerate the parent state over an ephemeral trie database, so nothing
+	// produced by the replay leaks into the live state.
+	backend, fixup := eth.MakeEthApiBackend(db)
+	fixup(chain, chain.Engine())
+	parent := chain.GetBlockByNumber(start - 1)
+	if parent == nil {
+		return fmt.Errorf("block #%d not found", start-1)
+	}
+	statedb, release, err := backend.StateAtBlock(context.Background(), parent, reexec, nil, false, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	var (
+		events   = core.InternalTransactionOpEvents()
+		batch    = db.NewBatch()
+		triedb   = statedb.Database().TrieDB()
+		prevRoot common.Hash
+		begin    = time.Now()
+		logged   = time.Now()
+		count    int
+	)
+	for number := start; number <= to; number++ {
+		select {
+		case <-stop:
+			rawdb.WriteInternalTxsBackfillProgress(batch, from, number)
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			log.Info("Internal transactions backfill interrupted", "next", number)
+			return nil
+		default:
+		}
+		block := chain.GetBlockByNumber(number)
+		if block == nil {
+			return fmt.Errorf("block #%d not found", number)
+		}
+		_, _, internalTxs, _, err := chain.Processor().Process(block, statedb, vm.Config{}, events...)
+		if err != nil {
+			return fmt.Errorf("processing block %d failed: %v", number, err)
+		}
+		root, err := statedb.Commit(number, chain.Config().IsEIP158(block.Number()))
+		if err != nil {
+			return fmt.Errorf("state commit failed, number %d: %v", number, err)
+		}
+		if root != block.Root() {
+			return fmt.Errorf("state root mismatch at block %d: have %x, want %x", number, root, block.Root())
+		}
+		if statedb, err = state.New(root, statedb.Database(), nil); err != nil {
+			return fmt.Errorf("state reset after block %d failed: %v", number, err)
+		}
+		// Hold the state reference and drop the previous one to prevent
+		// accumulating too many nodes in memory.
+		triedb.Reference(root, common.Hash{})
+		if prevRoot != (common.Hash{}) {
+			triedb.Dereference(prevRoot)
+		}
+		prevRoot = root
+
+		rawdb.WriteInternalTransactions(batch, block.Hash(), internalTxs)
+		count += len(internalTxs)
+		if batch.ValueSize() > ethdb.IdealBatchSize || number == to {
+			rawdb.WriteInternalTxsBackfillProgress(batch, from, number+1)
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Backfilling internal transactions", "number", number, "to", to, "internaltxs", count, "elapsed", common.PrettyDuration(time.Since(begin)))
+			logged = time.Now()
+		}
+	}
+	if prevRoot != (common.Hash{}) {
+		triedb.Dereference(prevRoot)
+	}
+	log.Info("Backfilled internal transactions", "from", from, "to", to, "internaltxs", count, "elapsed", common.PrettyDuration(time.Since(begin)))
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// newBackfillTestChain creates a chain whose blocks each call a contract making
+// an internal call.
+func newBackfillTestChain(t *testing.T, blocks int) *core.BlockChain {
+	var (
+		key, _   = crypto.GenerateKey()
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.HexToAddress("0xc0de")
+		callee   = common.HexToAddress("0xbeef")
+		// CALL(gas, callee, 0, 0, 0, 0, 0)
+		code = append(append([]byte{
+			byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0,
+			byte(vm.PUSH20)}, callee.Bytes()...),
+			byte(vm.GAS), byte(vm.CALL), byte(vm.STOP),
+		)
+		gspec = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				sender:   {Balance: big.NewInt(params.Ether)},
+				contract: {Code: code, Balance: common.Big0},
+			},
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+	db, generated, _ := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), blocks, func(i int, block *core.BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(sender), contract, common.Big0, 100_000, block.BaseFee(), nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block.AddTx(tx)
+	})
+	chain, err := core.NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	if _, err := chain.InsertChain(generated, nil); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	return chain
+}
+
+func TestBackfillInternalTxs(t *testing.T) {
+	chain := newBackfillTestChain(t, 6)
+	defer chain.Stop()
+	db := chain.DB()
+
+	backfilled := func(number uint64) bool {
+		return len(rawdb.ReadInternalTransactions(db, chain.GetHeaderByNumber(number).Hash())) != 0
+	}
+
+	// An interrupted backfill stores the block to resume from
+	stop := make(chan struct{})
+	close(stop)
+	if err := backfillInternalTxsRange(chain, db, 1, 6, 128, false, stop); err != nil {
+		t.Fatalf("failed to backfill: %v", err)
+	}
+	if from, next, ok := rawdb.ReadInternalTxsBackfillProgress(db); !ok || from != 1 || next != 1 {
+		t.Fatalf("unexpected progress after interruption: from %d, next %d, ok %t", from, next, ok)
+	}
+
+	// The backfill is resumed from the stored progress, the blocks before are
+	// not replayed
+	rawdb.WriteInternalTxsBackfillProgress(db, 1, 4)
+	if err := backfillInternalTxsRange(chain, db, 1, 6, 128, false, nil); err != nil {
+		t.Fatalf("failed to resume backfill: %v", err)
+	}
+	for number := uint64(1); number <= 6; number++ {
+		if backfilled(number) != (number >= 4) {
+			t.Errorf("block %d: unexpected backfill state %t", number, backfilled(number))
+		}
+	}
+	internalTxs := rawdb.ReadInternalTransactions(db, chain.GetHeaderByNumber(5).Hash())
+	if len(internalTxs) != 1 || internalTxs[0].To != common.HexToAddress("0xbeef") || internalTxs[0].Opcode != vm.CALL.String() {
+		t.Fatalf("unexpected internal transactions %v", internalTxs)
+	}
+	if from, next, ok := rawdb.ReadInternalTxsBackfillProgress(db); !ok || from != 1 || next != 7 {
+		t.Fatalf("unexpected progress after backfill: from %d, next %d, ok %t", from, next, ok)
+	}
+
+	// A restart replays the whole range
+	if err := backfillInternalTxsRange(chain, db, 1, 6, 128, true, nil); err != nil {
+		t.Fatalf("failed to restart backfill: %v", err)
+	}
+	for number := uint64(1); number <= 6; number++ {
+		if !backfilled(number) {
+			t.Errorf("block %d: not backfilled after restart", number)
+		}
+	}
+}
@@ -0,0 +1,771 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/bls"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	consortiumCommon "github.com/ethereum/go-ethereum/consensus/consortium/common"
+	legacyProfile "github.com/ethereum/go-ethereum/consensus/consortium/generated_contracts/legacy_profile"
+	"github.com/ethereum/go-ethereum/consensus/consortium/generated_contracts/profile"
+	roninValidatorSet "github.com/ethereum/go-ethereum/consensus/consortium/generated_contracts/ronin_validator_set"
+	"github.com/ethereum/go-ethereum/consensus/consortium/generated_contracts/staking"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/bls/blst"
+	blsCommon "github.com/ethereum/go-ethereum/crypto/bls/common"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/genesis"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/simulations/pipes"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	devnetValidatorsFlag = &cli.IntFlag{
+		Name:  "validators",
+		Usage: "Number of validators",
+		Value: 4,
+	}
+	devnetSeedFlag = &cli.StringFlag{
+		Name:  "seed",
+		Usage: "Seed the validator keys are derived from",
+		Value: "ronin-devnet",
+	}
+	devnetGenesisFlag = &cli.StringFlag{
+		Name:  "genesis",
+		Usage: "Genesis file the devnet genesis is based on, genesis/devnet.json is used if empty",
+	}
+	devnetForkFlag = &cli.StringSliceFlag{
+		Name:  "fork",
+		Usage: "Fork block as <name>=<number>, or <name>=off to disable the fork (e.g. --fork shillin=0 --fork tripp=20)",
+	}
+	devnetPeriodFlag = &cli.Uint64Flag{
+		Name:  "period",
+		Usage: "Number of seconds between blocks, the genesis one is used if 0",
+	}
+	devnetEpochFlag = &cli.Uint64Flag{
+		Name:  "epoch",
+		Usage: "Number of blocks of an epoch, the genesis one is used if 0",
+	}
+	devnetDataDirFlag = &cli.StringFlag{
+		Name:  "datadir",
+		Usage: "Data directory of the validators, a temporary directory is used if empty",
+	}
+	devnetHTTPPortFlag = &cli.IntFlag{
+		Name:  "http.port",
+		Usage: "HTTP-RPC port of the first validator, the next ones use the next ports (0 = disabled)",
+	}
+	devnetDumpFlag = &cli.BoolFlag{
+		Name:  "dump",
+		Usage: "Print the genesis and the validator keys without running the devnet",
+	}
+
+	devnetCommand = &cli.Command{
+		Action: runDevnet,
+		Name:   "devnet",
+		Usage:  "Run a local Consortium v2 network with multiple validators",
+		Flags: []cli.Flag{
+			devnetValidatorsFlag,
+			devnetSeedFlag,
+			devnetGenesisFlag,
+			devnetForkFlag,
+			devnetPeriodFlag,
+			devnetEpochFlag,
+			devnetDataDirFlag,
+			devnetHTTPPortFlag,
+			devnetDumpFlag,
+		},
+		Category: "MISCELLANEOUS COMMANDS",
+		Description: `
+The devnet command runs a network of Consortium v2 validators in-process, the
+validators are connected through in-memory pipes and vote for finality.
+
+The ECDSA and BLS keys of the validators are derived from --seed, so the same
+seed gives the same network. The genesis is based on genesis/devnet.json, the
+validator set is served by the system contracts allocated in the genesis, which
+return the devnet validators to the consensus engine. The fork blocks are set
+with --fork, e.g.
+
+    ronin devnet --validators 4 --fork shillin=0 --fork tripp=20 --fork aaron=40
+
+The genesis is written to the data directory and reused on restart.`,
+	}
+)
+
+// devnetValidator holds the keys of a devnet validator.
+type devnetValidator struct {
+	key    *ecdsa.PrivateKey
+	blsKey blsCommon.SecretKey
+	p2pKey *ecdsa.PrivateKey
+}
+
+func (v *devnetValidator) address() common.Address {
+	return crypto.PubkeyToAddress(v.key.PublicKey)
+}
+
+// devnetKeyJSON is the printed form of the validator keys.
+type devnetKeyJSON struct {
+	Address      common.Address `json:"address"`
+	PrivateKey   hexutil.Bytes  `json:"privateKey"`
+	BlsPublicKey hexutil.Bytes  `json:"blsPublicKey"`
+	BlsSecretKey hexutil.Bytes  `json:"blsSecretKey"`
+}
+
+// devnetDialer connects the devnet nodes through in-memory pipes.
+type devnetDialer struct {
+	lock    sync.RWMutex
+	servers map[enode.ID]*p2p.Server
+}
+
+// Dial implements p2p.NodeDialer, setting up the connection on the dialed node
+// as an inbound one.
+func (d *devnetDialer) Dial(ctx context.Context, dest *enode.Node) (net.Conn, error) {
+	d.lock.RLock()
+	srv := d.servers[dest.ID()]
+	d.lock.RUnlock()
+
+	if srv == nil {
+		return nil, fmt.Errorf("unknown node: %s", dest.ID())
+	}
+	local, remote, err := pipes.NetPipe()
+	if err != nil {
+		return nil, err
+	}
+	go srv.SetupConn(local, 0, nil)
+	return remote, nil
+}
+
+func (d *devnetDialer) register(srv *p2p.Server) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.servers[srv.Self().ID()] = srv
+}
+
+// devnetConfig contains the settings of the devnet.
+type devnetConfig struct {
+	Validators int      // Number of validators
+	Seed       string   // Seed the validator keys are derived from
+	Genesis    string   // Genesis file the devnet genesis is based on
+	Forks      []string // Fork blocks as <name>=<number>
+	Period     uint64   // Number of seconds between blocks
+	Epoch      uint64   // Number of blocks of an epoch
+	DataDir    string   // Data directory of the validators
+	HTTPPort   int      // HTTP-RPC port of the first validator
+}
+
+// devnet is a running network of validators connected in-process.
+type devnet struct {
+	genesis  *core.Genesis
+	stacks   []*node.Node
+	backends []*eth.Ethereum
+}
+
+// Close stops all the validators.
+func (d *devnet) Close() {
+	for _, stack := range d.stacks {
+		stack.Close()
+	}
+}
+
+// runDevnet generates the devnet and runs all validators until interrupted.
+func runDevnet(ctx *cli.Context) error {
+	config := &devnetConfig{
+		Validators: ctx.Int(devnetValidatorsFlag.Name),
+		Seed:       ctx.String(devnetSeedFlag.Name),
+		Genesis:    ctx.String(devnetGenesisFlag.Name),
+		Forks:      ctx.StringSlice(devnetForkFlag.Name),
+		Period:     ctx.Uint64(devnetPeriodFlag.Name),
+		Epoch:      ctx.Uint64(devnetEpochFlag.Name),
+		DataDir:    ctx.String(devnetDataDirFlag.Name),
+		HTTPPort:   ctx.Int(devnetHTTPPortFlag.Name),
+	}
+	if ctx.Bool(devnetDumpFlag.Name) {
+		validators, err := devnetValidators(config.Seed, config.Validators)
+		if err != nil {
+			return err
+		}
+		gen, err := devnetGenesis(config, validators)
+		if err != nil {
+			return err
+		}
+		return dumpDevnet(gen, validators)
+	}
+	if config.DataDir == "" {
+		datadir, err := os.MkdirTemp("", "ronin-devnet")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(datadir)
+		config.DataDir = datadir
+	}
+	network, err := startDevnet(config)
+	if err != nil {
+		return err
+	}
+	defer network.Close()
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigc)
+	<-sigc
+	log.Info("Got interrupt, shutting down devnet...")
+	return nil
+}
+
+// startDevnet generates the devnet into the data directory and starts all
+// validators sealing the blocks.
+func startDevnet(config *devnetConfig) (*devnet, error) {
+	if config.Validators <= 0 {
+		return nil, errors.New("at least one validator is required")
+	}
+	validators, err := devnetValidators(config.Seed, config.Validators)
+	if err != nil {
+		return nil, err
+	}
+	gen, err := devnetGenesis(config, validators)
+	if err != nil {
+		return nil, err
+	}
+	// The snapshot applying the first checkpoint of the chain takes the validator
+	// set from the mock validators when the checkpoint is the genesis
+	var addresses, publicKeys []string
+	for _, validator := range validators {
+		addresses = append(addresses, validator.address().Hex())
+		publicKeys = append(publicKeys, common.Bytes2Hex(validator.blsKey.PublicKey().Marshal()))
+	}
+	if err := consortiumCommon.SetMockValidators(strings.Join(addresses, ","), strings.Join(publicKeys, ","), ""); err != nil {
+		return nil, err
+	}
+
+	var (
+		network = &devnet{genesis: gen}
+		dialer  = &devnetDialer{servers: make(map[enode.ID]*p2p.Server)}
+		enodes  []*enode.Node
+	)
+	for i, validator := range validators {
+		stack, backend, err := startDevnetValidator(config, filepath.Join(config.DataDir, fmt.Sprintf("validator%d", i)), i, validator, gen, dialer)
+		if err != nil {
+			network.Close()
+			return nil, fmt.Errorf("failed to start validator %d: %v", i, err)
+		}
+		network.stacks = append(network.stacks, stack)
+		network.backends = append(network.backends, backend)
+
+		// Connect the validator to all the previous ones, the dialer ignores the
+		// endpoint which is only set to pass the dial checks
+		for _, n := range enodes {
+			stack.Server().AddPeer(n)
+		}
+		enodes = append(enodes, enode.NewV4(&validator.p2pKey.PublicKey, net.IPv4(127, 0, 0, 1), 30303, 0))
+	}
+	for i, backend := range network.backends {
+		if err := backend.StartMining(1); err != nil {
+			network.Close()
+			return nil, fmt.Errorf("failed to start validator %d: %v", i, err)
+		}
+	}
+	log.Info("Devnet started", "validators", len(validators), "chainid", gen.Config.ChainID, "genesis", gen.ToBlock().Hash(), "datadir", config.DataDir)
+	return network, nil
+}
+
+// devnetValidators derives the keys of the validators from the seed.
+func devnetValidators(seed string, count int) ([]*devnetValidator, error) {
+	validators := make([]*devnetValidator, count)
+	for i := range validators {
+		key, err := crypto.ToECDSA(devnetSecret(seed, "ecdsa", i, func(secret []byte) bool {
+			_, err := crypto.ToECDSA(secret)
+			return err == nil
+		}))
+		if err != nil {
+			return nil, err
+		}
+		p2pKey, err := crypto.ToECDSA(devnetSecret(seed, "p2p", i, func(secret []byte) bool {
+			_, err := crypto.ToECDSA(secret)
+			return err == nil
+		}))
+		if err != nil {
+			return nil, err
+		}
+		blsKey, err := blst.SecretKeyFromBytes(devnetSecret(seed, "bls", i, func(secret []byte) bool {
+			_, err := blst.SecretKeyFromBytes(secret)
+			return err == nil
+		}))
+		if err != nil {
+			return nil, err
+		}
+		validators[i] = &devnetValidator{key: key, blsKey: blsKey, p2pKey: p2pKey}
+	}
+	return validators, nil
+}
+
+// devnetSecret derives a secret of the validator from the seed, hashing it
+// again until it is a valid key.
+func devnetSecret(seed, kind string, index int, valid func([]byte) bool) []byte {
+	secret := crypto.Keccak256([]byte(fmt.Sprintf("%s/%s/%d", seed, kind, index)))
+	for !valid(secret) {
+		secret = crypto.Keccak256(secret)
+	}
+	return secret
+}
+
+// devnetGenesis returns the genesis stored in the data directory, or generates
+// it from the base genesis with the validators and the configured forks.
+func devnetGenesis(config *devnetConfig, validators []*devnetValidator) (*core.Genesis, error) {
+	path := filepath.Join(config.DataDir, "genesis.json")
+	if config.DataDir != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			gen := new(core.Genesis)
+			if err := json.Unmarshal(data, gen); err != nil {
+				return nil, fmt.Errorf("invalid devnet genesis %s: %v", path, err)
+			}
+			log.Info("Loaded devnet genesis, the generation flags are ignored", "path", path)
+			return gen, nil
+		}
+	}
+
+	data := genesis.Devnet
+	if config.Genesis != "" {
+		var err error
+		if data, err = os.ReadFile(config.Genesis); err != nil {
+			return nil, err
+		}
+	}
+	gen := new(core.Genesis)
+	if err := json.Unmarshal(data, gen); err != nil {
+		return nil, fmt.Errorf("invalid genesis: %v", err)
+	}
+	if gen.Config == nil || gen.Config.Consortium == nil || gen.Config.ConsortiumV2Contracts == nil {
+		return nil, errors.New("genesis has no consortium v2 config")
+	}
+	// The validator set of the genesis is read from the system contracts, which
+	// requires consortium v2 from the genesis on
+	gen.Config.ConsortiumV2Block = common.Big0
+	if err := applyDevnetForks(gen.Config, config.Forks); err != nil {
+		return nil, err
+	}
+	if config.Period != 0 {
+		gen.Config.Consortium.Period = config.Period
+	}
+	if config.Epoch != 0 {
+		gen.Config.Consortium.EpochV2 = config.Epoch
+	}
+	if err := gen.Config.CheckConfigForkOrder(); err != nil {
+		return nil, err
+	}
+	// The validator set of the genesis snapshot is read by number, which is only
+	// supported before Tripp
+	if gen.Config.IsTripp(common.Big0) {
+		return nil, errors.New("tripp must be activated after the genesis")
+	}
+
+	// Fund the validators and embed them into the extra data as the signers
+	var signers []common.Address
+	for _, validator := range validators {
+		signers = append(signers, validator.address())
+		gen.Alloc[validator.address()] = core.GenesisAccount{
+			Balance: new(big.Int).Mul(big.NewInt(1_000_000), big.NewInt(params.Ether)),
+		}
+	}
+	sort.Slice(signers, func(i, j int) bool {
+		return bytes.Compare(signers[i][:], signers[j][:]) < 0
+	})
+	gen.ExtraData = make([]byte, consortiumCommon.ExtraVanity+len(signers)*common.AddressLength+consortiumCommon.ExtraSeal)
+	for i, signer := range signers {
+		copy(gen.ExtraData[consortiumCommon.ExtraVanity+i*common.AddressLength:], signer[:])
+	}
+	if err := allocDevnetContracts(gen, validators); err != nil {
+		return nil, err
+	}
+
+	if config.DataDir != "" {
+		out, err := json.MarshalIndent(gen, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(config.DataDir, 0700); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, out, 0600); err != nil {
+			return nil, err
+		}
+	}
+	return gen, nil
+}
+
+// allocDevnetContracts allocates the system contracts serving the validator set
+// in the genesis. The contracts answer the calls of the consensus engine with
+// the devnet validators, equally staked, and accept any other call. The system
+// contracts already deployed in the genesis are kept.
+func allocDevnetContracts(gen *core.Genesis, validators []*devnetValidator) error {
+	validatorSetABI, err := roninValidatorSet.RoninValidatorSetMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+	profileABI, err := profile.ProfileMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+	legacyProfileABI, err := legacyProfile.ProfileMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+	stakingABI, err := staking.StakingMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+
+	var (
+		addresses = make([]common.Address, len(validators))
+		stakes    = make([]*big.Int, len(validators))
+		publicKey = make(map[common.Address][]byte)
+	)
+	for i, validator := range validators {
+		addresses[i] = validator.address()
+		stakes[i] = new(big.Int).Mul(big.NewInt(1_000), big.NewInt(params.Ether))
+		publicKey[addresses[i]] = validator.blsKey.PublicKey().Marshal()
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i][:], addresses[j][:]) < 0
+	})
+
+	contracts := []struct {
+		address common.Address
+		calls   []devnetContractCall
+	}{
+		{
+			gen.Config.ConsortiumV2Contracts.RoninValidatorSet,
+			[]devnetContractCall{
+				{validatorSetABI, "getBlockProducers", nil, []interface{}{addresses}},
+				{validatorSetABI, "getValidatorCandidates", nil, []interface{}{addresses}},
+				{validatorSetABI, "maxValidatorNumber", nil, []interface{}{big.NewInt(int64(len(addresses)))}},
+			},
+		},
+		{
+			gen.Config.ConsortiumV2Contracts.StakingContract,
+			[]devnetContractCall{
+				{stakingABI, "getManyStakingTotals", []interface{}{addresses}, []interface{}{stakes}},
+			},
+		},
+	}
+	var profileCalls []devnetContractCall
+	for _, address := range addresses {
+		profileCalls = append(profileCalls,
+			devnetContractCall{profileABI, "getConsensus2Id", []interface{}{address}, []interface{}{address}},
+			devnetContractCall{profileABI, "getId2Pubkey", []interface{}{address}, []interface{}{publicKey[address]}},
+			devnetContractCall{legacyProfileABI, "getId2Profile", []interface{}{address}, []interface{}{legacyProfile.IProfileCandidateProfile{
+				Id:        address,
+				Consensus: address,
+				Admin:     address,
+				Treasury:  address,
+				Governor:  address,
+				Pubkey:    publicKey[address],
+			}}},
+		)
+	}
+	contracts = append(contracts, struct {
+		address common.Address
+		calls   []devnetContractCall
+	}{gen.Config.ConsortiumV2Contracts.ProfileContract, profileCalls})
+
+	for _, contract := range contracts {
+		if account, ok := gen.Alloc[contract.address]; ok && len(account.Code) != 0 {
+			log.Info("Keeping the system contract of the genesis", "address", contract.address)
+			continue
+		}
+		code, err := devnetContractCode(contract.calls)
+		if err != nil {
+			return err
+		}
+		gen.Alloc[contract.address] = core.GenesisAccount{Code: code, Balance: common.Big0}
+	}
+	return nil
+}
+
+// devnetContractCall is a call answered by a devnet system contract.
+type devnetContractCall struct {
+	abi     *abi.ABI
+	method  string
+	inputs  []interface{}
+	outputs []interface{}
+}
+
+// devnetContractCode assembles the code of a contract returning the packed
+// outputs of the calls. The contract hashes the call data and returns the
+// outputs of the call with the same hash, other calls succeed without output.
+func devnetContractCode(calls []devnetContractCall) ([]byte, error) {
+	var (
+		hashes  []common.Hash
+		returns [][]byte
+	)
+	for _, call := range calls {
+		input, err := call.abi.Pack(call.method, call.inputs...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pack %s call: %v", call.method, err)
+		}
+		output, err := call.abi.Methods[call.method].Outputs.Pack(call.outputs...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pack %s output: %v", call.method, err)
+		}
+		hashes = append(hashes, crypto.Keccak256Hash(input))
+		returns = append(returns, output)
+	}
+
+	// The code hashes the call data, compares the hash with each call jumping to
+	// its return, and otherwise stops. The returns copy the output appended to
+	// the code into memory and return it.
+	const (
+		hashSize    = 10 // CALLDATASIZE PUSH1 PUSH1 CALLDATACOPY CALLDATASIZE PUSH1 SHA3
+		compareSize = 39 // DUP1 PUSH32 EQ PUSH2 JUMPI
+		returnSize  = 16 // JUMPDEST PUSH2 PUSH2 PUSH1 CODECOPY PUSH2 PUSH1 RETURN
+	)
+	var (
+		code     []byte
+		jumps    = hashSize + len(calls)*compareSize + 2 // Comparisons followed by POP STOP
+		offsets  []int
+		position = jumps + len(calls)*returnSize
+	)
+	for _, output := range returns {
+		offsets = append(offsets, position)
+		position += len(output)
+	}
+	if position > math.MaxUint16 {
+		return nil, errors.New("devnet contract too large")
+	}
+	code = append(code,
+		byte(vm.CALLDATASIZE), byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.CALLDATACOPY),
+		byte(vm.CALLDATASIZE), byte(vm.PUSH1), 0, byte(vm.SHA3),
+	)
+	for i, hash := range hashes {
+		dest := jumps + i*returnSize
+		code = append(code, byte(vm.DUP1), byte(vm.PUSH32))
+		code = append(code, hash[:]...)
+		code = append(code, byte(vm.EQ), byte(vm.PUSH2), byte(dest>>8), byte(dest), byte(vm.JUMPI))
+	}
+	code = append(code, byte(vm.POP), byte(vm.STOP))
+	for i, output := range returns {
+		size, offset := len(output), offsets[i]
+		code = append(code,
+			byte(vm.JUMPDEST),
+			byte(vm.PUSH2), byte(size>>8), byte(size),
+			byte(vm.PUSH2), byte(offset>>8), byte(offset),
+			byte(vm.PUSH1), 0, byte(vm.CODECOPY),
+			byte(vm.PUSH2), byte(size>>8), byte(size),
+			byte(vm.PUSH1), 0, byte(vm.RETURN),
+		)
+	}
+	for _, output := range returns {
+		code = append(code, output...)
+	}
+	return code, nil
+}
+
+// applyDevnetForks sets the fork blocks of the chain config, the forks are
+// named after the block fields of the config without the Block suffix.
+func applyDevnetForks(config *params.ChainConfig, forks []string) error {
+	if len(forks) == 0 {
+		return nil
+	}
+	fields := make(map[string]int)
+	configType := reflect.TypeOf(params.ChainConfig{})
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if field.Type == reflect.TypeOf(new(big.Int)) && strings.HasSuffix(name, "Block") {
+			fields[strings.ToLower(strings.TrimSuffix(name, "Block"))] = i
+		}
+	}
+
+	value := reflect.ValueOf(config).Elem()
+	for _, fork := range forks {
+		name, number, ok := strings.Cut(fork, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid fork %q, expected <name>=<number>", fork)
+		}
+		index, ok := fields[strings.ToLower(name)]
+		if !ok {
+			return fmt.Errorf("unknown fork %q", name)
+		}
+		if number == "off" {
+			value.Field(index).Set(reflect.Zero(configType.Field(index).Type))
+			continue
+		}
+		block, err := strconv.ParseUint(number, 0, 64)
+		if err != nil {
+			return fmt.Errorf("invalid fork block %q: %v", fork, err)
+		}
+		value.Field(index).Set(reflect.ValueOf(new(big.Int).SetUint64(block)))
+	}
+	return nil
+}
+
+// dumpDevnet prints the genesis and the validator keys.
+func dumpDevnet(gen *core.Genesis, validators []*devnetValidator) error {
+	keys := make([]devnetKeyJSON, len(validators))
+	for i, validator := range validators {
+		keys[i] = devnetKeyJSON{
+			Address:      validator.address(),
+			PrivateKey:   crypto.FromECDSA(validator.key),
+			BlsPublicKey: validator.blsKey.PublicKey().Marshal(),
+			BlsSecretKey: validator.blsKey.Marshal(),
+		}
+	}
+	out, err := json.MarshalIndent(struct {
+		Genesis    *core.Genesis   `json:"genesis"`
+		Validators []devnetKeyJSON `json:"validators"`
+	}{gen, keys}, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// startDevnetValidator starts a validator node with its keys imported, the node
+// is only reachable through the devnet dialer.
+func startDevnetValidator(devnetConfig *devnetConfig, datadir string, index int, validator *devnetValidator, gen *core.Genesis, dialer *devnetDialer) (*node.Node, *eth.Ethereum, error) {
+	blsWalletPath, blsPasswordPath, err := importDevnetBlsKey(datadir, validator.blsKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	config := &node.Config{
+		Name:    clientIdentifier,
+		Version: params.VersionWithMeta,
+		DataDir: datadir,
+		P2P: p2p.Config{
+			PrivateKey:  validator.p2pKey,
+			NoDiscovery: true,
+			MaxPeers:    2 * devnetConfig.Validators,
+		},
+		EnableFastFinality:       true,
+		EnableFastFinalitySign:   true,
+		BlsPasswordPath:          blsPasswordPath,
+		BlsWalletPath:            blsWalletPath,
+		MaxCurVoteAmountPerBlock: utils.MaxCurVoteAmountPerBlock.Value,
+	}
+	if port := devnetConfig.HTTPPort; port != 0 {
+		config.HTTPHost = "127.0.0.1"
+		config.HTTPPort = port + index
+		config.HTTPModules = []string{"admin", "eth", "net", "web3", "txpool", "consortiumv2"}
+		config.HTTPVirtualHosts = []string{"localhost"}
+	}
+	stack, err := node.New(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	stack.Server().Dialer = dialer
+
+	ethConfig := ethconfig.Defaults
+	ethConfig.Genesis = gen
+	ethConfig.NetworkId = gen.Config.ChainID.Uint64()
+	ethConfig.Miner.Etherbase = validator.address()
+	backend, err := eth.New(stack, &ethConfig)
+	if err != nil {
+		stack.Close()
+		return nil, nil, err
+	}
+
+	// Import the validator key to seal the blocks with
+	ks := keystore.NewKeyStore(stack.KeyStoreDir(), keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(validator.key, "")
+	if err != nil && !errors.Is(err, keystore.ErrAccountAlreadyExists) {
+		stack.Close()
+		return nil, nil, err
+	}
+	if err := ks.Unlock(account, ""); err != nil {
+		stack.Close()
+		return nil, nil, err
+	}
+	stack.AccountManager().AddBackend(ks)
+
+	if err := stack.Start(); err != nil {
+		stack.Close()
+		return nil, nil, err
+	}
+	dialer.register(stack.Server())
+	log.Info("Started devnet validator", "index", index, "address", validator.address(), "blspub", common.Bytes2Hex(validator.blsKey.PublicKey().Marshal()), "http", stack.HTTPEndpoint())
+	return stack, backend, nil
+}
+
+// importDevnetBlsKey imports the BLS key of the validator into a wallet in the
+// data directory, returning the paths of the wallet and its password file.
+func importDevnetBlsKey(datadir string, secretKey blsCommon.SecretKey) (string, string, error) {
+	var (
+		walletPath   = filepath.Join(datadir, "bls_keystore")
+		passwordPath = filepath.Join(datadir, "bls_password")
+	)
+	if err := os.MkdirAll(walletPath, 0700); err != nil {
+		return "", "", err
+	}
+	if _, err := os.Stat(passwordPath); errors.Is(err, os.ErrNotExist) {
+		if err := os.WriteFile(passwordPath, []byte("ronin-devnet"), 0600); err != nil {
+			return "", "", err
+		}
+	}
+	wallet, err := bls.New(walletPath, passwordPath)
+	if err != nil {
+		return "", "", err
+	}
+	km, err := bls.NewKeyManager(context.Background(), wallet)
+	if err != nil {
+		return "", "", err
+	}
+	publicKeys, err := km.FetchValidatingPublicKeys(context.Background())
+	if err != nil {
+		return "", "", err
+	}
+	publicKey := secretKey.PublicKey().Marshal()
+	for _, key := range publicKeys {
+		if bytes.Equal(key[:], publicKey) {
+			return walletPath, passwordPath, nil
+		}
+	}
+	if err := km.ImportKeypairs(context.Background(), [][]byte{secretKey.Marshal()}, [][]byte{publicKey}); err != nil {
+		return "", "", err
+	}
+	return walletPath, passwordPath, nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestDevnetValidators(t *testing.T) {
+	first, err := devnetValidators("seed", 3)
+	if err != nil {
+		t.Fatalf("failed to derive validators: %v", err)
+	}
+	second, err := devnetValidators("seed", 3)
+	if err != nil {
+		t.Fatalf("failed to derive validators: %v", err)
+	}
+	other, err := devnetValidators("other", 3)
+	if err != nil {
+		t.Fatalf("failed to derive validators: %v", err)
+	}
+	for i := range first {
+		if first[i].address() != second[i].address() || !first[i].blsKey.PublicKey().Equals(second[i].blsKey.PublicKey()) {
+			t.Errorf("validator %d: keys are not deterministic", i)
+		}
+		if first[i].address() == other[i].address() {
+			t.Errorf("validator %d: same key with another seed", i)
+		}
+	}
+	if first[0].address() == first[1].address() {
+		t.Error("validators share the same key")
+	}
+}
+
+func TestApplyDevnetForks(t *testing.T) {
+	config := &params.ChainConfig{
+		ChainID:      big.NewInt(2022),
+		ShillinBlock: big.NewInt(5),
+		TrippPeriod:  big.NewInt(10),
+		Consortium:   &params.ConsortiumConfig{Period: 3, EpochV2: 200},
+	}
+	if err := applyDevnetForks(config, []string{"tripp=20", "Aaron=0x1e", "shillin=off"}); err != nil {
+		t.Fatalf("failed to apply forks: %v", err)
+	}
+	if config.TrippBlock.Uint64() != 20 || config.AaronBlock.Uint64() != 30 || config.ShillinBlock != nil {
+		t.Errorf("unexpected forks: tripp %v, aaron %v, shillin %v", config.TrippBlock, config.AaronBlock, config.ShillinBlock)
+	}
+	if config.TrippPeriod.Uint64() != 10 || config.Consortium.EpochV2 != 200 {
+		t.Error("unrelated fields are changed")
+	}
+	for _, forks := range [][]string{{"unknown=1"}, {"tripp"}, {"tripp=x"}, {"trippPeriod=1"}, {"=5"}, {"="}} {
+		if err := applyDevnetForks(config, forks); err == nil {
+			t.Errorf("expected error for %v", forks)
+		}
+	}
+}
+
+func TestDevnetSealing(t *testing.T) {
+	network, err := startDevnet(&devnetConfig{
+		Validators: 1,
+		Seed:       "seed",
+		Period:     1,
+		DataDir:    t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("failed to start devnet: %v", err)
+	}
+	defer network.Close()
+
+	// The validator set is read from the genesis system contracts
+	backend := network.backends[0]
+	for deadline := time.Now().Add(30 * time.Second); backend.BlockChain().CurrentBlock().NumberU64() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("no block sealed")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	validators, _ := devnetValidators("seed", 1)
+	if block := backend.BlockChain().GetBlockByNumber(1); block.Coinbase() != validators[0].address() {
+		t.Errorf("block sealed by %x, expected %x", block.Coinbase(), validators[0].address())
+	}
+}
+
+func TestDevnetSealingValidators(t *testing.T) {
+	network, err := startDevnet(&devnetConfig{
+		Validators: 3,
+		Seed:       "seed",
+		Period:     1,
+		DataDir:    t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("failed to start devnet: %v", err)
+	}
+	defer network.Close()
+
+	// The validator set changes at block 1 from the genesis checkpoint, the
+	// chain keeps going after Tripp at block 10
+	trippBlock := network.genesis.Config.TrippBlock.Uint64()
+	backend := network.backends[0]
+	for deadline := time.Now().Add(60 * time.Second); backend.BlockChain().CurrentBlock().NumberU64() <= trippBlock+1; {
+		if time.Now().After(deadline) {
+			t.Fatalf("chain stuck at block %d", backend.BlockChain().CurrentBlock().NumberU64())
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	validators, _ := devnetValidators("seed", 3)
+	sealers := make(map[common.Address]struct{})
+	for _, validator := range validators {
+		sealers[validator.address()] = struct{}{}
+	}
+	for number := uint64(1); number <= trippBlock+1; number++ {
+		if _, ok := sealers[backend.BlockChain().GetBlockByNumber(number).Coinbase()]; !ok {
+			t.Errorf("block %d sealed by unknown validator", number)
+		}
+	}
+}
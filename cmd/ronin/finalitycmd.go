@@ -0,0 +1,287 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/consortium/v2/finality"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	finalityRPCFlag = &cli.StringFlag{
+		Name:  "rpc",
+		Usage: "RPC endpoint to fetch the header and the validator set from",
+	}
+	finalityValidatorsFlag = &cli.StringFlag{
+		Name:  "validators",
+		Usage: "JSON file of the validator set at the parent block, in the format of consortiumv2_getValidatorAtHash",
+	}
+	finalityGenesisFlag = &cli.StringFlag{
+		Name:  "genesis",
+		Usage: "Genesis file of the chain, the Ronin network of the RPC endpoint or the Ronin mainnet is used if empty",
+	}
+	finalityWeightedFlag = &cli.BoolFlag{
+		Name:  "weighted",
+		Usage: "Weight the votes by the validator weights as after Tripp, inferred from the validator set if not set",
+	}
+	finalityTimeoutFlag = &cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "Timeout of the RPC requests",
+		Value: 30 * time.Second,
+	}
+
+	finalityCommand = &cli.Command{
+		Name:        "finality",
+		Usage:       "A set of commands for the finality votes",
+		Category:    "MISCELLANEOUS COMMANDS",
+		Description: "",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "verify",
+				Usage:     "Verify the finality votes carried in a header",
+				ArgsUsage: "<header.rlp | number>",
+				Action:    verifyFinality,
+				Flags: []cli.Flag{
+					finalityRPCFlag,
+					finalityValidatorsFlag,
+					finalityGenesisFlag,
+					finalityWeightedFlag,
+					finalityTimeoutFlag,
+				},
+				Description: `
+ronin finality verify <header.rlp | number>
+decodes the extra data of the header and verifies the aggregated BLS signature
+of the finality votes for its parent block, without running a node.
+
+The header is read from a file holding its RLP encoding, in binary or in hex,
+or fetched by number from the --rpc endpoint. The validator set at the parent
+block is read from the --validators file, or fetched from the --rpc endpoint
+when the consortiumv2 namespace is enabled.
+
+The report lists the validators whose votes are included and the missing ones,
+the command fails if the votes do not finalize the parent block.`,
+			},
+		},
+	}
+)
+
+// finalityReport is the outcome of the verification of the finality votes in
+// a header.
+type finalityReport struct {
+	Number               uint64                         `json:"number"`
+	Hash                 common.Hash                    `json:"hash"`
+	TargetNumber         uint64                         `json:"targetNumber"`
+	TargetHash           common.Hash                    `json:"targetHash"`
+	HasFinalityVote      bool                           `json:"hasFinalityVote"`
+	Weighted             bool                           `json:"weighted"`
+	VoteWeight           int                            `json:"voteWeight"`
+	Threshold            int                            `json:"threshold"`
+	Voters               []finality.ValidatorWithBlsPub `json:"voters"`
+	Missing              []finality.ValidatorWithBlsPub `json:"missing"`
+	CheckpointValidators []finality.ValidatorWithBlsPub `json:"checkpointValidators,omitempty"`
+	BlockProducers       []common.Address               `json:"blockProducers,omitempty"`
+	Valid                bool                           `json:"valid"`
+	Error                string                         `json:"error,omitempty"`
+}
+
+// verifyFinality verifies the finality votes in the header and prints the report.
+func verifyFinality(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return errors.New("expected a header RLP file or a block number as argument")
+	}
+	var client *ethclient.Client
+	if url := ctx.String(finalityRPCFlag.Name); url != "" {
+		var err error
+		if client, err = ethclient.Dial(url); err != nil {
+			return fmt.Errorf("failed to connect to %s: %v", url, err)
+		}
+		defer client.Close()
+	}
+	rpcCtx, cancel := context.WithTimeout(context.Background(), ctx.Duration(finalityTimeoutFlag.Name))
+	defer cancel()
+
+	header, err := finalityHeader(rpcCtx, client, ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	chainConfig, err := finalityChainConfig(rpcCtx, client, ctx.String(finalityGenesisFlag.Name))
+	if err != nil {
+		return err
+	}
+	validators, err := finalityValidators(rpcCtx, client, ctx.String(finalityValidatorsFlag.Name), header)
+	if err != nil {
+		return err
+	}
+	weighted := chainConfig.IsTripp(header.Number)
+	if ctx.IsSet(finalityWeightedFlag.Name) {
+		weighted = ctx.Bool(finalityWeightedFlag.Name)
+	} else if weighted {
+		// Tripp takes effect at the first period after the fork, the validator
+		// weights are only set from then on
+		weighted = false
+		for _, validator := range validators {
+			if validator.Weight != 0 {
+				weighted = true
+				break
+			}
+		}
+	}
+
+	report := &finalityReport{
+		Number:   header.Number.Uint64(),
+		Hash:     header.Hash(),
+		Weighted: weighted,
+		Voters:   []finality.ValidatorWithBlsPub{},
+		Missing:  []finality.ValidatorWithBlsPub{},
+	}
+	extraData, proof, verifyErr := finality.VerifyHeaderFinality(chainConfig, header, validators, weighted)
+	if extraData != nil {
+		report.HasFinalityVote = extraData.HasFinalityVote == 1
+		report.CheckpointValidators = extraData.CheckpointValidators
+		report.BlockProducers = extraData.BlockProducers
+	}
+	if proof != nil {
+		report.TargetNumber, report.TargetHash = proof.TargetNumber, proof.TargetHash
+		report.VoteWeight, report.Threshold = proof.VoteWeight, proof.Threshold
+		if proof.Voters != nil {
+			report.Voters = proof.Voters
+		}
+		if proof.Missing != nil {
+			report.Missing = proof.Missing
+		}
+	}
+	report.Valid = verifyErr == nil
+	if verifyErr != nil {
+		report.Error = verifyErr.Error()
+	}
+
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	if verifyErr != nil {
+		return fmt.Errorf("invalid finality votes in block %d: %v", report.Number, verifyErr)
+	}
+	return nil
+}
+
+// finalityHeader reads the header RLP file, or fetches the header by number if
+// the argument is a number.
+func finalityHeader(ctx context.Context, client *ethclient.Client, arg string) (*types.Header, error) {
+	if number, err := strconv.ParseUint(arg, 0, 64); err == nil {
+		if client == nil {
+			return nil, errors.New("fetching a header by number requires --rpc")
+		}
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch header %d: %v", number, err)
+		}
+		return header, nil
+	}
+	data, err := os.ReadFile(arg)
+	if err != nil {
+		return nil, err
+	}
+	if text := strings.TrimSpace(string(data)); strings.HasPrefix(text, "0x") {
+		if data, err = hexutil.Decode(text); err != nil {
+			return nil, fmt.Errorf("invalid hex header: %v", err)
+		}
+	}
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(data, header); err != nil {
+		return nil, fmt.Errorf("invalid header RLP: %v", err)
+	}
+	if header.Number == nil {
+		return nil, errors.New("header has no number")
+	}
+	return header, nil
+}
+
+// finalityChainConfig returns the chain config of the genesis file, or of the
+// Ronin network of the RPC endpoint, defaulting to the Ronin mainnet.
+func finalityChainConfig(ctx context.Context, client *ethclient.Client, genesisPath string) (*params.ChainConfig, error) {
+	if genesisPath != "" {
+		file, err := os.Open(genesisPath)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		genesis := new(core.Genesis)
+		if err := json.NewDecoder(file).Decode(genesis); err != nil {
+			return nil, fmt.Errorf("invalid genesis file: %v", err)
+		}
+		if genesis.Config == nil {
+			return nil, errors.New("genesis file has no chain config")
+		}
+		return genesis.Config, nil
+	}
+	if client == nil {
+		return params.RoninMainnetChainConfig, nil
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chain id: %v", err)
+	}
+	for _, config := range []*params.ChainConfig{params.RoninMainnetChainConfig, params.RoninTestnetChainConfig} {
+		if config.ChainID.Cmp(chainID) == 0 {
+			return config, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown chain id %d, the genesis file is required", chainID)
+}
+
+// finalityValidators reads the validator set at the parent of the header from
+// the file, or fetches it from the RPC endpoint.
+func finalityValidators(ctx context.Context, client *ethclient.Client, path string, header *types.Header) ([]finality.ValidatorWithBlsPub, error) {
+	var validators []finality.ValidatorWithBlsPub
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &validators); err != nil {
+			return nil, fmt.Errorf("invalid validators file: %v", err)
+		}
+		return validators, nil
+	}
+	if client == nil {
+		return nil, errors.New("the validator set requires --validators or --rpc")
+	}
+	if err := client.RpcClient().CallContext(ctx, &validators, "consortiumv2_getValidatorAtHash", header.Hash()); err != nil {
+		return nil, fmt.Errorf("failed to fetch the validator set: %v", err)
+	}
+	return validators, nil
+}
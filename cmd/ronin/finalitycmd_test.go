@@ -0,0 +1,128 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/consortium/v2/finality"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/bls/blst"
+	blsCommon "github.com/ethereum/go-ethereum/crypto/bls/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestFinalityVerify(t *testing.T) {
+	dir := t.TempDir()
+	chainConfig := &params.ChainConfig{
+		ChainID:      big.NewInt(2021),
+		ShillinBlock: big.NewInt(0),
+		Consortium:   &params.ConsortiumConfig{Period: 3, EpochV2: 200},
+	}
+	writeJSON := func(name string, v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	genesisPath := writeJSON("genesis.json", &core.Genesis{Config: chainConfig, Difficulty: big.NewInt(1), Alloc: core.GenesisAlloc{}})
+
+	var (
+		keys       []blsCommon.SecretKey
+		validators []finality.ValidatorWithBlsPub
+	)
+	for i := 0; i < 4; i++ {
+		key, err := blst.RandKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+		validators = append(validators, finality.ValidatorWithBlsPub{Address: common.Address{byte(i + 1)}, BlsPublicKey: key.PublicKey()})
+	}
+	validatorsPath := writeJSON("validators.json", validators)
+
+	// header writes the hex RLP of a header carrying the votes of the voters
+	// for its parent block
+	header := func(name string, voters ...int) string {
+		header := &types.Header{Number: big.NewInt(10), ParentHash: common.Hash{0x1}, Difficulty: big.NewInt(7)}
+		voteData := &types.VoteData{TargetNumber: 9, TargetHash: header.ParentHash}
+		digest := voteData.Hash()
+
+		extraData := &finality.HeaderExtraData{HasFinalityVote: 1}
+		var signatures []blsCommon.Signature
+		for _, voter := range voters {
+			extraData.FinalityVotedValidators.SetBit(voter)
+			signatures = append(signatures, keys[voter].Sign(digest[:]))
+		}
+		extraData.AggregatedFinalityVotes = blst.AggregateSignatures(signatures)
+		extra, err := extraData.EncodeV2(chainConfig, header.Number)
+		if err != nil {
+			t.Fatal(err)
+		}
+		header.Extra = extra
+		enc, err := rlp.EncodeToBytes(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(hexutil.Encode(enc)), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		header string
+		valid  bool
+		voters int
+	}{
+		{header("finalized.rlp", 0, 1, 3), true, 3},
+		{header("unfinalized.rlp", 0, 2), false, 2},
+	}
+	for _, tt := range tests {
+		geth := runGeth(t, "finality", "verify", "--genesis", genesisPath, "--validators", validatorsPath, tt.header)
+		output := geth.Output()
+		geth.WaitExit()
+
+		var report finalityReport
+		if err := json.Unmarshal(output, &report); err != nil {
+			t.Fatalf("%s: invalid report: %v\n%s", tt.header, err, output)
+		}
+		if report.Valid != tt.valid || len(report.Voters) != tt.voters || len(report.Missing) != 4-tt.voters {
+			t.Errorf("%s: unexpected report, valid %t, voters %d, missing %d", tt.header, report.Valid, len(report.Voters), len(report.Missing))
+		}
+		if report.TargetNumber != 9 || report.Threshold != 3 || report.Weighted {
+			t.Errorf("%s: unexpected target %d, threshold %d, weighted %t", tt.header, report.TargetNumber, report.Threshold, report.Weighted)
+		}
+		if status := geth.ExitStatus(); (status == 0) != tt.valid {
+			t.Errorf("%s: unexpected exit status %d", tt.header, status)
+		}
+	}
+}
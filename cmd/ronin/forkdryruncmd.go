@@ -0,0 +1,540 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/consortium"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/forkid"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	dryrunOverrideFlag = &cli.StringFlag{
+		Name:     "override",
+		Usage:    "JSON file of the chain config fields to override, e.g. {\"venokiBlock\": 100}",
+		Required: true,
+	}
+	dryrunFromFlag = &cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First block of the range to replay",
+		Value: 1,
+	}
+	dryrunToFlag = &cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Last block of the range to replay (default = current head)",
+	}
+	dryrunReexecFlag = &cli.Uint64Flag{
+		Name:  "reexec",
+		Usage: "Maximum number of blocks to re-execute to regenerate the state of the first block",
+		Value: 128,
+	}
+	dryrunTraceFlag = &cli.StringFlag{
+		Name:  "trace",
+		Usage: "File to write the traces of the first diverging transaction to",
+		Value: "fork-dryrun-trace.json",
+	}
+
+	forkDryrunCommand = &cli.Command{
+		Action: forkDryrun,
+		Name:   "fork-dryrun",
+		Usage:  "Replay historical blocks with an overridden chain config",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.DBEngineFlag,
+			utils.AncientFlag,
+			utils.SyncModeFlag,
+			utils.GCModeFlag,
+			utils.StateSchemeFlag,
+			utils.CacheFlag,
+			utils.CacheDatabaseFlag,
+			dryrunOverrideFlag,
+			dryrunFromFlag,
+			dryrunToFlag,
+			dryrunReexecFlag,
+			dryrunTraceFlag,
+		},
+		Category: "MISCELLANEOUS COMMANDS",
+		Description: `
+The fork-dryrun command re-executes the given block range on top of the historical
+state with the stored chain config overridden by the fields of the --override file,
+e.g. to check that the blocks are still processed identically with a fork moved
+earlier. The receipts, the gas used and the state root of every replayed block are
+compared to the canonical chain, and the replay stops at the first divergence.
+
+If the divergence is caused by a transaction, it is traced with both the stored and
+the overridden chain config into the --trace file. The consortium snapshots from the
+first rescheduled fork on are recomputed with the overridden chain config. The
+replayed state and the recomputed snapshots are not persisted.`,
+	}
+)
+
+// dryrunDivergence is the first difference between the replayed chain and the
+// canonical one.
+type dryrunDivergence struct {
+	Number uint64
+	Hash   common.Hash
+	Reason string
+	Index  int // index of the diverging transaction, -1 if not caused by a transaction
+}
+
+// dryrunTrace is the output of the trace of the diverging transaction.
+type dryrunTrace struct {
+	Number    uint64          `json:"number"`
+	Hash      common.Hash     `json:"hash"`
+	Tx        common.Hash     `json:"tx"`
+	Index     int             `json:"index"`
+	Reason    string          `json:"reason"`
+	Canonical json.RawMessage `json:"canonical"`
+	Override  json.RawMessage `json:"override"`
+}
+
+func forkDryrun(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack)
+	defer db.Close()
+	defer chain.Stop()
+
+	config, err := loadDryrunConfig(chain.Config(), ctx.String(dryrunOverrideFlag.Name))
+	if err != nil {
+		return err
+	}
+	var (
+		from = ctx.Uint64(dryrunFromFlag.Name)
+		to   = chain.CurrentBlock().NumberU64()
+	)
+	if ctx.IsSet(dryrunToFlag.Name) {
+		if ctx.Uint64(dryrunToFlag.Name) > to {
+			return fmt.Errorf("block #%d is above the current head #%d", ctx.Uint64(dryrunToFlag.Name), to)
+		}
+		to = ctx.Uint64(dryrunToFlag.Name)
+	}
+	if from == 0 {
+		from = 1 // Genesis is not processed
+	}
+	if from > to {
+		return fmt.Errorf("invalid block range [%d, %d]", from, to)
+	}
+	// The consensus snapshots computed with the stored config can be reused up
+	// to the first rescheduled fork
+	rescheduled := from
+	for _, fork := range forkid.Conflicts(chain.Config(), config, to) {
+		log.Info("Replaying with rescheduled fork", "fork", fork.Name, "stored", fork.StoredBlock, "override", fork.NewBlock)
+		for _, block := range []*big.Int{fork.StoredBlock, fork.NewBlock} {
+			if block != nil && block.Uint64() < rescheduled {
+				rescheduled = block.Uint64()
+			}
+		}
+	}
+
+	// The overridden config is applied to a separate consensus engine, so that the
+	// engine specific rules follow the rescheduled forks as well.
+	backend, fixup := eth.MakeEthApiBackend(db)
+	engine := makeDryrunEngine(chain, newDryrunDatabase(db), config, rescheduled, to)
+	fixup(chain, chain.Engine())
+
+	parent := chain.GetBlockByNumber(from - 1)
+	if parent == nil {
+		return fmt.Errorf("block #%d not found", from-1)
+	}
+	statedb, release, err := backend.StateAtBlock(context.Background(), parent, ctx.Uint64(dryrunReexecFlag.Name), nil, false, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	var (
+		processor = core.NewStateProcessor(config, chain, engine)
+		triedb    = statedb.Database().TrieDB()
+		prevRoot  common.Hash
+		begin     = time.Now()
+		logged    = time.Now()
+		diverged  *dryrunDivergence
+	)
+	for number := from; number <= to; number++ {
+		block := chain.GetBlockByNumber(number)
+		if block == nil {
+			return fmt.Errorf("block #%d not found", number)
+		}
+		receipts, _, _, usedGas, err := processor.Process(block, statedb, vm.Config{})
+		if err != nil {
+			diverged = &dryrunDivergence{Number: number, Hash: block.Hash(), Reason: fmt.Sprintf("processing failed: %v", err), Index: -1}
+			break
+		}
+		if diverged = compareDryrunBlock(block, chain.GetReceiptsByHash(block.Hash()), receipts, usedGas); diverged != nil {
+			break
+		}
+		root, err := statedb.Commit(number, config.IsEIP158(block.Number()))
+		if err != nil {
+			return fmt.Errorf("state commit failed, number %d: %v", number, err)
+		}
+		if root != block.Root() {
+			diverged = &dryrunDivergence{Number: number, Hash: block.Hash(), Reason: fmt.Sprintf("state root mismatch: have %x, want %x", root, block.Root()), Index: -1}
+			break
+		}
+		if statedb, err = state.New(root, statedb.Database(), nil); err != nil {
+			return fmt.Errorf("state reset after block %d failed: %v", number, err)
+		}
+		// Hold the state reference and drop the previous one to prevent
+		// accumulating too many nodes in memory.
+		triedb.Reference(root, common.Hash{})
+		if prevRoot != (common.Hash{}) {
+			triedb.Dereference(prevRoot)
+		}
+		prevRoot = root
+
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Replaying blocks", "number", number, "to", to, "elapsed", common.PrettyDuration(time.Since(begin)))
+			logged = time.Now()
+		}
+	}
+	if prevRoot != (common.Hash{}) {
+		triedb.Dereference(prevRoot)
+	}
+	if diverged == nil {
+		fmt.Printf("No divergence in blocks #%d-#%d, replayed in %v\n", from, to, common.PrettyDuration(time.Since(begin)))
+		return nil
+	}
+	fmt.Printf("Divergence at block #%d (%x): %s\n", diverged.Number, diverged.Hash, diverged.Reason)
+	if diverged.Index < 0 {
+		return nil
+	}
+	block := chain.GetBlockByNumber(diverged.Number)
+	if posa, ok := engine.(consensus.PoSA); ok {
+		if isSystemTx, _ := posa.IsSystemTransaction(block.Transactions()[diverged.Index], block.Header()); isSystemTx {
+			fmt.Printf("Transaction %d is a system transaction, not traced\n", diverged.Index)
+			return nil
+		}
+	}
+	result := &dryrunTrace{
+		Number: diverged.Number,
+		Hash:   diverged.Hash,
+		Tx:     block.Transactions()[diverged.Index].Hash(),
+		Index:  diverged.Index,
+		Reason: diverged.Reason,
+	}
+	reexec := ctx.Uint64(dryrunReexecFlag.Name)
+	if result.Canonical, err = traceDryrunTx(backend, chain, chain.Config(), block, diverged.Index, reexec); err != nil {
+		return fmt.Errorf("failed to trace the canonical transaction: %v", err)
+	}
+	if result.Override, err = traceDryrunTx(backend, chain, config, block, diverged.Index, reexec); err != nil {
+		return fmt.Errorf("failed to trace the replayed transaction: %v", err)
+	}
+	out, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(ctx.String(dryrunTraceFlag.Name), out, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Traces of transaction %d (%x) written to %s\n", diverged.Index, result.Tx, ctx.String(dryrunTraceFlag.Name))
+	return nil
+}
+
+// loadDryrunConfig applies the chain config fields of the given JSON file on top
+// of the stored chain config.
+func loadDryrunConfig(stored *params.ChainConfig, path string) (*params.ChainConfig, error) {
+	override, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config override: %v", err)
+	}
+	blob, err := json.Marshal(stored)
+	if err != nil {
+		return nil, err
+	}
+	config := new(params.ChainConfig)
+	if err := json.Unmarshal(blob, config); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(override, config); err != nil {
+		return nil, fmt.Errorf("invalid config override: %v", err)
+	}
+	if err := config.CheckConfigForkOrder(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// makeDryrunEngine creates the consensus engine of the overridden chain config
+// on top of the dry run database. The consortium checkpoint snapshots in range
+// [rescheduled, to] are hidden, so that they are recomputed with the overridden
+// config from the last checkpoint before the first rescheduled fork.
+func makeDryrunEngine(chain *core.BlockChain, db *dryrunDatabase, config *params.ChainConfig, rescheduled, to uint64) consensus.Engine {
+	if config.Consortium == nil {
+		return chain.Engine()
+	}
+	if epoch := config.Consortium.EpochV2; epoch != 0 {
+		for number := (rescheduled + epoch - 1) / epoch * epoch; number <= to; number += epoch {
+			rawdb.DeleteSnapshotConsortium(db, rawdb.ReadCanonicalHash(db, number))
+		}
+	}
+	backend, fixup := eth.MakeEthApiBackend(db)
+	engine := consortium.New(config, db, ethapi.NewPublicBlockChainAPI(backend), true)
+	fixup(chain, engine)
+
+	engine.SetGetSCValidatorsFn(func() ([]common.Address, error) {
+		statedb, err := chain.State()
+		if err != nil {
+			return nil, err
+		}
+		return state.GetSCValidators(statedb), nil
+	})
+	engine.SetGetFenixValidators(func() ([]common.Address, error) {
+		statedb, err := chain.State()
+		if err != nil {
+			return nil, err
+		}
+		return state.GetFenixValidators(statedb, config.FenixValidatorContractAddress), nil
+	})
+	return engine
+}
+
+// dryrunDatabase layers the writes of the dry run consensus engine over the chain
+// database. The written and deleted keys are only kept in memory, the other keys
+// are read from the chain database.
+type dryrunDatabase struct {
+	ethdb.Database
+
+	lock    sync.RWMutex
+	written map[string][]byte // Written values, nil for the deleted keys
+}
+
+func newDryrunDatabase(db ethdb.Database) *dryrunDatabase {
+	return &dryrunDatabase{Database: db, written: make(map[string][]byte)}
+}
+
+// Has implements ethdb.KeyValueReader.
+func (db *dryrunDatabase) Has(key []byte) (bool, error) {
+	db.lock.RLock()
+	value, ok := db.written[string(key)]
+	db.lock.RUnlock()
+	if ok {
+		return value != nil, nil
+	}
+	return db.Database.Has(key)
+}
+
+// Get implements ethdb.KeyValueReader.
+func (db *dryrunDatabase) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	value, ok := db.written[string(key)]
+	db.lock.RUnlock()
+	if ok {
+		if value == nil {
+			return nil, errors.New("not found")
+		}
+		return common.CopyBytes(value), nil
+	}
+	return db.Database.Get(key)
+}
+
+// Put implements ethdb.KeyValueWriter.
+func (db *dryrunDatabase) Put(key []byte, value []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.written[string(key)] = append([]byte{}, value...)
+	return nil
+}
+
+// Delete implements ethdb.KeyValueWriter.
+func (db *dryrunDatabase) Delete(key []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.written[string(key)] = nil
+	return nil
+}
+
+// NewBatch implements ethdb.Batcher.
+func (db *dryrunDatabase) NewBatch() ethdb.Batch {
+	return &dryrunBatch{db: db}
+}
+
+// NewBatchWithSize implements ethdb.Batcher.
+func (db *dryrunDatabase) NewBatchWithSize(size int) ethdb.Batch {
+	return &dryrunBatch{db: db}
+}
+
+// dryrunBatch buffers the writes to the dry run database.
+type dryrunBatch struct {
+	db     *dryrunDatabase
+	writes []dryrunWrite
+	size   int
+}
+
+type dryrunWrite struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+func (b *dryrunBatch) Put(key, value []byte) error {
+	b.writes = append(b.writes, dryrunWrite{common.CopyBytes(key), common.CopyBytes(value), false})
+	b.size += len(key) + len(value)
+	return nil
+}
+
+func (b *dryrunBatch) Delete(key []byte) error {
+	b.writes = append(b.writes, dryrunWrite{common.CopyBytes(key), nil, true})
+	b.size += len(key)
+	return nil
+}
+
+func (b *dryrunBatch) ValueSize() int {
+	return b.size
+}
+
+func (b *dryrunBatch) Write() error {
+	return b.Replay(b.db)
+}
+
+func (b *dryrunBatch) Reset() {
+	b.writes = b.writes[:0]
+	b.size = 0
+}
+
+func (b *dryrunBatch) Replay(w ethdb.KeyValueWriter) error {
+	for _, write := range b.writes {
+		if write.delete {
+			if err := w.Delete(write.key); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := w.Put(write.key, write.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compareDryrunBlock compares the replayed receipts and gas used of the block to
+// the canonical ones.
+func compareDryrunBlock(block *types.Block, want, have types.Receipts, usedGas uint64) *dryrunDivergence {
+	diverged := func(index int, format string, args ...interface{}) *dryrunDivergence {
+		return &dryrunDivergence{Number: block.NumberU64(), Hash: block.Hash(), Reason: fmt.Sprintf(format, args...), Index: index}
+	}
+	for i := 0; i < len(have) && i < len(want); i++ {
+		if reason := diffReceipt(have[i], want[i]); reason != "" {
+			return diverged(i, "receipt %d (%x) mismatch: %s", i, want[i].TxHash, reason)
+		}
+	}
+	if len(have) != len(want) {
+		return diverged(-1, "receipt count mismatch: have %d, want %d", len(have), len(want))
+	}
+	if usedGas != block.GasUsed() {
+		return diverged(-1, "gas used mismatch: have %d, want %d", usedGas, block.GasUsed())
+	}
+	return nil
+}
+
+// diffReceipt returns the first consensus field differing between the replayed
+// receipt and the canonical one, or an empty string if they are identical.
+func diffReceipt(have, want *types.Receipt) string {
+	switch {
+	case have.TxHash != want.TxHash:
+		return fmt.Sprintf("transaction have %x, want %x", have.TxHash, want.TxHash)
+	case have.Status != want.Status:
+		return fmt.Sprintf("status have %d, want %d", have.Status, want.Status)
+	case !bytes.Equal(have.PostState, want.PostState):
+		return fmt.Sprintf("post state have %x, want %x", have.PostState, want.PostState)
+	case have.CumulativeGasUsed != want.CumulativeGasUsed:
+		return fmt.Sprintf("cumulative gas used have %d, want %d", have.CumulativeGasUsed, want.CumulativeGasUsed)
+	case len(have.Logs) != len(want.Logs):
+		return fmt.Sprintf("log count have %d, want %d", len(have.Logs), len(want.Logs))
+	}
+	for i := range have.Logs {
+		h, w := have.Logs[i], want.Logs[i]
+		if h.Address != w.Address || !bytes.Equal(h.Data, w.Data) || len(h.Topics) != len(w.Topics) {
+			return fmt.Sprintf("log %d mismatch", i)
+		}
+		for j := range h.Topics {
+			if h.Topics[j] != w.Topics[j] {
+				return fmt.Sprintf("log %d topic %d have %x, want %x", i, j, h.Topics[j], w.Topics[j])
+			}
+		}
+	}
+	return ""
+}
+
+// traceDryrunTx re-executes the block up to the given transaction on top of the
+// parent state with the given chain config, and returns the struct logs of the
+// transaction.
+func traceDryrunTx(backend *eth.EthAPIBackend, chain *core.BlockChain, config *params.ChainConfig, block *types.Block, index int, reexec uint64) (json.RawMessage, error) {
+	parent := chain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent %x not found", block.ParentHash())
+	}
+	statedb, release, err := backend.StateAtBlock(context.Background(), parent, reexec, nil, false, false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var (
+		header  = block.Header()
+		gp      = new(core.GasPool).AddGas(block.GasLimit())
+		usedGas uint64
+	)
+	for i, tx := range block.Transactions()[:index+1] {
+		var (
+			tracer *logger.StructLogger
+			cfg    vm.Config
+		)
+		if i == index {
+			tracer = logger.NewStructLogger(nil)
+			cfg.Tracer = tracer
+		}
+		statedb.SetTxContext(tx.Hash(), i)
+		if _, _, err := core.ApplyTransaction(config, chain, nil, gp, statedb, header, tx, &usedGas, cfg, core.NewReceiptBloomGenerator()); err != nil {
+			if tracer == nil {
+				return nil, fmt.Errorf("transaction %d failed: %v", i, err)
+			}
+			return json.Marshal(map[string]string{"error": err.Error()})
+		}
+		if tracer != nil {
+			return tracer.GetResult()
+		}
+	}
+	return nil, fmt.Errorf("transaction %d not found", index)
+}
@@ -0,0 +1,211 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestLoadDryrunConfig(t *testing.T) {
+	stored := &params.ChainConfig{
+		ChainID:        big.NewInt(2020),
+		HomesteadBlock: big.NewInt(0),
+		TrippBlock:     big.NewInt(100),
+		VenokiBlock:    big.NewInt(200),
+		Consortium:     &params.ConsortiumConfig{Period: 3, EpochV2: 200},
+	}
+	path := filepath.Join(t.TempDir(), "override.json")
+	if err := os.WriteFile(path, []byte(`{"venokiBlock": 150, "trippBlock": null}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := loadDryrunConfig(stored, path)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if config.VenokiBlock.Uint64() != 150 || config.TrippBlock != nil {
+		t.Errorf("fields not overridden: venoki %v, tripp %v", config.VenokiBlock, config.TrippBlock)
+	}
+	if config.ChainID.Uint64() != 2020 || config.Consortium.EpochV2 != 200 {
+		t.Error("stored fields not preserved")
+	}
+	if stored.VenokiBlock.Uint64() != 200 || stored.TrippBlock == nil {
+		t.Error("stored config modified")
+	}
+}
+
+func TestCompareDryrunBlock(t *testing.T) {
+	receipt := func(status, gas uint64, data byte) *types.Receipt {
+		return &types.Receipt{
+			Status:            status,
+			CumulativeGasUsed: gas,
+			TxHash:            common.Hash{data},
+			Logs:              []*types.Log{{Address: common.Address{1}, Topics: []common.Hash{{data}}, Data: []byte{data}}},
+		}
+	}
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(10), GasUsed: 42000})
+	canonical := types.Receipts{receipt(1, 21000, 1), receipt(1, 42000, 2)}
+
+	if diverged := compareDryrunBlock(block, canonical, types.Receipts{receipt(1, 21000, 1), receipt(1, 42000, 2)}, 42000); diverged != nil {
+		t.Fatalf("unexpected divergence: %s", diverged.Reason)
+	}
+	tests := []struct {
+		receipts types.Receipts
+		usedGas  uint64
+		index    int
+	}{
+		{types.Receipts{receipt(1, 21000, 1), receipt(0, 42000, 2)}, 42000, 1},
+		{types.Receipts{receipt(1, 22000, 1), receipt(1, 43000, 2)}, 43000, 0},
+		{types.Receipts{receipt(1, 21000, 1)}, 21000, -1},
+		{types.Receipts{receipt(1, 21000, 1), receipt(1, 42000, 2)}, 43000, -1},
+	}
+	for i, tt := range tests {
+		diverged := compareDryrunBlock(block, canonical, tt.receipts, tt.usedGas)
+		if diverged == nil {
+			t.Errorf("test %d: divergence not detected", i)
+			continue
+		}
+		if diverged.Index != tt.index {
+			t.Errorf("test %d: diverging transaction mismatch: have %d, want %d (%s)", i, diverged.Index, tt.index, diverged.Reason)
+		}
+	}
+	changed := receipt(1, 21000, 1)
+	changed.Logs[0].Topics[0] = common.Hash{9}
+	if reason := diffReceipt(changed, receipt(1, 21000, 1)); reason == "" {
+		t.Error("log topic mismatch not detected")
+	}
+}
+
+func TestForkDryrun(t *testing.T) {
+	var (
+		datadir  = t.TempDir()
+		key, _   = crypto.GenerateKey()
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.HexToAddress("0xc0de")
+		config   = &params.ChainConfig{
+			ChainID:             big.NewInt(1),
+			HomesteadBlock:      big.NewInt(0),
+			EIP150Block:         big.NewInt(0),
+			EIP155Block:         big.NewInt(0),
+			EIP158Block:         big.NewInt(0),
+			ByzantiumBlock:      big.NewInt(0),
+			ConstantinopleBlock: big.NewInt(0),
+			PetersburgBlock:     big.NewInt(0),
+			IstanbulBlock:       big.NewInt(0),
+			BerlinBlock:         big.NewInt(2),
+			Ethash:              new(params.EthashConfig),
+		}
+		gspec = &core.Genesis{
+			Config: config,
+			Alloc: core.GenesisAlloc{
+				sender: {Balance: big.NewInt(params.Ether)},
+				// SLOAD(0), which is more expensive after Berlin
+				contract: {Code: []byte{byte(vm.PUSH1), 0, byte(vm.SLOAD), byte(vm.POP), byte(vm.STOP)}, Balance: common.Big0},
+			},
+		}
+		signer = types.LatestSigner(config)
+	)
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 4, func(i int, block *core.BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(sender), contract, common.Big0, 100_000, big.NewInt(1), nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block.AddTx(tx)
+	})
+	chaindata := filepath.Join(datadir, clientIdentifier, "chaindata")
+	db, err := rawdb.Open(rawdb.OpenOptions{Type: "leveldb", Directory: chaindata, AncientsDirectory: filepath.Join(chaindata, "ancient")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := core.NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.InsertChain(blocks, nil); err != nil {
+		t.Fatal(err)
+	}
+	chain.Stop()
+	db.Close()
+
+	tests := []struct {
+		override string
+		output   string
+		traced   bool
+	}{
+		{`{"berlinBlock": 2}`, "No divergence in blocks #1-#4", false},
+		{`{"berlinBlock": 3}`, fmt.Sprintf("Divergence at block #2 (%x)", blocks[1].Hash()), true},
+	}
+	for i, tt := range tests {
+		override := filepath.Join(datadir, fmt.Sprintf("override%d.json", i))
+		if err := os.WriteFile(override, []byte(tt.override), 0644); err != nil {
+			t.Fatal(err)
+		}
+		trace := filepath.Join(datadir, fmt.Sprintf("trace%d.json", i))
+		geth := runGeth(t, "fork-dryrun", "--datadir", datadir, "--override", override, "--trace", trace)
+		output := string(geth.Output())
+		geth.WaitExit()
+		if status := geth.ExitStatus(); status != 0 {
+			t.Fatalf("test %d: exit status %d\n%s", i, status, output)
+		}
+		if !strings.Contains(output, tt.output) {
+			t.Errorf("test %d: output %q not found\n%s", i, tt.output, output)
+		}
+		if _, err := os.Stat(trace); (err == nil) != tt.traced {
+			t.Errorf("test %d: unexpected trace file state, err %v", i, err)
+		}
+	}
+}
+
+func TestDryrunDatabase(t *testing.T) {
+	var (
+		chaindb = rawdb.NewMemoryDatabase()
+		hash    = common.Hash{0x1}
+	)
+	rawdb.WriteSnapshotConsortium(chaindb, hash, []byte{0x1})
+	db := newDryrunDatabase(chaindb)
+
+	// The stored snapshot is hidden by the deletion, the recomputed one is only
+	// written in memory
+	rawdb.DeleteSnapshotConsortium(db, hash)
+	if _, err := rawdb.ReadSnapshotConsortium(db, hash); err == nil {
+		t.Fatal("deleted snapshot still readable")
+	}
+	batch := db.NewBatch()
+	rawdb.WriteSnapshotConsortium(batch, hash, []byte{0x2})
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+	if blob, err := rawdb.ReadSnapshotConsortium(db, hash); err != nil || blob[0] != 0x2 {
+		t.Fatalf("unexpected recomputed snapshot %x, err %v", blob, err)
+	}
+	if blob, err := rawdb.ReadSnapshotConsortium(chaindb, hash); err != nil || blob[0] != 0x1 {
+		t.Fatalf("chain database modified: %x, err %v", blob, err)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/forkid"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	forksJSONFlag = &cli.BoolFlag{
+		Name:  "json",
+		Usage: "Print the fork schedule and the conflicts in JSON",
+	}
+
+	forksCommand = &cli.Command{
+		Action:    showForks,
+		Name:      "forks",
+		Usage:     "Print the fork activation schedule of the chain",
+		ArgsUsage: "[<genesisPath>]",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.DBEngineFlag,
+			utils.AncientFlag,
+			forksJSONFlag,
+		},
+		Category: "MISCELLANEOUS COMMANDS",
+		Description: `
+The forks command prints every block based fork of the chain config stored in the
+database, its activation block, whether it is active at the current head and the
+fork id the chain yields from the fork on.
+
+If a genesis file is given, its chain config is inspected instead, as it would be
+written by 'ronin init --overrideChainConfig'. The forks rescheduled across the
+current head are reported, since the chain before the head was processed under the
+stored rules and the node would not be able to follow the network anymore.`,
+	}
+)
+
+// forksReport is the JSON output of the forks command.
+type forksReport struct {
+	Genesis   common.Hash       `json:"genesis"`
+	Head      hexutil.Uint64    `json:"head"`
+	Forks     []forkid.Fork     `json:"forks"`
+	Conflicts []forkid.Conflict `json:"conflicts,omitempty"`
+}
+
+func showForks(ctx *cli.Context) error {
+	if ctx.Args().Len() > 1 {
+		utils.Fatalf("This command accepts at most one argument.")
+	}
+	var genesis *core.Genesis
+	if ctx.Args().Len() == 1 {
+		file, err := os.Open(ctx.Args().First())
+		if err != nil {
+			utils.Fatalf("Failed to read genesis file: %v", err)
+		}
+		defer file.Close()
+
+		genesis = new(core.Genesis)
+		if err := json.NewDecoder(file).Decode(genesis); err != nil {
+			utils.Fatalf("Invalid genesis file: %v", err)
+		}
+		if genesis.Config == nil {
+			utils.Fatalf("Genesis file has no chain config")
+		}
+		if err := genesis.Config.CheckConfigForkOrder(); err != nil {
+			utils.Fatalf("Invalid fork order: %v", err)
+		}
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	var (
+		report    forksReport
+		config    *params.ChainConfig
+		storedcfg *params.ChainConfig
+	)
+	if _, err := os.Stat(stack.ResolvePath("chaindata")); err == nil {
+		db := utils.MakeChainDatabase(ctx, stack, true)
+		defer db.Close()
+
+		report.Genesis = rawdb.ReadCanonicalHash(db, 0)
+		if report.Genesis != (common.Hash{}) {
+			storedcfg = rawdb.ReadChainConfig(db, report.Genesis)
+			if number := rawdb.ReadHeaderNumber(db, rawdb.ReadHeadHeaderHash(db)); number != nil {
+				report.Head = hexutil.Uint64(*number)
+			}
+		}
+	}
+	switch {
+	case genesis != nil:
+		config = genesis.Config
+		if report.Genesis == (common.Hash{}) {
+			report.Genesis = genesis.ToBlock().Hash()
+		}
+	case storedcfg != nil:
+		config = storedcfg
+	default:
+		utils.Fatalf("No chain config found in the database, a genesis file is required")
+	}
+	report.Forks = forkid.Schedule(config, report.Genesis, uint64(report.Head))
+	if genesis != nil && storedcfg != nil {
+		report.Conflicts = forkid.Conflicts(storedcfg, genesis.Config, uint64(report.Head))
+	}
+
+	if ctx.Bool(forksJSONFlag.Name) {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			utils.Fatalf("Failed to encode fork schedule: %v", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	fmt.Printf("Genesis: %s\nHead:    %d\n\n", report.Genesis.Hex(), report.Head)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "FORK\tBLOCK\tACTIVE\tFORKID\tNEXT")
+	for _, fork := range report.Forks {
+		if fork.Block == nil {
+			fmt.Fprintf(w, "%s\t-\t-\t-\t-\n", fork.Name)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%t\t%s\t%d\n", fork.Name, fork.Block.ToInt(), fork.Active, *fork.ForkHash, *fork.ForkNext)
+	}
+	w.Flush()
+
+	for _, conflict := range report.Conflicts {
+		fmt.Printf("\nWARNING: %s fork is rescheduled across the head (stored %v, new %v, head %d)", conflict.Name, conflict.StoredBlock, conflict.NewBlock, report.Head)
+	}
+	if len(report.Conflicts) > 0 {
+		fmt.Println("\n\nThe override is incompatible with the chain, it must be rewound before the earliest conflicting fork.")
+	}
+	return nil
+}
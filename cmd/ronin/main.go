@@ -21,10 +21,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/grafana/pyroscope-go"
@@ -117,6 +119,7 @@ var (
 		utils.UltraLightFractionFlag,
 		utils.UltraLightOnlyAnnounceFlag,
 		utils.LightNoSyncServeFlag,
+		utils.LightConsortiumCheckpointFlag,
 		utils.WhitelistFlag,
 		utils.BloomFilterSizeFlag,
 		utils.CacheFlag,
@@ -175,12 +178,30 @@ var (
 		utils.CatalystFlag,
 		utils.MonitorDoubleSign,
 		utils.MonitorFinalityVoteFlag,
+		utils.MonitorValidatorUptimeFlag,
+		utils.MonitorUptimeValidatorsFlag,
+		utils.MonitorUptimeThresholdFlag,
+		utils.MonitorAlertSlackFlag,
+		utils.MonitorAlertWebhookFlag,
+		utils.MonitorAlertFileFlag,
+		utils.MonitorAlertPagerDutyFlag,
+		utils.MonitorAlertOpsgenieFlag,
+		utils.MonitorAlertRateLimitFlag,
+		utils.MonitorAlertDedupFlag,
 		utils.StoreInternalTransactions,
 		utils.MaxCurVoteAmountPerBlock,
+		utils.VoteJournalFlag,
+		utils.VoteRejournalFlag,
 		utils.EnableFastFinality,
 		utils.EnableFastFinalitySign,
 		utils.BlsPasswordPath,
 		utils.BlsWalletPath,
+		utils.BlsRemoteSignerFlag,
+		utils.BlsRemoteSignerPublicKeyFlag,
+		utils.BlsRemoteSignerTLSCertFlag,
+		utils.BlsRemoteSignerTLSKeyFlag,
+		utils.BlsRemoteSignerTLSCAFlag,
+		utils.BlsRemoteSignerTimeoutFlag,
 		utils.DisableRoninProtocol,
 		utils.AdditionalChainEventFlag,
 		utils.DBEngineFlag,
@@ -213,6 +234,13 @@ var (
 		utils.RPCGlobalEVMTimeoutFlag,
 		utils.RPCGlobalTxFeeCapFlag,
 		utils.AllowUnprotectedTxs,
+		utils.RPCJWTSecretFlag,
+		utils.RPCAccessRulesFlag,
+		utils.RPCBatchRequestLimitFlag,
+		utils.RPCBatchResponseMaxSizeFlag,
+		utils.RPCRateLimitFlag,
+		utils.RPCRateLimitBurstFlag,
+		utils.RPCMethodRateLimitsFlag,
 		utils.ReadinessEnabledFlag,
 		utils.ReadinessPrometheusEndpointFlag,
 		utils.ReadinessBlockLagFlag,
@@ -286,6 +314,14 @@ func init() {
 		utils.ShowDeprecated,
 		// See snapshot.go
 		snapshotCommand,
+		// See finalitycmd.go
+		finalityCommand,
+		// See devnetcmd.go
+		devnetCommand,
+		// See forkscmd.go
+		forksCommand,
+		// See forkdryruncmd.go
+		forkDryrunCommand,
 	}
 
 	sort.Sort(cli.CommandsByName(app.Commands))
@@ -505,6 +541,25 @@ func startNode(ctx *cli.Context, stack *node.Node, backend ethapi.Backend) {
 		}()
 	}
 
+	// Reload the BLS keys signing the finality votes on SIGHUP, so the keys
+	// rotated on-chain can be added without restarting the node
+	if ctx.Bool(utils.EnableFastFinalitySign.Name) {
+		if ethBackend, ok := backend.(*eth.EthAPIBackend); ok {
+			go func() {
+				sighup := make(chan os.Signal, 1)
+				signal.Notify(sighup, syscall.SIGHUP)
+				defer signal.Stop(sighup)
+
+				for range sighup {
+					log.Info("Got SIGHUP, reloading BLS keys")
+					if _, err := ethBackend.ReloadVoteKeys(); err != nil {
+						log.Error("Failed to reload BLS keys", "err", err)
+					}
+				}
+			}()
+		}
+	}
+
 	// Start auxiliary services if enabled
 	if ctx.Bool(utils.MiningEnabledFlag.Name) || ctx.Bool(utils.DeveloperFlag.Name) {
 		// Mining only makes sense if a full Ethereum node is running
@@ -20,11 +20,15 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/consortium"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/state/pruner"
@@ -182,6 +186,53 @@ The argument is interpreted as block number or hash. If none is provided, the la
 block is used.
 `,
 			},
+			{
+				Name:     "consortium",
+				Usage:    "Inspect and repair the consortium v2 snapshots",
+				Category: "MISCELLANEOUS COMMANDS",
+				Subcommands: []*cli.Command{
+					{
+						Name:      "dump",
+						Usage:     "Print the consortium v2 snapshot at a block",
+						ArgsUsage: "<blockHash> | <blockNum>",
+						Action:    dumpConsortiumSnapshot,
+						Flags: []cli.Flag{
+							utils.DataDirFlag,
+							utils.DBEngineFlag,
+							utils.AncientFlag,
+							utils.StateSchemeFlag,
+						},
+						Description: `
+This command prints the consortium v2 snapshot at the given block as JSON, it
+contains the validator set with BLS public keys, the block producers, the recent
+signers and the justified block. The snapshot is recomputed from the closest
+stored checkpoint when it is not persisted.
+`,
+					},
+					{
+						Name:   "rebuild",
+						Usage:  "Drop and recompute the consortium v2 snapshots from a checkpoint",
+						Action: rebuildConsortiumSnapshots,
+						Flags: []cli.Flag{
+							utils.DataDirFlag,
+							utils.DBEngineFlag,
+							utils.AncientFlag,
+							utils.StateSchemeFlag,
+							&cli.Uint64Flag{
+								Name:     "from",
+								Usage:    "Checkpoint block whose stored snapshot is used as the starting point",
+								Required: true,
+							},
+						},
+						Description: `
+This command drops the consortium v2 checkpoint snapshots of the canonical chain
+after the given checkpoint block, then recomputes them by applying the canonical
+headers on top of the snapshot stored at that block. It is used to recover from
+corrupted snapshot entries, the snapshot at the starting checkpoint must be valid.
+`,
+					},
+				},
+			},
 		},
 	}
 )
@@ -577,3 +628,78 @@ func dumpState(ctx *cli.Context) error {
 		"elapsed", common.PrettyDuration(time.Since(start)))
 	return nil
 }
+
+// consortiumEngine returns the consortium engine of the chain
+func consortiumEngine(chain *core.BlockChain) (*consortium.Consortium, error) {
+	engine, ok := chain.Engine().(*consortium.Consortium)
+	if !ok {
+		return nil, errors.New("the chain does not use consortium consensus")
+	}
+	return engine, nil
+}
+
+func dumpConsortiumSnapshot(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return errors.New("expect a block number or hash")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack)
+	defer db.Close()
+	defer chain.Stop()
+
+	engine, err := consortiumEngine(chain)
+	if err != nil {
+		return err
+	}
+	var (
+		arg    = ctx.Args().First()
+		header *types.Header
+	)
+	if hashish(arg) {
+		header = chain.GetHeaderByHash(common.HexToHash(arg))
+	} else {
+		number, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid block number %q: %v", arg, err)
+		}
+		header = chain.GetHeaderByNumber(number)
+	}
+	if header == nil {
+		return fmt.Errorf("block %s not found", arg)
+	}
+	snap, err := engine.GetSnapshot(chain, header.Number.Uint64(), header.Hash())
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+func rebuildConsortiumSnapshots(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack)
+	defer db.Close()
+	defer chain.Stop()
+
+	engine, err := consortiumEngine(chain)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	rebuilt, err := engine.RebuildSnapshots(chain, ctx.Uint64("from"))
+	if err != nil {
+		log.Error("Consortium snapshots rebuild failed", "rebuilt", rebuilt, "err", err)
+		return err
+	}
+	log.Info("Rebuilt consortium snapshots", "from", ctx.Uint64("from"), "rebuilt", rebuilt,
+		"elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
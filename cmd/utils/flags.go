@@ -70,12 +70,14 @@ import (
 	"github.com/ethereum/go-ethereum/metrics/exp"
 	"github.com/ethereum/go-ethereum/metrics/influxdb"
 	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/monitor"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/p2p/nat"
 	"github.com/ethereum/go-ethereum/p2p/netutil"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // These are all the command line flags we support.
@@ -274,6 +276,60 @@ var (
 		Usage:    "Enable finality vote monitoring",
 		Category: flags.EthCategory,
 	}
+	MonitorValidatorUptimeFlag = &cli.BoolFlag{
+		Name:     "monitor.uptime",
+		Usage:    "Enable validator missed block monitoring",
+		Category: flags.EthCategory,
+	}
+	MonitorUptimeValidatorsFlag = &cli.StringFlag{
+		Name:     "monitor.uptime.validators",
+		Usage:    "Comma separated validator addresses alerted when missing consecutive in-turn slots",
+		Category: flags.EthCategory,
+	}
+	MonitorUptimeThresholdFlag = &cli.Uint64Flag{
+		Name:     "monitor.uptime.threshold",
+		Usage:    "Number of consecutive missed in-turn slots before alerting",
+		Value:    ethconfig.Defaults.MonitorUptimeMissThreshold,
+		Category: flags.EthCategory,
+	}
+	MonitorAlertSlackFlag = &cli.StringFlag{
+		Name:     "monitor.alert.slack",
+		Usage:    "Slack incoming webhook URL receiving the monitor alerts",
+		EnvVars:  []string{"SLACK_WEBHOOK_URL"},
+		Category: flags.EthCategory,
+	}
+	MonitorAlertWebhookFlag = &cli.StringFlag{
+		Name:     "monitor.alert.webhook",
+		Usage:    "HTTP endpoint receiving the monitor alerts as JSON",
+		Category: flags.EthCategory,
+	}
+	MonitorAlertFileFlag = &cli.StringFlag{
+		Name:     "monitor.alert.file",
+		Usage:    "File the monitor alerts are appended to as JSON lines",
+		Category: flags.EthCategory,
+	}
+	MonitorAlertPagerDutyFlag = &cli.StringFlag{
+		Name:     "monitor.alert.pagerduty",
+		Usage:    "PagerDuty Events API v2 routing key receiving the monitor alerts",
+		Category: flags.EthCategory,
+	}
+	MonitorAlertOpsgenieFlag = &cli.StringFlag{
+		Name:     "monitor.alert.opsgenie",
+		Usage:    "Opsgenie API key receiving the monitor alerts",
+		Category: flags.EthCategory,
+	}
+	MonitorAlertRateLimitFlag = &cli.IntFlag{
+		Name:     "monitor.alert.ratelimit",
+		Usage:    "Maximum number of monitor alerts sent per minute (0 = unlimited)",
+		Value:    ethconfig.Defaults.MonitorAlert.RateLimit,
+		Category: flags.EthCategory,
+	}
+	MonitorAlertDedupFlag = &cli.DurationFlag{
+		Name:     "monitor.alert.dedup",
+		Usage:    "Time window in which monitor alerts with the same key are sent only once",
+		Value:    ethconfig.Defaults.MonitorAlert.DedupWindow,
+		Category: flags.EthCategory,
+	}
 	StoreInternalTransactions = &cli.BoolFlag{
 		Name:     "internaltxs",
 		Usage:    "Enable storing internal transactions to db",
@@ -351,6 +407,11 @@ var (
 		Usage:    "Enables serving light clients before syncing",
 		Category: flags.LightCategory,
 	}
+	LightConsortiumCheckpointFlag = &cli.StringFlag{
+		Name:     "light.consortium.checkpoint",
+		Usage:    "Trusted epoch checkpoint header (<number>:<hash>) to sync a consortium v2 light client from, walking the epoch checkpoints",
+		Category: flags.LightCategory,
+	}
 	// Ethash settings
 	EthashCacheDirFlag = &flags.DirectoryFlag{
 		Name:     "ethash.cachedir",
@@ -805,6 +866,43 @@ var (
 		Usage:    "Allow for unprotected (non EIP155 signed) transactions to be submitted via RPC",
 		Category: flags.APICategory,
 	}
+	RPCJWTSecretFlag = &cli.StringFlag{
+		Name:     "rpc.jwtsecret",
+		Usage:    "Path to a hex encoded secret authenticating the HTTP-RPC and WS-RPC requests with HS256 JSON web tokens (generated if missing)",
+		Category: flags.APICategory,
+	}
+	RPCBatchRequestLimitFlag = &cli.IntFlag{
+		Name:     "rpc.batch-request-limit",
+		Usage:    "Maximum number of requests in a batch (0 = unlimited)",
+		Value:    node.DefaultConfig.RPCLimits.BatchItems,
+		Category: flags.APICategory,
+	}
+	RPCBatchResponseMaxSizeFlag = &cli.IntFlag{
+		Name:     "rpc.batch-response-max-size",
+		Usage:    "Maximum number of bytes returned from a batched call (0 = unlimited)",
+		Value:    node.DefaultConfig.RPCLimits.BatchResponseSize,
+		Category: flags.APICategory,
+	}
+	RPCRateLimitFlag = &cli.Float64Flag{
+		Name:     "rpc.ratelimit",
+		Usage:    "Maximum calls per second of a client IP to the HTTP-RPC and WS-RPC servers (0 = unlimited)",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitBurstFlag = &cli.IntFlag{
+		Name:     "rpc.ratelimit.burst",
+		Usage:    "Maximum calls at once of a client IP to the HTTP-RPC and WS-RPC servers (0 = the rate limit)",
+		Category: flags.APICategory,
+	}
+	RPCMethodRateLimitsFlag = &cli.StringFlag{
+		Name:     "rpc.ratelimit.methods",
+		Usage:    "Per client IP rate limits of individual methods as method=rate[:burst], e.g. \"eth_getLogs=5:10,debug_traceCall=1\"",
+		Category: flags.APICategory,
+	}
+	RPCAccessRulesFlag = &cli.StringFlag{
+		Name:     "rpc.acl",
+		Usage:    "Namespaces and methods callable per token role, e.g. \"anonymous=eth,net;tracer=eth,debug_traceTransaction\" (requires --rpc.jwtsecret)",
+		Category: flags.APICategory,
+	}
 	ReadinessEnabledFlag = &cli.BoolFlag{
 		Name:  "readiness",
 		Usage: "Enable Readiness on the HTTP-RPC server. Note that Readiness can only be started if an HTTP server is started as well.",
@@ -1062,6 +1160,19 @@ var (
 		Category: flags.FastFinalityCategory,
 	}
 
+	VoteJournalFlag = &cli.StringFlag{
+		Name:     "votepool.journal",
+		Usage:    "Disk journal for finality votes to survive node restarts (empty = disabled)",
+		Category: flags.FastFinalityCategory,
+	}
+
+	VoteRejournalFlag = &cli.DurationFlag{
+		Name:     "votepool.rejournal",
+		Usage:    "Time interval to regenerate the finality vote journal",
+		Value:    time.Minute,
+		Category: flags.FastFinalityCategory,
+	}
+
 	EnableFastFinality = &cli.BoolFlag{
 		Name:     "finality.enable",
 		Usage:    "Enable fast finality vote",
@@ -1088,6 +1199,43 @@ var (
 		Category: flags.FastFinalityCategory,
 	}
 
+	BlsRemoteSignerFlag = &cli.StringFlag{
+		Name:     "finality.remotesigner",
+		Usage:    "URL of a Web3Signer compatible remote signer holding the BLS key, used instead of the local wallet",
+		Category: flags.FastFinalityCategory,
+	}
+
+	BlsRemoteSignerPublicKeyFlag = &cli.StringFlag{
+		Name:     "finality.remotesigner.pubkey",
+		Usage:    "BLS public key to sign with in the remote signer (default = first key of the signer)",
+		Category: flags.FastFinalityCategory,
+	}
+
+	BlsRemoteSignerTLSCertFlag = &cli.StringFlag{
+		Name:     "finality.remotesigner.tlscert",
+		Usage:    "The path to the TLS client certificate for the remote signer",
+		Category: flags.FastFinalityCategory,
+	}
+
+	BlsRemoteSignerTLSKeyFlag = &cli.StringFlag{
+		Name:     "finality.remotesigner.tlskey",
+		Usage:    "The path to the TLS client private key for the remote signer",
+		Category: flags.FastFinalityCategory,
+	}
+
+	BlsRemoteSignerTLSCAFlag = &cli.StringFlag{
+		Name:     "finality.remotesigner.tlsca",
+		Usage:    "The path to the CA certificate verifying the remote signer (default = system roots)",
+		Category: flags.FastFinalityCategory,
+	}
+
+	BlsRemoteSignerTimeoutFlag = &cli.DurationFlag{
+		Name:     "finality.remotesigner.timeout",
+		Usage:    "Timeout of a request to the remote signer",
+		Value:    5 * time.Second,
+		Category: flags.FastFinalityCategory,
+	}
+
 	DisableRoninProtocol = &cli.BoolFlag{
 		Name:     "ronin.disable",
 		Usage:    "Disable ronin p2p protocol",
@@ -1341,6 +1489,90 @@ func setWS(ctx *cli.Context, cfg *node.Config) {
 
 }
 
+// setRPCAuth configures the authentication and the access rules of the HTTP and
+// WebSocket RPC endpoints from the set command line flags.
+func setRPCAuth(ctx *cli.Context, cfg *node.Config) {
+	if ctx.IsSet(RPCJWTSecretFlag.Name) {
+		cfg.JWTSecret = ctx.String(RPCJWTSecretFlag.Name)
+	}
+	if ctx.IsSet(RPCAccessRulesFlag.Name) {
+		rules, err := parseRPCAccessRules(ctx.String(RPCAccessRulesFlag.Name))
+		if err != nil {
+			Fatalf("Invalid --%s: %v", RPCAccessRulesFlag.Name, err)
+		}
+		cfg.RPCAccessRules = rules
+	}
+}
+
+// parseRPCAccessRules parses semicolon separated role=namespace,method lists.
+func parseRPCAccessRules(input string) (map[string][]string, error) {
+	rules := make(map[string][]string)
+	for _, rule := range strings.Split(input, ";") {
+		if rule = strings.TrimSpace(rule); rule == "" {
+			continue
+		}
+		role, allowed, ok := strings.Cut(rule, "=")
+		if role = strings.TrimSpace(role); !ok || role == "" {
+			return nil, fmt.Errorf("malformed rule %q, want role=namespace,method", rule)
+		}
+		if _, exists := rules[role]; exists {
+			return nil, fmt.Errorf("duplicate rule for role %q", role)
+		}
+		rules[role] = SplitAndTrim(allowed)
+	}
+	return rules, nil
+}
+
+// setRPCLimits configures the batch size and rate limits of the HTTP and
+// WebSocket RPC endpoints from the set command line flags.
+func setRPCLimits(ctx *cli.Context, cfg *node.Config) {
+	if ctx.IsSet(RPCBatchRequestLimitFlag.Name) {
+		cfg.RPCLimits.BatchItems = ctx.Int(RPCBatchRequestLimitFlag.Name)
+	}
+	if ctx.IsSet(RPCBatchResponseMaxSizeFlag.Name) {
+		cfg.RPCLimits.BatchResponseSize = ctx.Int(RPCBatchResponseMaxSizeFlag.Name)
+	}
+	if ctx.IsSet(RPCRateLimitFlag.Name) {
+		cfg.RPCLimits.PerIP.Rate = ctx.Float64(RPCRateLimitFlag.Name)
+	}
+	if ctx.IsSet(RPCRateLimitBurstFlag.Name) {
+		cfg.RPCLimits.PerIP.Burst = ctx.Int(RPCRateLimitBurstFlag.Name)
+	}
+	if ctx.IsSet(RPCMethodRateLimitsFlag.Name) {
+		limits, err := parseRPCMethodRateLimits(ctx.String(RPCMethodRateLimitsFlag.Name))
+		if err != nil {
+			Fatalf("Invalid --%s: %v", RPCMethodRateLimitsFlag.Name, err)
+		}
+		cfg.RPCLimits.PerMethod = limits
+	}
+}
+
+// parseRPCMethodRateLimits parses comma separated method=rate[:burst] limits.
+func parseRPCMethodRateLimits(input string) (map[string]rpc.RateLimit, error) {
+	limits := make(map[string]rpc.RateLimit)
+	for _, entry := range SplitAndTrim(input) {
+		method, spec, ok := strings.Cut(entry, "=")
+		if method = strings.TrimSpace(method); !ok || method == "" {
+			return nil, fmt.Errorf("malformed limit %q, want method=rate[:burst]", entry)
+		}
+		var (
+			limit                 rpc.RateLimit
+			err                   error
+			rate, burst, hasBurst = strings.Cut(spec, ":")
+		)
+		if limit.Rate, err = strconv.ParseFloat(strings.TrimSpace(rate), 64); err != nil || limit.Rate <= 0 {
+			return nil, fmt.Errorf("invalid rate in %q", entry)
+		}
+		if hasBurst {
+			if limit.Burst, err = strconv.Atoi(strings.TrimSpace(burst)); err != nil || limit.Burst <= 0 {
+				return nil, fmt.Errorf("invalid burst in %q", entry)
+			}
+		}
+		limits[method] = limit
+	}
+	return limits, nil
+}
+
 // setIPC creates an IPC path configuration from the set command line flags,
 // returning an empty string if IPC was explicitly disabled, or the set path.
 func setIPC(ctx *cli.Context, cfg *node.Config) {
@@ -1386,6 +1618,22 @@ func setLes(ctx *cli.Context, cfg *ethconfig.Config) {
 	if ctx.IsSet(LightNoSyncServeFlag.Name) {
 		cfg.LightNoSyncServe = ctx.Bool(LightNoSyncServeFlag.Name)
 	}
+	if ctx.IsSet(LightConsortiumCheckpointFlag.Name) {
+		value := ctx.String(LightConsortiumCheckpointFlag.Name)
+		parts := strings.Split(value, ":")
+		if len(parts) != 2 {
+			Fatalf("Invalid consortium checkpoint %q, expected <number>:<hash>", value)
+		}
+		number, err := strconv.ParseUint(parts[0], 0, 64)
+		if err != nil {
+			Fatalf("Invalid consortium checkpoint number %q: %v", parts[0], err)
+		}
+		hash := common.HexToHash(parts[1])
+		if len(strings.TrimPrefix(parts[1], "0x")) != 2*common.HashLength {
+			Fatalf("Invalid consortium checkpoint hash %q", parts[1])
+		}
+		cfg.ConsortiumCheckpoint = &ethconfig.ConsortiumCheckpoint{Number: number, Hash: hash}
+	}
 }
 
 // MakeDatabaseHandles raises out the number of allowed file handles per process
@@ -1560,6 +1808,8 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	setHTTP(ctx, cfg)
 	setGraphQL(ctx, cfg)
 	setWS(ctx, cfg)
+	setRPCAuth(ctx, cfg)
+	setRPCLimits(ctx, cfg)
 	setNodeUserIdent(ctx, cfg)
 	setDataDir(ctx, cfg)
 	setSmartCard(ctx, cfg)
@@ -1602,10 +1852,18 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 
 func setFastFinality(ctx *cli.Context, cfg *node.Config) {
 	cfg.MaxCurVoteAmountPerBlock = ctx.Int(MaxCurVoteAmountPerBlock.Name)
+	cfg.VoteJournal = ctx.String(VoteJournalFlag.Name)
+	cfg.VoteRejournal = ctx.Duration(VoteRejournalFlag.Name)
 	cfg.EnableFastFinality = ctx.Bool(EnableFastFinality.Name)
 	cfg.EnableFastFinalitySign = ctx.Bool(EnableFastFinalitySign.Name)
 	cfg.BlsPasswordPath = ctx.String(BlsPasswordPath.Name)
 	cfg.BlsWalletPath = ctx.String(BlsWalletPath.Name)
+	cfg.BlsRemoteSignerURL = ctx.String(BlsRemoteSignerFlag.Name)
+	cfg.BlsRemoteSignerPublicKey = ctx.String(BlsRemoteSignerPublicKeyFlag.Name)
+	cfg.BlsRemoteSignerTLSCert = ctx.String(BlsRemoteSignerTLSCertFlag.Name)
+	cfg.BlsRemoteSignerTLSKey = ctx.String(BlsRemoteSignerTLSKeyFlag.Name)
+	cfg.BlsRemoteSignerTLSCA = ctx.String(BlsRemoteSignerTLSCAFlag.Name)
+	cfg.BlsRemoteSignerTimeout = ctx.Duration(BlsRemoteSignerTimeoutFlag.Name)
 }
 
 func setSmartCard(ctx *cli.Context, cfg *node.Config) {
@@ -1730,6 +1988,48 @@ func setBlobPool(ctx *cli.Context, cfg *blobpool.Config) {
 	}
 }
 
+func setMonitorUptime(ctx *cli.Context, cfg *ethconfig.Config) {
+	if ctx.Bool(MonitorValidatorUptimeFlag.Name) {
+		cfg.EnableMonitorValidatorUptime = true
+	}
+	if ctx.IsSet(MonitorUptimeValidatorsFlag.Name) {
+		cfg.MonitorUptimeValidators = nil
+		for _, account := range SplitAndTrim(ctx.String(MonitorUptimeValidatorsFlag.Name)) {
+			if !common.IsHexAddress(account) {
+				Fatalf("Invalid validator address in --%s: %s", MonitorUptimeValidatorsFlag.Name, account)
+			}
+			cfg.MonitorUptimeValidators = append(cfg.MonitorUptimeValidators, common.HexToAddress(account))
+		}
+	}
+	if ctx.IsSet(MonitorUptimeThresholdFlag.Name) {
+		cfg.MonitorUptimeMissThreshold = ctx.Uint64(MonitorUptimeThresholdFlag.Name)
+	}
+}
+
+func setMonitorAlert(ctx *cli.Context, cfg *monitor.AlertConfig) {
+	if ctx.IsSet(MonitorAlertSlackFlag.Name) {
+		cfg.SlackURL = ctx.String(MonitorAlertSlackFlag.Name)
+	}
+	if ctx.IsSet(MonitorAlertWebhookFlag.Name) {
+		cfg.WebhookURL = ctx.String(MonitorAlertWebhookFlag.Name)
+	}
+	if ctx.IsSet(MonitorAlertFileFlag.Name) {
+		cfg.FilePath = ctx.String(MonitorAlertFileFlag.Name)
+	}
+	if ctx.IsSet(MonitorAlertPagerDutyFlag.Name) {
+		cfg.PagerDutyRoutingKey = ctx.String(MonitorAlertPagerDutyFlag.Name)
+	}
+	if ctx.IsSet(MonitorAlertOpsgenieFlag.Name) {
+		cfg.OpsgenieAPIKey = ctx.String(MonitorAlertOpsgenieFlag.Name)
+	}
+	if ctx.IsSet(MonitorAlertRateLimitFlag.Name) {
+		cfg.RateLimit = ctx.Int(MonitorAlertRateLimitFlag.Name)
+	}
+	if ctx.IsSet(MonitorAlertDedupFlag.Name) {
+		cfg.DedupWindow = ctx.Duration(MonitorAlertDedupFlag.Name)
+	}
+}
+
 func setEthash(ctx *cli.Context, cfg *ethconfig.Config) {
 	if ctx.IsSet(EthashCacheDirFlag.Name) {
 		cfg.Ethash.CacheDir = ctx.String(EthashCacheDirFlag.Name)
@@ -2113,6 +2413,8 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.Bool(MonitorFinalityVoteFlag.Name) {
 		cfg.EnableMonitorFinalityVote = true
 	}
+	setMonitorUptime(ctx, cfg)
+	setMonitorAlert(ctx, &cfg.MonitorAlert)
 	// Set any dangling config values
 	if ctx.String(CryptoKZGFlag.Name) != "gokzg" && ctx.String(CryptoKZGFlag.Name) != "ckzg" {
 		Fatalf("--%s flag must be 'gokzg' or 'ckzg'", CryptoKZGFlag.Name)
@@ -2378,8 +2680,9 @@ func MakeChain(ctx *cli.Context, stack *node.Node) (chain *core.BlockChain, chai
 	if err != nil {
 		Fatalf("Can't create BlockChain: %v", err)
 	}
-	fixupEth(chain, engine)
 	if config.Consortium != nil {
+		fixupEth(chain, engine)
+
 		c := engine.(*consortium.Consortium)
 		c.SetGetSCValidatorsFn(func() ([]common.Address, error) {
 			stateDb, err := chain.State()
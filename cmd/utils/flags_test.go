@@ -20,6 +20,8 @@ package utils
 import (
 	"reflect"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 func Test_SplitTagsFlag(t *testing.T) {
@@ -62,3 +64,41 @@ func Test_SplitTagsFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRPCAccessRules(t *testing.T) {
+	rules, err := parseRPCAccessRules(" anonymous=eth, net ; tracer=eth,debug_traceTransaction;")
+	if err != nil {
+		t.Fatalf("failed to parse rules: %v", err)
+	}
+	want := map[string][]string{
+		"anonymous": {"eth", "net"},
+		"tracer":    {"eth", "debug_traceTransaction"},
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("rules mismatch: have %v, want %v", rules, want)
+	}
+	for _, input := range []string{"eth,net", "=eth", "a=eth;a=net"} {
+		if _, err := parseRPCAccessRules(input); err == nil {
+			t.Errorf("invalid rules %q accepted", input)
+		}
+	}
+}
+
+func TestParseRPCMethodRateLimits(t *testing.T) {
+	limits, err := parseRPCMethodRateLimits("eth_getLogs=5:10, debug_traceCall=0.5")
+	if err != nil {
+		t.Fatalf("failed to parse limits: %v", err)
+	}
+	want := map[string]rpc.RateLimit{
+		"eth_getLogs":     {Rate: 5, Burst: 10},
+		"debug_traceCall": {Rate: 0.5},
+	}
+	if !reflect.DeepEqual(limits, want) {
+		t.Errorf("limits mismatch: have %v, want %v", limits, want)
+	}
+	for _, input := range []string{"eth_getLogs", "=5", "eth_getLogs=0", "eth_getLogs=5:x", "eth_getLogs=5:0"} {
+		if _, err := parseRPCMethodRateLimits(input); err == nil {
+			t.Errorf("invalid limits %q accepted", input)
+		}
+	}
+}
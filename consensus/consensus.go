@@ -171,6 +171,10 @@ type FastFinalityPoSA interface {
 
 	// This is used by finality monitor
 	GetFinalityVoterAt(chain ChainHeaderReader, blockNumber uint64, blockHash common.Hash) []finality.ValidatorWithBlsPub
+
+	// GetInturnValidatorAt returns the in-turn validator of the block following the given block,
+	// this is used by validator uptime monitor
+	GetInturnValidatorAt(chain ChainHeaderReader, blockNumber uint64, blockHash common.Hash) (common.Address, error)
 }
 
 type VotePool interface {
@@ -3,13 +3,14 @@ package common
 import (
 	"errors"
 
+	"github.com/ethereum/go-ethereum/consensus/consortium/v2/finality"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
 const (
-	ExtraSeal                        = crypto.SignatureLength
-	ExtraVanity                      = 32
-	MaxFinalityVotePercentage uint16 = 10_000
+	ExtraSeal                 = crypto.SignatureLength
+	ExtraVanity               = 32
+	MaxFinalityVotePercentage = finality.MaxFinalityVotePercentage
 
 	// The gas limit of system transaction after Venoki
 	systemTransactionGasLimit = 50_000_000
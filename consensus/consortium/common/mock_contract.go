@@ -35,7 +35,7 @@ func SetMockValidators(validators, publicKeys, stakeAmounts string) error {
 	Validators = &MockValidators{
 		validators:    make([]common.Address, len(vals)),
 		blsPublicKeys: make(map[common.Address]blsCommon.PublicKey),
-		stakeAmounts:  make([]*big.Int, len(amounts)),
+		stakeAmounts:  make([]*big.Int, len(vals)),
 	}
 	for i, val := range vals {
 		Validators.validators[i] = common.HexToAddress(val)
@@ -48,9 +48,9 @@ func SetMockValidators(validators, publicKeys, stakeAmounts string) error {
 			Validators.stakeAmounts[i], ok = new(big.Int).SetString(amounts[i], 10)
 			if !ok {
 				return errors.New("failed to parse stake amount")
-			} else {
-				Validators.stakeAmounts[i] = common.Big0
 			}
+		} else {
+			Validators.stakeAmounts[i] = common.Big0
 		}
 	}
 	return nil
@@ -107,5 +107,5 @@ func (contract *MockContract) GetStakedAmount(blockHash common.Hash, blockNumber
 }
 
 func (contract *MockContract) GetMaxValidatorNumber(blockHash common.Hash, blockNumber *big.Int) (*big.Int, error) {
-	return nil, nil
+	return big.NewInt(int64(len(Validators.validators))), nil
 }
@@ -1,6 +1,7 @@
 package consortium
 
 import (
+	"errors"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -268,6 +269,40 @@ func (c *Consortium) GetFinalityVoterAt(
 	return nil
 }
 
+// GetInturnValidatorAt returns an error before ConsortiumV2
+// See the comment for GetInturnValidatorAt in v2 package
+// for more information
+func (c *Consortium) GetInturnValidatorAt(
+	chain consensus.ChainHeaderReader,
+	blockNumber uint64,
+	blockHash common.Hash,
+) (common.Address, error) {
+	if c.chainConfig.IsConsortiumV2(new(big.Int).SetUint64(blockNumber + 1)) {
+		return c.v2.GetInturnValidatorAt(chain, blockNumber, blockHash)
+	}
+
+	return common.Address{}, errors.New("in-turn validator is not available before consortium v2")
+}
+
+// GetSnapshot returns the consortium v2 snapshot at the given block
+func (c *Consortium) GetSnapshot(chain consensus.ChainHeaderReader, number uint64, hash common.Hash) (*v2.Snapshot, error) {
+	if !c.chainConfig.IsConsortiumV2(new(big.Int).SetUint64(number + 1)) {
+		return nil, errors.New("consortium v2 snapshot is not available before consortium v2")
+	}
+
+	return c.v2.GetSnapshot(chain, number, hash)
+}
+
+// RebuildSnapshots drops and recomputes the consortium v2 checkpoint snapshots
+// after block from
+func (c *Consortium) RebuildSnapshots(chain consensus.ChainHeaderReader, from uint64) (int, error) {
+	if !c.chainConfig.IsConsortiumV2(new(big.Int).SetUint64(from + 1)) {
+		return 0, errors.New("consortium v2 snapshot is not available before consortium v2")
+	}
+
+	return c.v2.RebuildSnapshots(chain, from)
+}
+
 // HandleSystemTransaction fixes up the statedb when system transaction
 // goes through ApplyMessage when tracing/debugging
 func HandleSystemTransaction(engine consensus.Engine, statedb *state.StateDB, msg core.Message, block *types.Block) bool {
@@ -1,12 +1,19 @@
 package v2
 
 import (
+	"errors"
+
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
 	consortiumCommon "github.com/ethereum/go-ethereum/consensus/consortium/common"
 	"github.com/ethereum/go-ethereum/consensus/consortium/v2/finality"
 )
 
+// maxFinalityParticipationRange is the maximum number of blocks that can be
+// queried in one GetFinalityParticipation call
+const maxFinalityParticipationRange = 100_000
+
 type consortiumV2Api struct {
 	chain      consensus.ChainHeaderReader
 	consortium *Consortium
@@ -77,3 +84,61 @@ func (api *consortiumV2Api) GetFinalityVoteAtHash(hash common.Hash) (*finalityVo
 
 	return &vote, nil
 }
+
+type validatorParticipation struct {
+	Address       common.Address  `json:"address"`
+	Eligible      hexutil.Uint64  `json:"eligible"`
+	Voted         hexutil.Uint64  `json:"voted"`
+	FirstMissed   *hexutil.Uint64 `json:"firstMissed"`
+	LastMissed    *hexutil.Uint64 `json:"lastMissed"`
+	Participation float64         `json:"participation"`
+}
+
+type finalityParticipation struct {
+	From       hexutil.Uint64            `json:"from"`
+	To         hexutil.Uint64            `json:"to"`
+	Validators []*validatorParticipation `json:"validators"`
+}
+
+// GetFinalityParticipation returns, per validator, the number of blocks it is
+// eligible to vote and has voted for, its first and last missed vote and its
+// participation percentage in the canonical block range [from, to]. The vote for
+// a block is carried in its child header, so the block numbers are the numbers
+// of the headers carrying the votes.
+func (api *consortiumV2Api) GetFinalityParticipation(from hexutil.Uint64, to hexutil.Uint64) (*finalityParticipation, error) {
+	if from > to {
+		return nil, errors.New("invalid block range")
+	}
+	if uint64(to-from) >= maxFinalityParticipationRange {
+		return nil, errors.New("block range is too large")
+	}
+	if uint64(to) > api.chain.CurrentHeader().Number.Uint64() {
+		return nil, consortiumCommon.ErrUnknownBlock
+	}
+
+	participation, err := api.consortium.finalityParticipation(api.chain, uint64(from), uint64(to))
+	if err != nil {
+		return nil, err
+	}
+	result := &finalityParticipation{
+		From:       from,
+		To:         to,
+		Validators: make([]*validatorParticipation, 0, len(participation.stats)),
+	}
+	for _, stat := range participation.sorted() {
+		validator := &validatorParticipation{
+			Address:  stat.Address,
+			Eligible: hexutil.Uint64(stat.Eligible),
+			Voted:    hexutil.Uint64(stat.Voted),
+		}
+		if stat.FirstMissed != 0 {
+			firstMissed, lastMissed := hexutil.Uint64(stat.FirstMissed), hexutil.Uint64(stat.LastMissed)
+			validator.FirstMissed, validator.LastMissed = &firstMissed, &lastMissed
+		}
+		if stat.Eligible != 0 {
+			validator.Participation = float64(stat.Voted) * 100 / float64(stat.Eligible)
+		}
+		result.Validators = append(result.Validators, validator)
+	}
+	return result, nil
+}
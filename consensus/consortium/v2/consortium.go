@@ -7,7 +7,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"math"
 	"math/big"
 	"math/rand"
 	"sort"
@@ -26,6 +25,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/misc"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -50,10 +50,9 @@ const (
 	wiggleTime          = 1000 * time.Millisecond // Random delay (per signer) to allow concurrent signers
 	unSealableValidator = -1
 
-	finalityRatio                  float64 = 2.0 / 3
-	assemblingFinalityVoteDuration         = 1 * time.Second
-	MaxValidatorCandidates                 = 64 // Maximum number of validator candidates.
-	dayInSeconds                           = uint64(86400)
+	assemblingFinalityVoteDuration = 1 * time.Second
+	MaxValidatorCandidates         = 64 // Maximum number of validator candidates.
+	dayInSeconds                   = uint64(86400)
 )
 
 // Consortium delegated proof-of-stake protocol constants.
@@ -369,42 +368,14 @@ func (c *Consortium) verifyFinalitySignatures(
 		TargetNumber: header.Number.Uint64() - 1,
 		TargetHash:   header.ParentHash,
 	}
-	digest := voteData.Hash()
-
-	// verify aggregated signature
-	var (
-		publicKeys            []blsCommon.PublicKey
-		accumulatedVoteWeight int
-		finalityThreshold     int
+	_, err = finality.VerifyFinalityVotes(
+		snap.ValidatorsWithBlsPub,
+		isTrippEffective,
+		finalityVotedValidators,
+		finalitySignatures,
+		&voteData,
 	)
-	votedValidatorPositions := finalityVotedValidators.Indices()
-	for _, position := range votedValidatorPositions {
-		if position >= len(snap.ValidatorsWithBlsPub) {
-			return finality.ErrInvalidFinalityVotedBitSet
-		}
-		publicKeys = append(publicKeys, snap.ValidatorsWithBlsPub[position].BlsPublicKey)
-		if isTrippEffective {
-			accumulatedVoteWeight += int(snap.ValidatorsWithBlsPub[position].Weight)
-		} else {
-			accumulatedVoteWeight += 1
-		}
-	}
-
-	if isTrippEffective {
-		finalityThreshold = int(math.Floor(finalityRatio*float64(consortiumCommon.MaxFinalityVotePercentage))) + 1
-	} else {
-		finalityThreshold = int(math.Floor(finalityRatio*float64(len(snap.ValidatorsWithBlsPub)))) + 1
-	}
-
-	if accumulatedVoteWeight < finalityThreshold {
-		return finality.ErrNotEnoughFinalityVote
-	}
-
-	if !finalitySignatures.FastAggregateVerify(publicKeys, digest) {
-		return finality.ErrFinalitySignatureVerificationFailed
-	}
-
-	return nil
+	return err
 }
 
 // VerifyHeaderAndParents checks whether a header conforms to the consensus rules.The
@@ -444,7 +415,34 @@ func (c *Consortium) verifyValidatorFieldsInExtraData(
 	header *types.Header,
 	parents []*types.Header,
 ) error {
-	isEpoch := header.Number.Uint64()%c.config.EpochV2 == 0 || c.chainConfig.IsOnConsortiumV2(header.Number)
+	var (
+		isTrippEffective = c.IsTrippEffective(chain, header, parents)
+		isPeriodBlock    bool
+	)
+	if isTrippEffective {
+		var err error
+		isPeriodBlock, err = c.IsPeriodBlock(chain, header, parents)
+		if err != nil {
+			log.Error("Failed to check IsPeriodBlock", "blocknum", header.Number, "err", err)
+			return err
+		}
+	}
+	return VerifyValidatorFields(c.chainConfig, c.config.EpochV2, extraData, header, isTrippEffective, isPeriodBlock)
+}
+
+// VerifyValidatorFields checks the validator fields in the header extra data are
+// set as expected at the header, given whether Tripp is effective and whether the
+// header is a period block. It does not depend on the chain, so the light clients
+// can check the headers without the snapshots.
+func VerifyValidatorFields(
+	chainConfig *params.ChainConfig,
+	epoch uint64,
+	extraData *finality.HeaderExtraData,
+	header *types.Header,
+	isTrippEffective bool,
+	isPeriodBlock bool,
+) error {
+	isEpoch := header.Number.Uint64()%epoch == 0 || chainConfig.IsOnConsortiumV2(header.Number)
 	if !isEpoch {
 		if len(extraData.CheckpointValidators) != 0 || len(extraData.BlockProducers) != 0 || extraData.BlockProducersBitSet != 0 {
 			return fmt.Errorf(
@@ -457,8 +455,8 @@ func (c *Consortium) verifyValidatorFieldsInExtraData(
 		}
 	}
 
-	if c.IsTrippEffective(chain, header, parents) {
-		if c.chainConfig.IsAaron(header.Number) {
+	if isTrippEffective {
+		if chainConfig.IsAaron(header.Number) {
 			if isEpoch && (extraData.BlockProducersBitSet == 0 || len(extraData.BlockProducers) != 0) {
 				return fmt.Errorf(
 					"%w: block producer: %v, block producer bitset: %v",
@@ -475,11 +473,6 @@ func (c *Consortium) verifyValidatorFieldsInExtraData(
 				extraData.BlockProducersBitSet,
 			)
 		}
-		isPeriodBlock, err := c.IsPeriodBlock(chain, header, parents)
-		if err != nil {
-			log.Error("Failed to check IsPeriodBlock", "blocknum", header.Number, "err", err)
-			return err
-		}
 		if isPeriodBlock {
 			if len(extraData.CheckpointValidators) == 0 {
 				return fmt.Errorf(
@@ -1668,11 +1661,7 @@ func (c *Consortium) assembleFinalityVote(chain consensus.ChainHeaderReader, hea
 		)
 
 		isTrippEffective := c.IsTrippEffective(chain, header, nil)
-		if isTrippEffective {
-			finalityThreshold = int(math.Floor(finalityRatio*float64(consortiumCommon.MaxFinalityVotePercentage))) + 1
-		} else {
-			finalityThreshold = int(math.Floor(finalityRatio*float64(len(snap.ValidatorsWithBlsPub)))) + 1
-		}
+		finalityThreshold = finality.Threshold(snap.ValidatorsWithBlsPub, isTrippEffective)
 
 		// We assume the signature has been verified in vote pool
 		// so we do not verify signature here
@@ -1839,6 +1828,94 @@ func (c *Consortium) GetFinalityVoterAt(
 	return snap.ValidatorsWithBlsPub
 }
 
+// GetInturnValidatorAt gets the in-turn validator of block number + 1
+// based on the snapshot at block number
+func (c *Consortium) GetInturnValidatorAt(
+	chain consensus.ChainHeaderReader,
+	blockNumber uint64,
+	blockHash common.Hash,
+) (common.Address, error) {
+	snap, err := c.snapshot(chain, blockNumber, blockHash, nil)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(snap.validators()) == 0 {
+		return common.Address{}, errors.New("empty validator set")
+	}
+
+	return snap.supposeValidator(), nil
+}
+
+// GetSnapshot returns the snapshot at the given block, this is used by the
+// snapshot inspection command
+func (c *Consortium) GetSnapshot(chain consensus.ChainHeaderReader, number uint64, hash common.Hash) (*Snapshot, error) {
+	return c.snapshot(chain, number, hash, nil)
+}
+
+// RebuildSnapshots drops the checkpoint snapshots of the canonical chain after
+// the checkpoint block from, then recomputes them by applying the canonical
+// headers on top of the snapshot stored at block from. The snapshots are dropped
+// first so that an interrupted rebuild never leaves a corrupted snapshot behind,
+// the missing ones are recomputed on demand. It returns the number of rebuilt
+// snapshots.
+func (c *Consortium) RebuildSnapshots(chain consensus.ChainHeaderReader, from uint64) (int, error) {
+	if from%c.config.EpochV2 != 0 && from != c.forkedBlock-1 {
+		return 0, fmt.Errorf("block %d is not a checkpoint block", from)
+	}
+	header := chain.GetHeaderByNumber(from)
+	if header == nil {
+		return 0, consortiumCommon.ErrUnknownBlock
+	}
+	snap, err := loadSnapshot(c.config, c.signatures, c.db, header.Hash(), c.ethAPI, c.chainConfig)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load snapshot at block %d: %w", from, err)
+	}
+	if snap.Number != from || snap.Hash != header.Hash() {
+		return 0, fmt.Errorf("snapshot at block %d is corrupted, number %d hash %s", from, snap.Number, snap.Hash)
+	}
+
+	head := chain.CurrentHeader().Number.Uint64()
+	firstCheckpoint := (from/c.config.EpochV2 + 1) * c.config.EpochV2
+	for number := firstCheckpoint; number <= head; number += c.config.EpochV2 {
+		checkpoint := chain.GetHeaderByNumber(number)
+		if checkpoint == nil {
+			return 0, consortiumCommon.ErrUnknownBlock
+		}
+		if err := rawdb.DeleteSnapshotConsortium(c.db, checkpoint.Hash()); err != nil {
+			return 0, err
+		}
+	}
+	c.recents.Purge()
+
+	var (
+		rebuilt int
+		logged  = time.Now()
+	)
+	for number := firstCheckpoint; number <= head; number += c.config.EpochV2 {
+		headers := make([]*types.Header, 0, number-snap.Number)
+		for i := snap.Number + 1; i <= number; i++ {
+			header := chain.GetHeaderByNumber(i)
+			if header == nil {
+				return rebuilt, consortiumCommon.ErrUnknownBlock
+			}
+			headers = append(headers, header)
+		}
+		snap, err = snap.apply(headers, chain, nil, c.chainConfig.ChainID)
+		if err != nil {
+			return rebuilt, fmt.Errorf("failed to apply headers up to block %d: %w", number, err)
+		}
+		if err := snap.store(c.db); err != nil {
+			return rebuilt, err
+		}
+		rebuilt++
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Rebuilding consortium snapshots", "number", number, "head", head, "rebuilt", rebuilt)
+			logged = time.Now()
+		}
+	}
+	return rebuilt, nil
+}
+
 // ecrecover extracts the Ronin account address from a signed header.
 func ecrecover(header *types.Header, sigcache *arc.ARCCache[common.Hash, common.Address], chainId *big.Int) (common.Address, error) {
 	// If the signature's already cached, return that
@@ -3267,3 +3267,297 @@ func TestVerifyValidatorExtraDataWithContract(t *testing.T) {
 		}
 	}
 }
+
+func TestFinalityParticipationMergeAndCache(t *testing.T) {
+	var (
+		validator1 = common.Address{0x1}
+		validator2 = common.Address{0x2}
+	)
+	epoch1 := newParticipation()
+	*epoch1.stat(validator1) = participationStat{Address: validator1, Eligible: 10, Voted: 10}
+	*epoch1.stat(validator2) = participationStat{Address: validator2, Eligible: 10, Voted: 8, FirstMissed: 3, LastMissed: 7}
+
+	epoch2 := newParticipation()
+	*epoch2.stat(validator1) = participationStat{Address: validator1, Eligible: 10, Voted: 9, FirstMissed: 15, LastMissed: 15}
+	*epoch2.stat(validator2) = participationStat{Address: validator2, Eligible: 10, Voted: 10}
+
+	c := Consortium{db: rawdb.NewMemoryDatabase()}
+	hash := common.Hash{0x1}
+	if c.readFinalityParticipation(hash) != nil {
+		t.Fatal("Expect no cached participation")
+	}
+	c.writeFinalityParticipation(hash, epoch2)
+	cached := c.readFinalityParticipation(hash)
+	if cached == nil {
+		t.Fatal("Expect cached participation")
+	}
+
+	result := newParticipation()
+	result.merge(epoch1)
+	result.merge(cached)
+	expected := []participationStat{
+		{Address: validator1, Eligible: 20, Voted: 19, FirstMissed: 15, LastMissed: 15},
+		{Address: validator2, Eligible: 20, Voted: 18, FirstMissed: 3, LastMissed: 7},
+	}
+	stats := result.sorted()
+	if len(stats) != len(expected) {
+		t.Fatalf("Expect %d stats, got %d", len(expected), len(stats))
+	}
+	for i, stat := range stats {
+		if *stat != expected[i] {
+			t.Fatalf("Mismatch stat %d, expect %+v, got %+v", i, expected[i], *stat)
+		}
+	}
+}
+
+// testHeaderReader serves the headers of a single chain without a database
+type testHeaderReader struct {
+	consensus.ChainHeaderReader
+	config  *params.ChainConfig
+	headers []*types.Header
+}
+
+func (r *testHeaderReader) Config() *params.ChainConfig { return r.config }
+
+func (r *testHeaderReader) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header := r.GetHeaderByNumber(number); header != nil && header.Hash() == hash {
+		return header
+	}
+	return nil
+}
+
+func (r *testHeaderReader) GetHeaderByNumber(number uint64) *types.Header {
+	if number >= uint64(len(r.headers)) {
+		return nil
+	}
+	return r.headers[number]
+}
+
+func TestComputeFinalityParticipation(t *testing.T) {
+	chainConfig := params.ChainConfig{
+		ChainID:           big.NewInt(2021),
+		ConsortiumV2Block: common.Big0,
+		ShillinBlock:      common.Big0,
+		Consortium: &params.ConsortiumConfig{
+			EpochV2: 10,
+		},
+	}
+	var (
+		ecdsaKeys  []*ecdsa.PrivateKey
+		blsKeys    []blsCommon.SecretKey
+		validators []finality.ValidatorWithBlsPub
+	)
+	for i := 0; i < 3; i++ {
+		secretKey, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		blsKey, err := blst.RandKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ecdsaKeys = append(ecdsaKeys, secretKey)
+		blsKeys = append(blsKeys, blsKey)
+		validators = append(validators, finality.ValidatorWithBlsPub{
+			Address:      crypto.PubkeyToAddress(secretKey.PublicKey),
+			BlsPublicKey: blsKey.PublicKey(),
+		})
+	}
+
+	// The checkpoint block 10 drops the third validator, which takes effect
+	// after block 11. The third validator misses the vote in block 5, the
+	// second one misses the votes in blocks 3 and 13.
+	missed := map[uint64]int{3: 1, 5: 2, 13: 1}
+	headers := []*types.Header{{Number: common.Big0, Difficulty: common.Big1}}
+	for number := uint64(1); number <= 15; number++ {
+		signer, voters := ecdsaKeys[number%3], 3
+		if number > 11 {
+			signer, voters = ecdsaKeys[number%2], 2
+		}
+		var (
+			extraData  = finality.HeaderExtraData{HasFinalityVote: 1}
+			voteData   = types.VoteData{TargetNumber: number - 1, TargetHash: headers[number-1].Hash()}
+			signatures []blsCommon.Signature
+		)
+		for i := 0; i < voters; i++ {
+			if index, ok := missed[number]; !ok || index != i {
+				extraData.FinalityVotedValidators.SetBit(i)
+				signatures = append(signatures, blsKeys[i].Sign(voteData.Hash().Bytes()))
+			}
+		}
+		extraData.AggregatedFinalityVotes = blst.AggregateSignatures(signatures)
+		if number == 10 {
+			extraData.CheckpointValidators = validators[:2]
+		}
+		header := &types.Header{
+			ParentHash: headers[number-1].Hash(),
+			Number:     new(big.Int).SetUint64(number),
+			Difficulty: big.NewInt(7),
+			Extra:      extraData.Encode(true),
+		}
+		hash := calculateSealHash(header, chainConfig.ChainID)
+		sig, err := crypto.Sign(hash[:], signer)
+		if err != nil {
+			t.Fatalf("Failed to sign block, err %s", err)
+		}
+		copy(header.Extra[len(header.Extra)-consortiumCommon.ExtraSeal:], sig)
+		headers = append(headers, header)
+	}
+
+	recents, _ := arc.NewARC[common.Hash, *Snapshot](inmemorySnapshots)
+	signatures, _ := arc.NewARC[common.Hash, common.Address](inmemorySignatures)
+	c := Consortium{
+		chainConfig: &chainConfig,
+		recents:     recents,
+		signatures:  signatures,
+		config:      chainConfig.Consortium,
+	}
+	// Only the snapshot at the parent of the range is known
+	recents.Add(headers[1].Hash(), newSnapshot(&chainConfig, c.config, signatures, 1, headers[1].Hash(), nil, validators, nil))
+
+	chain := &testHeaderReader{config: &chainConfig, headers: headers}
+	result, err := c.computeFinalityParticipation(chain, 2, headers[len(headers)-1])
+	if err != nil {
+		t.Fatalf("Failed to compute finality participation, err %s", err)
+	}
+	expected := map[common.Address]participationStat{
+		validators[0].Address: {Address: validators[0].Address, Eligible: 14, Voted: 14},
+		validators[1].Address: {Address: validators[1].Address, Eligible: 14, Voted: 12, FirstMissed: 3, LastMissed: 13},
+		validators[2].Address: {Address: validators[2].Address, Eligible: 10, Voted: 9, FirstMissed: 5, LastMissed: 5},
+	}
+	if len(result.stats) != len(expected) {
+		t.Fatalf("Expect %d stats, got %d", len(expected), len(result.stats))
+	}
+	for address, stat := range result.stats {
+		if *stat != expected[address] {
+			t.Fatalf("Mismatch stat of %x, expect %+v, got %+v", address, expected[address], *stat)
+		}
+	}
+}
+
+func TestRebuildSnapshots(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	secretKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator := crypto.PubkeyToAddress(secretKey.PublicKey)
+
+	chainConfig := params.ChainConfig{
+		ChainID:           big.NewInt(2021),
+		HomesteadBlock:    common.Big0,
+		EIP150Block:       common.Big0,
+		EIP155Block:       common.Big0,
+		EIP158Block:       common.Big0,
+		ConsortiumV2Block: common.Big0,
+		Consortium: &params.ConsortiumConfig{
+			EpochV2: 10,
+		},
+	}
+	gspec := &core.Genesis{
+		Config: &chainConfig,
+	}
+	genesis := gspec.MustCommit(db, trie.NewDatabase(db, nil))
+
+	mock := &mockContract{
+		validators: map[common.Address]mockValidator{validator: {}},
+	}
+	recents, _ := arc.NewARC[common.Hash, *Snapshot](inmemorySnapshots)
+	signatures, _ := arc.NewARC[common.Hash, common.Address](inmemorySignatures)
+	v2 := Consortium{
+		chainConfig: &chainConfig,
+		contract:    mock,
+		recents:     recents,
+		signatures:  signatures,
+		config:      chainConfig.Consortium,
+		db:          db,
+	}
+	chain, _ := core.NewBlockChain(db, nil, gspec, nil, &v2, vm.Config{}, nil, nil)
+	defer chain.Stop()
+
+	blocks, _ := core.GenerateConsortiumChain(
+		&chainConfig,
+		genesis,
+		&v2,
+		db,
+		25,
+		func(i int, bg *core.BlockGen) {
+			extra := make([]byte, consortiumCommon.ExtraVanity)
+			if (i+1)%10 == 0 {
+				extra = append(extra, validator.Bytes()...)
+			}
+			bg.SetCoinbase(validator)
+			bg.SetExtra(append(extra, make([]byte, consortiumCommon.ExtraSeal)...))
+			bg.SetDifficulty(big.NewInt(7))
+		},
+		true,
+		func(i int, bg *core.BlockGen) {
+			header := bg.Header()
+			hash := calculateSealHash(header, chainConfig.ChainID)
+			sig, err := crypto.Sign(hash[:], secretKey)
+			if err != nil {
+				t.Fatalf("Failed to sign block, err %s", err)
+			}
+			copy(header.Extra[len(header.Extra)-consortiumCommon.ExtraSeal:], sig)
+			bg.SetExtra(header.Extra)
+		},
+	)
+	if _, err := chain.InsertChain(blocks, nil); err != nil {
+		t.Fatalf("Failed to insert block, err %s", err)
+	}
+
+	// Record the checkpoint snapshots, then corrupt the one at block 20 and drop
+	// the one at block 10
+	checkpoints := []*types.Header{chain.GetHeaderByNumber(10), chain.GetHeaderByNumber(20)}
+	var expected [][]byte
+	for _, checkpoint := range checkpoints {
+		if _, err := v2.GetSnapshot(chain, checkpoint.Number.Uint64(), checkpoint.Hash()); err != nil {
+			t.Fatalf("Failed to get snapshot at block %d, err %s", checkpoint.Number, err)
+		}
+		blob, err := rawdb.ReadSnapshotConsortium(db, checkpoint.Hash())
+		if err != nil {
+			t.Fatalf("Missing snapshot at block %d, err %s", checkpoint.Number, err)
+		}
+		expected = append(expected, blob)
+	}
+	corrupted, err := loadSnapshot(v2.config, v2.signatures, db, checkpoints[1].Hash(), nil, &chainConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupted.Recents = map[uint64]common.Address{}
+	corrupted.Validators = nil
+	if err := corrupted.store(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := rawdb.DeleteSnapshotConsortium(db, checkpoints[0].Hash()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only checkpoint blocks can be rebuilt from
+	if _, err := v2.RebuildSnapshots(chain, 5); err == nil {
+		t.Fatal("Expect rebuild from a non checkpoint block to fail")
+	}
+	rebuilt, err := v2.RebuildSnapshots(chain, 0)
+	if err != nil {
+		t.Fatalf("Failed to rebuild snapshots, err %s", err)
+	}
+	if rebuilt != 2 {
+		t.Fatalf("Expect 2 rebuilt snapshots, got %d", rebuilt)
+	}
+	for i, checkpoint := range checkpoints {
+		blob, err := rawdb.ReadSnapshotConsortium(db, checkpoint.Hash())
+		if err != nil {
+			t.Fatalf("Missing rebuilt snapshot at block %d, err %s", checkpoint.Number, err)
+		}
+		if !bytes.Equal(blob, expected[i]) {
+			t.Fatalf("Rebuilt snapshot at block %d mismatch\nhave %s\nwant %s", checkpoint.Number, blob, expected[i])
+		}
+	}
+	snap, err := v2.GetSnapshot(chain, 20, checkpoints[1].Hash())
+	if err != nil {
+		t.Fatalf("Failed to get rebuilt snapshot, err %s", err)
+	}
+	if len(snap.validators()) != 1 || snap.validators()[0] != validator {
+		t.Fatalf("Unexpected validators in rebuilt snapshot %v", snap.validators())
+	}
+}
@@ -0,0 +1,126 @@
+package finality
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	blsCommon "github.com/ethereum/go-ethereum/crypto/bls/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// finalityRatio is the ratio of the vote weight above which a block is finalized
+	finalityRatio float64 = 2.0 / 3
+
+	// MaxFinalityVotePercentage is the total vote weight of the validator set
+	// once Tripp is effective
+	MaxFinalityVotePercentage uint16 = 10_000
+)
+
+// ErrMissingFinalityVote is returned if the header extra data does not carry
+// the finality votes
+var ErrMissingFinalityVote = errors.New("header has no finality vote")
+
+// FinalityProof is the result of the verification of the finality votes for a
+// block, which are carried in the extra data of its child header.
+type FinalityProof struct {
+	TargetNumber uint64                // Number of the block voted for finality
+	TargetHash   common.Hash           // Hash of the block voted for finality
+	Voters       []ValidatorWithBlsPub // Validators whose votes are aggregated
+	Missing      []ValidatorWithBlsPub // Validators whose votes are not aggregated
+	VoteWeight   int                   // Accumulated weight of the voters
+	Threshold    int                   // Minimum vote weight to finalize the block
+}
+
+// Threshold returns the minimum vote weight to finalize a block. Once Tripp is
+// effective the votes are weighted by the validator weights, whose total is
+// fixed, otherwise each validator counts for one.
+func Threshold(validators []ValidatorWithBlsPub, weighted bool) int {
+	if weighted {
+		return int(math.Floor(finalityRatio*float64(MaxFinalityVotePercentage))) + 1
+	}
+	return int(math.Floor(finalityRatio*float64(len(validators)))) + 1
+}
+
+// VerifyFinalityVotes verifies the aggregated finality signature of the voted
+// validators against the vote data, and checks the vote weight is above the
+// finality threshold. The validators are the set at the target block, in the
+// order of the voted bit set.
+func VerifyFinalityVotes(
+	validators []ValidatorWithBlsPub,
+	weighted bool,
+	votedValidators BitSet,
+	signature blsCommon.Signature,
+	voteData *types.VoteData,
+) (*FinalityProof, error) {
+	proof := &FinalityProof{
+		TargetNumber: voteData.TargetNumber,
+		TargetHash:   voteData.TargetHash,
+		Threshold:    Threshold(validators, weighted),
+	}
+	var publicKeys []blsCommon.PublicKey
+	for _, position := range votedValidators.Indices() {
+		if position >= len(validators) {
+			return nil, ErrInvalidFinalityVotedBitSet
+		}
+		validator := validators[position]
+		if validator.BlsPublicKey == nil {
+			return nil, ErrUnauthorizedFinalityVoter
+		}
+		publicKeys = append(publicKeys, validator.BlsPublicKey)
+		proof.Voters = append(proof.Voters, validator)
+		if weighted {
+			proof.VoteWeight += int(validator.Weight)
+		} else {
+			proof.VoteWeight += 1
+		}
+	}
+	for position, validator := range validators {
+		if votedValidators.GetBit(position) == 0 {
+			proof.Missing = append(proof.Missing, validator)
+		}
+	}
+
+	if proof.VoteWeight < proof.Threshold {
+		return proof, ErrNotEnoughFinalityVote
+	}
+	if signature == nil || !signature.FastAggregateVerify(publicKeys, voteData.Hash()) {
+		return proof, ErrFinalitySignatureVerificationFailed
+	}
+	return proof, nil
+}
+
+// VerifyHeaderFinality decodes the extra data of the header and verifies the
+// finality votes for its parent block. The validators are the set at the parent
+// block. The decoded extra data is returned even if the verification fails.
+func VerifyHeaderFinality(
+	chainConfig *params.ChainConfig,
+	header *types.Header,
+	validators []ValidatorWithBlsPub,
+	weighted bool,
+) (*HeaderExtraData, *FinalityProof, error) {
+	extraData, err := DecodeExtraV2(header.Extra, chainConfig, header.Number)
+	if err != nil {
+		return nil, nil, err
+	}
+	if extraData.HasFinalityVote == 0 {
+		return extraData, nil, ErrMissingFinalityVote
+	}
+	if header.Number.Sign() == 0 {
+		return extraData, nil, ErrInvalidTargetNumber
+	}
+	voteData := &types.VoteData{
+		TargetNumber: header.Number.Uint64() - 1,
+		TargetHash:   header.ParentHash,
+	}
+	proof, err := VerifyFinalityVotes(
+		validators,
+		weighted,
+		extraData.FinalityVotedValidators,
+		extraData.AggregatedFinalityVotes,
+		voteData,
+	)
+	return extraData, proof, err
+}
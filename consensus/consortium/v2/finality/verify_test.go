@@ -0,0 +1,100 @@
+package finality
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/bls/blst"
+	blsCommon "github.com/ethereum/go-ethereum/crypto/bls/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestVerifyHeaderFinality(t *testing.T) {
+	const numValidator = 4
+
+	chainConfig := &params.ChainConfig{
+		ShillinBlock: big.NewInt(0),
+		TrippBlock:   big.NewInt(0),
+	}
+	parentHash := common.Hash{0x1}
+	voteData := types.VoteData{TargetNumber: 9, TargetHash: parentHash}
+	digest := voteData.Hash()
+
+	validators := make([]ValidatorWithBlsPub, numValidator)
+	signatures := make([]blsCommon.Signature, numValidator)
+	for i := 0; i < numValidator; i++ {
+		secretKey, err := blst.RandKey()
+		if err != nil {
+			t.Fatalf("Failed to generate secret key, err %s", err)
+		}
+		validators[i] = ValidatorWithBlsPub{
+			Address:      common.BigToAddress(big.NewInt(int64(i))),
+			BlsPublicKey: secretKey.PublicKey(),
+			Weight:       2500,
+		}
+		signatures[i] = secretKey.Sign(digest[:])
+	}
+
+	newHeader := func(voted []int, signed []int) *types.Header {
+		extraData := &HeaderExtraData{}
+		if len(voted) != 0 {
+			extraData.HasFinalityVote = 1
+			var toAggregate []blsCommon.Signature
+			for _, i := range voted {
+				extraData.FinalityVotedValidators.SetBit(i)
+			}
+			for _, i := range signed {
+				toAggregate = append(toAggregate, signatures[i])
+			}
+			extraData.AggregatedFinalityVotes = blst.AggregateSignatures(toAggregate)
+		}
+		extra, err := extraData.EncodeV2(chainConfig, big.NewInt(10))
+		if err != nil {
+			t.Fatalf("Failed to encode extra data, err %s", err)
+		}
+		return &types.Header{Number: big.NewInt(10), ParentHash: parentHash, Extra: extra}
+	}
+
+	// The votes of 3 validators out of 4 finalize the parent block
+	_, proof, err := VerifyHeaderFinality(chainConfig, newHeader([]int{0, 1, 3}, []int{0, 1, 3}), validators, true)
+	if err != nil {
+		t.Fatalf("Expect successful verification have %v", err)
+	}
+	if proof.TargetNumber != 9 || proof.TargetHash != parentHash {
+		t.Fatalf("Unexpected target %d %s", proof.TargetNumber, proof.TargetHash)
+	}
+	if proof.VoteWeight != 7500 || proof.Threshold != 6667 {
+		t.Fatalf("Unexpected vote weight %d, threshold %d", proof.VoteWeight, proof.Threshold)
+	}
+	if len(proof.Voters) != 3 || proof.Voters[2].Address != validators[3].Address {
+		t.Fatalf("Unexpected voters %v", proof.Voters)
+	}
+	if len(proof.Missing) != 1 || proof.Missing[0].Address != validators[2].Address {
+		t.Fatalf("Unexpected missing voters %v", proof.Missing)
+	}
+
+	// The same votes without weights are counted per validator
+	if _, _, err := VerifyHeaderFinality(chainConfig, newHeader([]int{0, 1, 3}, []int{0, 1, 3}), validators, false); err != nil {
+		t.Fatalf("Expect successful verification have %v", err)
+	}
+
+	tests := []struct {
+		voted, signed []int
+		validators    []ValidatorWithBlsPub
+		err           error
+	}{
+		{nil, nil, validators, ErrMissingFinalityVote},
+		{[]int{0, 1}, []int{0, 1}, validators, ErrNotEnoughFinalityVote},
+		{[]int{0, 1, 2}, []int{0, 1, 3}, validators, ErrFinalitySignatureVerificationFailed},
+		{[]int{0, 1, 2}, []int{0, 1, 2}, validators[:2], ErrInvalidFinalityVotedBitSet},
+	}
+	for i, test := range tests {
+		_, _, err := VerifyHeaderFinality(chainConfig, newHeader(test.voted, test.signed), test.validators, true)
+		if !errors.Is(err, test.err) {
+			t.Errorf("test %d: expect error %v have %v", i, test.err, err)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package v2
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	consortiumCommon "github.com/ethereum/go-ethereum/consensus/consortium/common"
+	"github.com/ethereum/go-ethereum/consensus/consortium/v2/finality"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var errChainReorged = errors.New("chain reorged while computing finality participation")
+
+// participationStat is the finality vote participation of a validator over a
+// block range. The finality vote for block N - 1 is carried in block N header,
+// so the block numbers here are the numbers of the headers carrying the votes.
+// FirstMissed and LastMissed are 0 when the validator does not miss any vote,
+// as the genesis block never carries finality votes.
+type participationStat struct {
+	Address     common.Address
+	Eligible    uint64
+	Voted       uint64
+	FirstMissed uint64
+	LastMissed  uint64
+}
+
+// participation accumulates the participationStat of validators over
+// consecutive block ranges.
+type participation struct {
+	stats map[common.Address]*participationStat
+}
+
+func newParticipation() *participation {
+	return &participation{stats: make(map[common.Address]*participationStat)}
+}
+
+func (p *participation) stat(address common.Address) *participationStat {
+	stat, ok := p.stats[address]
+	if !ok {
+		stat = &participationStat{Address: address}
+		p.stats[address] = stat
+	}
+	return stat
+}
+
+// merge appends the stats of the next block range to p
+func (p *participation) merge(next *participation) {
+	for address, nextStat := range next.stats {
+		stat := p.stat(address)
+		stat.Eligible += nextStat.Eligible
+		stat.Voted += nextStat.Voted
+		if stat.FirstMissed == 0 {
+			stat.FirstMissed = nextStat.FirstMissed
+		}
+		if nextStat.LastMissed != 0 {
+			stat.LastMissed = nextStat.LastMissed
+		}
+	}
+}
+
+// sorted returns the stats sorted by validator address
+func (p *participation) sorted() []*participationStat {
+	stats := make([]*participationStat, 0, len(p.stats))
+	for _, stat := range p.stats {
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Address.Cmp(stats[j].Address) < 0
+	})
+	return stats
+}
+
+// finalityParticipation returns the finality vote participation of validators
+// in the canonical block range [from, to]. The participation of every full
+// epoch in the range is cached on disk, keyed by the hash of the epoch's last
+// block, so that reorged epochs are never served from the cache.
+func (c *Consortium) finalityParticipation(chain consensus.ChainHeaderReader, from, to uint64) (*participation, error) {
+	result := newParticipation()
+	for start := from; start <= to; {
+		epoch := start / c.config.EpochV2
+		end := (epoch+1)*c.config.EpochV2 - 1
+		if end > to {
+			end = to
+		}
+		fullEpoch := start == epoch*c.config.EpochV2 && end == (epoch+1)*c.config.EpochV2-1
+
+		endHeader := chain.GetHeaderByNumber(end)
+		if endHeader == nil {
+			return nil, consortiumCommon.ErrUnknownBlock
+		}
+		if fullEpoch {
+			if cached := c.readFinalityParticipation(endHeader.Hash()); cached != nil {
+				result.merge(cached)
+				start = end + 1
+				continue
+			}
+		}
+
+		segment, err := c.computeFinalityParticipation(chain, start, endHeader)
+		if err != nil {
+			return nil, err
+		}
+		if fullEpoch {
+			c.writeFinalityParticipation(endHeader.Hash(), segment)
+		}
+		result.merge(segment)
+		start = end + 1
+	}
+	return result, nil
+}
+
+// computeFinalityParticipation decodes the finality vote bit set of the
+// canonical headers from block number start to endHeader.
+func (c *Consortium) computeFinalityParticipation(
+	chain consensus.ChainHeaderReader,
+	start uint64,
+	endHeader *types.Header,
+) (*participation, error) {
+	var (
+		end     = endHeader.Number.Uint64()
+		result  = newParticipation()
+		headers = make([]*types.Header, end-start+1)
+	)
+	// Collect the headers backward by parent hash so all of them belong to
+	// the same chain.
+	headers[len(headers)-1] = endHeader
+	for i := len(headers) - 2; i >= 0; i-- {
+		child := headers[i+1]
+		headers[i] = chain.GetHeader(child.ParentHash, child.Number.Uint64()-1)
+		if headers[i] == nil {
+			return nil, errChainReorged
+		}
+	}
+
+	// Skip the headers before Shillin as they never carry finality votes
+	for len(headers) > 0 && (headers[0].Number.Sign() == 0 || !c.chainConfig.IsShillin(headers[0].Number)) {
+		headers = headers[1:]
+	}
+	if len(headers) == 0 {
+		return result, nil
+	}
+	// Take the snapshot once at the parent of the first header and walk it
+	// forward, the snapshot before each header is the one its finality vote
+	// bit set is decoded against.
+	first := headers[0]
+	snap, err := c.snapshot(chain, first.Number.Uint64()-1, first.ParentHash, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i, header := range headers {
+		if i > 0 {
+			snap, err = snap.apply(headers[i-1:i], chain, headers[:i-1], c.chainConfig.ChainID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		number := header.Number.Uint64()
+		// The finality vote is not enabled yet
+		if len(snap.ValidatorsWithBlsPub) == 0 {
+			continue
+		}
+		extraData, err := finality.DecodeExtraV2(header.Extra, c.chainConfig, header.Number)
+		if err != nil {
+			return nil, err
+		}
+
+		voted := make(map[common.Address]struct{})
+		if extraData.HasFinalityVote == 1 {
+			for _, voter := range decodeValidatorBitSet(extraData.FinalityVotedValidators, snap.ValidatorsWithBlsPub) {
+				voted[voter] = struct{}{}
+			}
+		}
+		for _, validator := range snap.ValidatorsWithBlsPub {
+			stat := result.stat(validator.Address)
+			stat.Eligible++
+			if _, ok := voted[validator.Address]; ok {
+				stat.Voted++
+				continue
+			}
+			if stat.FirstMissed == 0 {
+				stat.FirstMissed = number
+			}
+			stat.LastMissed = number
+		}
+	}
+	return result, nil
+}
+
+func (c *Consortium) readFinalityParticipation(hash common.Hash) *participation {
+	if c.db == nil {
+		return nil
+	}
+	data := rawdb.ReadFinalityParticipation(c.db, hash)
+	if len(data) == 0 {
+		return nil
+	}
+	var stats []*participationStat
+	if err := rlp.DecodeBytes(data, &stats); err != nil {
+		log.Warn("Failed to decode cached finality participation", "hash", hash, "err", err)
+		return nil
+	}
+	result := newParticipation()
+	for _, stat := range stats {
+		result.stats[stat.Address] = stat
+	}
+	return result
+}
+
+func (c *Consortium) writeFinalityParticipation(hash common.Hash, p *participation) {
+	if c.db == nil {
+		return
+	}
+	data, err := rlp.EncodeToBytes(p.sorted())
+	if err != nil {
+		log.Warn("Failed to encode finality participation", "hash", hash, "err", err)
+		return
+	}
+	rawdb.WriteFinalityParticipation(c.db, hash, data)
+}
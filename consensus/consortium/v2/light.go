@@ -0,0 +1,459 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	consortiumCommon "github.com/ethereum/go-ethereum/consensus/consortium/common"
+	"github.com/ethereum/go-ethereum/consensus/consortium/v2/finality"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/hashicorp/golang-lru/arc/v2"
+)
+
+var (
+	// errLightUnsupported is returned if the light verification is used on a chain
+	// or at a block without the finality votes
+	errLightUnsupported = errors.New("light verification requires consortium v2 after Shillin")
+
+	// errNoLightCheckpoint is returned if the light verifier has no trusted checkpoint yet
+	errNoLightCheckpoint = errors.New("no trusted light checkpoint")
+
+	// errNotEpochHeader is returned if a light checkpoint is not an epoch header
+	errNotEpochHeader = errors.New("light checkpoint is not an epoch header")
+
+	// errMissingCheckpointValidators is returned if the trusted checkpoint header
+	// does not carry the validator set
+	errMissingCheckpointValidators = errors.New("trusted checkpoint header has no checkpoint validators")
+
+	// errBeforeLightCheckpoint is returned if the header is not after the trusted checkpoint
+	errBeforeLightCheckpoint = errors.New("header is not after the trusted light checkpoint")
+
+	// errUnfinalizedCheckpoint is returned if the validator set of an epoch
+	// checkpoint is in effect before the checkpoint is finalized
+	errUnfinalizedCheckpoint = errors.New("epoch checkpoint is not finalized before its validator set takes effect")
+
+	// errMissingCheckpointFinality is returned if none of the headers after an
+	// epoch checkpoint carries the finality votes finalizing it
+	errMissingCheckpointFinality = errors.New("no finality vote for the epoch checkpoint")
+
+	// errUnauthorizedProducer is returned if the header is not sealed by a block producer
+	errUnauthorizedProducer = errors.New("header is not sealed by a block producer")
+
+	// errUnknownPreviousValidators is returned if the header is verified against
+	// the validator set before the trusted checkpoint, which is unknown
+	errUnknownPreviousValidators = errors.New("validator set before the trusted light checkpoint is unknown")
+
+	// errInvalidLightTd is returned if a total difficulty is out of the range the
+	// chain may have at the block number
+	errInvalidLightTd = errors.New("total difficulty out of range")
+)
+
+// LightCheckpoint is an epoch checkpoint header with the validator set taking
+// effect from it. The light clients keep the latest finalized checkpoint instead
+// of the snapshots, and verify the later headers against its validator set.
+type LightCheckpoint struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+
+	// Period is the period of the checkpoint, as the CurrentPeriod of the snapshot
+	Period uint64 `json:"period"`
+
+	// Validators are the finality voters and BlockProducers the block producers
+	// from the Apply block on, the previous ones are in effect before. The
+	// validator set is switched some blocks after the checkpoint, as the snapshot
+	// does.
+	Validators         []finality.ValidatorWithBlsPub `json:"validators"`
+	BlockProducers     []common.Address               `json:"blockProducers"`
+	Apply              uint64                         `json:"apply"`
+	PreviousValidators []finality.ValidatorWithBlsPub `json:"previousValidators,omitempty"`
+	PreviousProducers  []common.Address               `json:"previousProducers,omitempty"`
+}
+
+// NewLightCheckpoint derives the validator set of the epoch checkpoint header
+// from its extra data. The previous checkpoint is nil for the trusted checkpoint,
+// whose header must carry the validator set. The parent header is only required
+// after Venoki, where the period is derived from it.
+func NewLightCheckpoint(chainConfig *params.ChainConfig, header, parent *types.Header, previous *LightCheckpoint) (*LightCheckpoint, error) {
+	if chainConfig.Consortium == nil || !chainConfig.IsShillin(header.Number) {
+		return nil, errLightUnsupported
+	}
+	epoch := chainConfig.Consortium.EpochV2
+	if header.Number.Uint64()%epoch != 0 {
+		return nil, errNotEpochHeader
+	}
+	extraData, err := finality.DecodeExtraV2(header.Extra, chainConfig, header.Number)
+	if err != nil {
+		return nil, err
+	}
+	// The period is updated at every epoch checkpoint, as the snapshot does
+	var period uint64
+	if previous != nil {
+		period = previous.Period
+	}
+	switch {
+	case chainConfig.IsVenoki(header.Number):
+		if parent == nil || parent.Hash() != header.ParentHash {
+			return nil, consensus.ErrUnknownAncestor
+		}
+		period = parent.Time / dayInSeconds
+	case chainConfig.IsTripp(header.Number):
+		period = header.Time / dayInSeconds
+	}
+	// The light clients have no snapshot, derive whether Tripp is effective and
+	// whether the header is a period block from the chain config and the period
+	// of the previous checkpoint, as the engine does. The period of the block
+	// before the trusted checkpoint is unknown, the trusted header is a period
+	// block if it carries the validator set.
+	isTrippEffective := lightTrippEffective(chainConfig, header, period)
+	isPeriodBlock := len(extraData.CheckpointValidators) != 0
+	if previous != nil {
+		isPeriodBlock = period > previous.Period
+	}
+	if err := VerifyValidatorFields(chainConfig, epoch, extraData, header, isTrippEffective, isPeriodBlock); err != nil {
+		return nil, err
+	}
+
+	checkpoint := &LightCheckpoint{
+		Number:     header.Number.Uint64(),
+		Hash:       header.Hash(),
+		Period:     period,
+		Validators: extraData.CheckpointValidators,
+		Apply:      header.Number.Uint64(),
+	}
+	if previous != nil {
+		checkpoint.PreviousValidators = previous.Validators
+		checkpoint.PreviousProducers = previous.BlockProducers
+		checkpoint.Apply += uint64(len(previous.BlockProducers) / 2)
+		// After Tripp, the checkpoint validators are only updated at the period blocks
+		if len(checkpoint.Validators) == 0 {
+			checkpoint.Validators = previous.Validators
+		}
+	}
+	if len(checkpoint.Validators) == 0 {
+		return nil, errMissingCheckpointValidators
+	}
+	for _, validator := range checkpoint.Validators {
+		if validator.BlsPublicKey == nil {
+			return nil, finality.ErrUnauthorizedFinalityVoter
+		}
+	}
+
+	switch {
+	case isTrippEffective && chainConfig.IsAaron(header.Number):
+		for _, index := range extraData.BlockProducersBitSet.Indices() {
+			if index >= len(checkpoint.Validators) {
+				return nil, fmt.Errorf("%w: block producer bit set %d", finality.ErrInvalidExtraData, extraData.BlockProducersBitSet)
+			}
+		}
+		checkpoint.BlockProducers = decodeValidatorBitSet(extraData.BlockProducersBitSet, checkpoint.Validators)
+	case isTrippEffective:
+		checkpoint.BlockProducers = extraData.BlockProducers
+	default:
+		for _, validator := range checkpoint.Validators {
+			checkpoint.BlockProducers = append(checkpoint.BlockProducers, validator.Address)
+		}
+	}
+	return checkpoint, nil
+}
+
+// Bootstrap returns whether the checkpoint is the trusted one the light client
+// starts from. The validator set before it is unknown, so it only verifies the
+// next epoch checkpoint.
+func (checkpoint *LightCheckpoint) Bootstrap() bool {
+	return len(checkpoint.PreviousProducers) == 0
+}
+
+// validatorsAt returns the finality voters and the block producers in effect
+// at the block number, which must not be before the checkpoint.
+func (checkpoint *LightCheckpoint) validatorsAt(number uint64) ([]finality.ValidatorWithBlsPub, []common.Address) {
+	if number < checkpoint.Apply {
+		return checkpoint.PreviousValidators, checkpoint.PreviousProducers
+	}
+	return checkpoint.Validators, checkpoint.BlockProducers
+}
+
+// lightTrippEffective returns whether Tripp is effective at the header as
+// Consortium.IsTrippEffective does, the period being the one of the latest
+// epoch checkpoint before the header.
+func lightTrippEffective(chainConfig *params.ChainConfig, header *types.Header, period uint64) bool {
+	if !chainConfig.IsTripp(header.Number) {
+		return false
+	}
+	if header.Number.Uint64() > chainConfig.TrippBlock.Uint64()+28800 {
+		return true
+	}
+	if header.Number.Uint64()%chainConfig.Consortium.EpochV2 == 0 {
+		return header.Time/dayInSeconds > chainConfig.TrippPeriod.Uint64()
+	}
+	return period > chainConfig.TrippPeriod.Uint64()
+}
+
+// LightTotalDifficulty returns the total difficulty the light clients assume for
+// a trusted header, as they do not have the headers before it. It is the lowest
+// one the chain may have at the block number, so that the local chain is never
+// ahead of the honest peers.
+func LightTotalDifficulty(genesisTd *big.Int, number uint64) *big.Int {
+	td := new(big.Int).Mul(new(big.Int).SetUint64(number), diffNoTurn)
+	return td.Add(td, genesisTd)
+}
+
+// VerifyLightTotalDifficulty checks the total difficulty announced for the block
+// number is in the range the chain may have, every block being sealed either in
+// turn or out of turn.
+func VerifyLightTotalDifficulty(genesisTd *big.Int, number uint64, td *big.Int) error {
+	highest := new(big.Int).Mul(new(big.Int).SetUint64(number), diffInTurn)
+	highest.Add(highest, genesisTd)
+	if td == nil || td.Cmp(LightTotalDifficulty(genesisTd, number)) < 0 || td.Cmp(highest) > 0 {
+		return fmt.Errorf("%w: number %d, td %v", errInvalidLightTd, number, td)
+	}
+	return nil
+}
+
+// LightVerifier verifies the consortium v2 headers for the light clients. It
+// trusts a checkpoint and walks the later epoch checkpoints, each of them is
+// trusted once finalized by the finality votes of the validator set of the
+// previous one. The headers are verified against the validator set of the
+// latest checkpoint, without the snapshots nor the state.
+type LightVerifier struct {
+	chainConfig *params.ChainConfig
+	epoch       uint64
+	signatures  *arc.ARCCache[common.Hash, common.Address]
+
+	lock       sync.Mutex
+	checkpoint *LightCheckpoint
+	pending    map[common.Hash]*LightCheckpoint // Epoch checkpoints after the trusted one, not finalized yet
+}
+
+// NewLightVerifier creates a light verifier trusting the checkpoint, which may be
+// nil and set later on.
+func NewLightVerifier(chainConfig *params.ChainConfig, checkpoint *LightCheckpoint) (*LightVerifier, error) {
+	if chainConfig.Consortium == nil {
+		return nil, errLightUnsupported
+	}
+	signatures, _ := arc.NewARC[common.Hash, common.Address](inmemorySignatures)
+	return &LightVerifier{
+		chainConfig: chainConfig,
+		epoch:       chainConfig.Consortium.EpochV2,
+		signatures:  signatures,
+		checkpoint:  checkpoint,
+		pending:     make(map[common.Hash]*LightCheckpoint),
+	}, nil
+}
+
+// Checkpoint returns the latest finalized checkpoint.
+func (v *LightVerifier) Checkpoint() *LightCheckpoint {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	return v.checkpoint
+}
+
+// SetCheckpoint sets the trusted checkpoint.
+func (v *LightVerifier) SetCheckpoint(checkpoint *LightCheckpoint) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	v.checkpoint = checkpoint
+	v.pending = make(map[common.Hash]*LightCheckpoint)
+}
+
+// NextCheckpoint returns the number of the first header and the number of the
+// headers required to verify the epoch checkpoint following the trusted one. The
+// headers start with the parent of the checkpoint.
+func (v *LightVerifier) NextCheckpoint() (uint64, int, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if v.checkpoint == nil {
+		return 0, 0, errNoLightCheckpoint
+	}
+	// The finality votes for a block are carried in its child header, and must
+	// be cast before the validator set of the checkpoint takes effect
+	amount := len(v.checkpoint.BlockProducers)/2 + 1
+	if amount < 2 {
+		amount = 2
+	}
+	return v.checkpoint.Number - v.checkpoint.Number%v.epoch + v.epoch - 1, amount + 1, nil
+}
+
+// VerifyCheckpoint verifies the epoch checkpoint header following the trusted
+// checkpoint. The headers start with the parent of the checkpoint header, and
+// must contain a header carrying the finality votes for a block from the
+// checkpoint on, cast before the validator set of the checkpoint takes effect.
+// The verified checkpoint becomes the trusted one.
+func (v *LightVerifier) VerifyCheckpoint(headers []*types.Header) (*LightCheckpoint, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if v.checkpoint == nil {
+		return nil, errNoLightCheckpoint
+	}
+	if len(headers) < 3 {
+		return nil, errMissingCheckpointFinality
+	}
+	for i := 1; i < len(headers); i++ {
+		if headers[i].Number.Uint64() != headers[i-1].Number.Uint64()+1 || headers[i].ParentHash != headers[i-1].Hash() {
+			return nil, errOutOfRangeChain
+		}
+	}
+	number := headers[1].Number.Uint64()
+	if next := v.checkpoint.Number - v.checkpoint.Number%v.epoch + v.epoch; number != next {
+		return nil, fmt.Errorf("%w: expect epoch checkpoint %d, got %d", errNotEpochHeader, next, number)
+	}
+	checkpoint, err := NewLightCheckpoint(v.chainConfig, headers[1], headers[0], v.checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	for _, header := range headers[2:] {
+		extraData, err := finality.DecodeExtraV2(header.Extra, v.chainConfig, header.Number)
+		if err != nil {
+			return nil, err
+		}
+		if extraData.HasFinalityVote == 0 {
+			continue
+		}
+		target := header.Number.Uint64() - 1
+		if target >= checkpoint.Apply {
+			break
+		}
+		validators, _ := checkpoint.validatorsAt(target)
+		if _, err := finality.VerifyFinalityVotes(
+			validators,
+			lightTrippEffective(v.chainConfig, header, checkpoint.Period),
+			extraData.FinalityVotedValidators,
+			extraData.AggregatedFinalityVotes,
+			&types.VoteData{TargetNumber: target, TargetHash: header.ParentHash},
+		); err != nil {
+			return nil, err
+		}
+		v.checkpoint = checkpoint
+		v.pending = make(map[common.Hash]*LightCheckpoint)
+		return checkpoint, nil
+	}
+	return nil, errMissingCheckpointFinality
+}
+
+// VerifyHeader verifies a header after the trusted checkpoint is sealed by a
+// block producer with the matching difficulty and timestamp, and the finality
+// votes it carries if any. The epoch checkpoint headers are trusted once a later
+// header carries the finality votes for them.
+func (v *LightVerifier) VerifyHeader(chain consensus.ChainHeaderReader, header *types.Header, parents []*types.Header) error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if v.checkpoint == nil {
+		return errNoLightCheckpoint
+	}
+	if header.Number == nil || header.Number.Uint64() <= v.checkpoint.Number {
+		return errBeforeLightCheckpoint
+	}
+	if !v.chainConfig.IsShillin(header.Number) {
+		return errLightUnsupported
+	}
+	number := header.Number.Uint64()
+	extraData, err := finality.DecodeExtraV2(header.Extra, v.chainConfig, header.Number)
+	if err != nil {
+		return err
+	}
+
+	// The validator set at the parent block is the one of the latest epoch
+	// checkpoint, which may not be finalized yet
+	checkpoint := v.checkpoint
+	if epochNumber := (number - 1) - (number-1)%v.epoch; epochNumber > checkpoint.Number {
+		epochParent := FindAncientHeader(header, number-epochNumber+1, chain, parents)
+		if epochParent == nil {
+			return consensus.ErrUnknownAncestor
+		}
+		epochHeader := FindAncientHeader(header, number-epochNumber, chain, parents)
+		pending, ok := v.pending[epochHeader.Hash()]
+		if !ok {
+			if pending, err = NewLightCheckpoint(v.chainConfig, epochHeader, epochParent, checkpoint); err != nil {
+				return err
+			}
+			v.pending[epochHeader.Hash()] = pending
+		}
+		checkpoint = pending
+	}
+	if checkpoint.Bootstrap() {
+		return errUnknownPreviousValidators
+	}
+	if number-1 >= checkpoint.Apply && checkpoint != v.checkpoint {
+		return errUnfinalizedCheckpoint
+	}
+	validators, producers := checkpoint.validatorsAt(number - 1)
+
+	parent := FindAncientHeader(header, 1, chain, parents)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	// The recently signed producers are not tracked without the snapshots, so
+	// the back off time is not checked, only the block period
+	if header.Time > uint64(time.Now().Unix()) {
+		return consensus.ErrFutureBlock
+	}
+	if v.chainConfig.IsBuba(header.Number) && header.Time < parent.Time+v.chainConfig.Consortium.Period {
+		return consensus.ErrFutureBlock
+	}
+
+	signer, err := ecrecover(header, v.signatures, v.chainConfig.ChainID)
+	if err != nil {
+		return err
+	}
+	if signer != header.Coinbase {
+		return errCoinBaseMisMatch
+	}
+	authorized := false
+	for _, producer := range producers {
+		if producer == signer {
+			authorized = true
+			break
+		}
+	}
+	if !authorized {
+		return fmt.Errorf("%w: %s", errUnauthorizedProducer, signer)
+	}
+	// Ensure that the difficulty corresponds to the turn-ness of the signer
+	inturn := producers[number%uint64(len(producers))] == signer
+	if inturn && header.Difficulty.Cmp(diffInTurn) != 0 {
+		return consortiumCommon.ErrWrongDifficulty
+	}
+	if !inturn && header.Difficulty.Cmp(diffNoTurn) != 0 {
+		return consortiumCommon.ErrWrongDifficulty
+	}
+
+	if extraData.HasFinalityVote == 1 {
+		if _, err := finality.VerifyFinalityVotes(
+			validators,
+			lightTrippEffective(v.chainConfig, header, checkpoint.Period),
+			extraData.FinalityVotedValidators,
+			extraData.AggregatedFinalityVotes,
+			&types.VoteData{TargetNumber: number - 1, TargetHash: header.ParentHash},
+		); err != nil {
+			return err
+		}
+		// The votes finalize the parent block and the epoch checkpoint it descends from
+		if checkpoint != v.checkpoint {
+			v.checkpoint = checkpoint
+			v.pending = make(map[common.Hash]*LightCheckpoint)
+		}
+	}
+
+	// Check the validator fields of the epoch checkpoint header, it is trusted
+	// once finalized
+	if number%v.epoch == 0 {
+		if _, ok := v.pending[header.Hash()]; !ok {
+			pending, err := NewLightCheckpoint(v.chainConfig, header, parent, v.checkpoint)
+			if err != nil {
+				return err
+			}
+			v.pending[header.Hash()] = pending
+		}
+	}
+	return nil
+}
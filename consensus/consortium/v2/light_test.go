@@ -0,0 +1,354 @@
+package v2
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	consortiumCommon "github.com/ethereum/go-ethereum/consensus/consortium/common"
+	"github.com/ethereum/go-ethereum/consensus/consortium/v2/finality"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/bls/blst"
+	blsCommon "github.com/ethereum/go-ethereum/crypto/bls/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+type lightTestValidator struct {
+	ecdsaKey *ecdsa.PrivateKey
+	blsKey   blsCommon.SecretKey
+	info     finality.ValidatorWithBlsPub
+}
+
+func newLightTestValidators(t *testing.T, n int) []lightTestValidator {
+	validators := make([]lightTestValidator, n)
+	for i := range validators {
+		ecdsaKey, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatalf("Failed to generate ecdsa key, err %s", err)
+		}
+		blsKey, err := blst.RandKey()
+		if err != nil {
+			t.Fatalf("Failed to generate bls key, err %s", err)
+		}
+		validators[i] = lightTestValidator{
+			ecdsaKey: ecdsaKey,
+			blsKey:   blsKey,
+			info: finality.ValidatorWithBlsPub{
+				Address:      crypto.PubkeyToAddress(ecdsaKey.PublicKey),
+				BlsPublicKey: blsKey.PublicKey(),
+				Weight:       consortiumCommon.MaxFinalityVotePercentage / uint16(n),
+			},
+		}
+	}
+	return validators
+}
+
+// newLightTestChain generates the headers from the epoch checkpoint from to the
+// block to. The sets map holds the validator sets carried by the epoch
+// checkpoints, each of them takes effect half of the previous set later. After
+// Tripp, the epoch checkpoints carry the block producers, and the validator set
+// only at the period blocks. The voted map holds the finality voters of the
+// headers carrying the votes for their parent.
+func newLightTestChain(t *testing.T, chainConfig *params.ChainConfig, from, to uint64, sets, voted map[uint64][]lightTestValidator, timestamp func(uint64) uint64) []*types.Header {
+	var (
+		headers           []*types.Header
+		parent            common.Hash
+		current, previous []lightTestValidator
+		apply             uint64
+	)
+	effective := func(number uint64) []lightTestValidator {
+		if number < apply {
+			return previous
+		}
+		return current
+	}
+	for number := from; number <= to; number++ {
+		extraData := &finality.HeaderExtraData{}
+		if number%chainConfig.Consortium.EpochV2 == 0 {
+			next := current
+			if set, ok := sets[number]; ok {
+				next = set
+				for _, validator := range set {
+					extraData.CheckpointValidators = append(extraData.CheckpointValidators, validator.info)
+				}
+			}
+			if chainConfig.IsTripp(new(big.Int).SetUint64(number)) {
+				for _, validator := range next {
+					extraData.BlockProducers = append(extraData.BlockProducers, validator.info.Address)
+				}
+			}
+			apply = number + uint64(len(current)/2)
+			previous, current = current, next
+			if previous == nil {
+				previous = current
+			}
+		}
+		if voters, ok := voted[number]; ok {
+			voteData := types.VoteData{TargetNumber: number - 1, TargetHash: parent}
+			digest := voteData.Hash()
+			var signatures []blsCommon.Signature
+			for _, voter := range voters {
+				for i, validator := range effective(number - 1) {
+					if validator.info.Address == voter.info.Address {
+						extraData.FinalityVotedValidators.SetBit(i)
+					}
+				}
+				signatures = append(signatures, voter.blsKey.Sign(digest[:]))
+			}
+			extraData.HasFinalityVote = 1
+			extraData.AggregatedFinalityVotes = blst.AggregateSignatures(signatures)
+		}
+		producers := effective(number - 1)
+		producer := producers[number%uint64(len(producers))]
+		header := &types.Header{
+			ParentHash: parent,
+			Number:     new(big.Int).SetUint64(number),
+			Time:       timestamp(number),
+			Coinbase:   producer.info.Address,
+			Difficulty: new(big.Int).Set(diffInTurn),
+		}
+		extra, err := extraData.EncodeV2(chainConfig, header.Number)
+		if err != nil {
+			t.Fatalf("Failed to encode extra data, err %s", err)
+		}
+		header.Extra = extra
+		sealLightTestHeader(t, chainConfig, header, producer)
+
+		headers = append(headers, header)
+		parent = header.Hash()
+	}
+	return headers
+}
+
+// sealLightTestHeader signs the header by the block producer.
+func sealLightTestHeader(t *testing.T, chainConfig *params.ChainConfig, header *types.Header, producer lightTestValidator) {
+	header.Coinbase = producer.info.Address
+	hash := calculateSealHash(header, chainConfig.ChainID)
+	sig, err := crypto.Sign(hash[:], producer.ecdsaKey)
+	if err != nil {
+		t.Fatalf("Failed to sign header, err %s", err)
+	}
+	copy(header.Extra[len(header.Extra)-consortiumCommon.ExtraSeal:], sig)
+}
+
+func TestLightVerifier(t *testing.T) {
+	chainConfig := &params.ChainConfig{
+		ChainID:      big.NewInt(2021),
+		ShillinBlock: big.NewInt(0),
+		Consortium:   &params.ConsortiumConfig{EpochV2: 10},
+	}
+	first := newLightTestValidators(t, 4)
+	second := newLightTestValidators(t, 4)
+	sets := map[uint64][]lightTestValidator{10: first, 20: second}
+	timestamp := func(number uint64) uint64 { return number * 3 }
+	headers := newLightTestChain(t, chainConfig, 10, 25, sets, map[uint64][]lightTestValidator{
+		21: first[:3],
+		24: second[1:],
+	}, timestamp)
+
+	trusted, err := NewLightCheckpoint(chainConfig, headers[0], nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create trusted checkpoint, err %s", err)
+	}
+	if trusted.Apply != 10 || len(trusted.BlockProducers) != 4 || !trusted.Bootstrap() {
+		t.Fatalf("Unexpected trusted checkpoint, apply %d, producers %d", trusted.Apply, len(trusted.BlockProducers))
+	}
+
+	// The next epoch checkpoint is finalized by the votes of the first set
+	verifier, err := NewLightVerifier(chainConfig, trusted)
+	if err != nil {
+		t.Fatalf("Failed to create light verifier, err %s", err)
+	}
+	origin, amount, err := verifier.NextCheckpoint()
+	if err != nil {
+		t.Fatalf("Failed to get next checkpoint, err %s", err)
+	}
+	if origin != 19 || amount != 4 {
+		t.Fatalf("Expect next checkpoint from 19 with 4 headers, got %d with %d", origin, amount)
+	}
+	checkpoint, err := verifier.VerifyCheckpoint(headers[9:13])
+	if err != nil {
+		t.Fatalf("Failed to verify checkpoint, err %s", err)
+	}
+	if checkpoint.Number != 20 || checkpoint.Apply != 22 || checkpoint.Validators[0].Address != second[0].info.Address {
+		t.Fatalf("Unexpected checkpoint %d, apply %d", checkpoint.Number, checkpoint.Apply)
+	}
+	if verifier.Checkpoint() != checkpoint {
+		t.Fatal("Expect the verified checkpoint to be trusted")
+	}
+
+	// The headers before the next epoch checkpoint are sealed by the validator
+	// set before the trusted checkpoint, which is unknown
+	verifier, _ = NewLightVerifier(chainConfig, trusted)
+	for i := 1; i < len(headers); i++ {
+		err := verifier.VerifyHeader(nil, headers[i], headers[:i])
+		if headers[i].Number.Uint64() <= 20 {
+			if !errors.Is(err, errUnknownPreviousValidators) {
+				t.Fatalf("Expect error %v at header %d, got %v", errUnknownPreviousValidators, headers[i].Number, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Failed to verify header %d, err %s", headers[i].Number, err)
+		}
+	}
+	if verifier.Checkpoint().Number != 20 {
+		t.Fatalf("Expect checkpoint 20, got %d", verifier.Checkpoint().Number)
+	}
+	if err := verifier.VerifyHeader(nil, headers[5], nil); !errors.Is(err, errBeforeLightCheckpoint) {
+		t.Fatalf("Expect error %v, got %v", errBeforeLightCheckpoint, err)
+	}
+
+	// The votes signed by other keys do not finalize the checkpoint
+	forgers := make([]lightTestValidator, 3)
+	for i := range forgers {
+		forgers[i] = lightTestValidator{ecdsaKey: first[i].ecdsaKey, blsKey: second[i].blsKey, info: first[i].info}
+	}
+	forged := newLightTestChain(t, chainConfig, 10, 25, sets, map[uint64][]lightTestValidator{21: forgers}, timestamp)
+	verifier, _ = NewLightVerifier(chainConfig, trusted)
+	if _, err := verifier.VerifyCheckpoint(forged[9:13]); !errors.Is(err, finality.ErrFinalitySignatureVerificationFailed) {
+		t.Fatalf("Expect error %v, got %v", finality.ErrFinalitySignatureVerificationFailed, err)
+	}
+
+	// The second set can not produce blocks before the checkpoint is finalized
+	unfinalized := newLightTestChain(t, chainConfig, 10, 25, sets, nil, timestamp)
+	if _, err := verifier.VerifyCheckpoint(unfinalized[9:13]); !errors.Is(err, errMissingCheckpointFinality) {
+		t.Fatalf("Expect error %v, got %v", errMissingCheckpointFinality, err)
+	}
+	for i := 11; i < len(unfinalized); i++ {
+		err := verifier.VerifyHeader(nil, unfinalized[i], unfinalized[:i])
+		if unfinalized[i].Number.Uint64() <= 22 {
+			if err != nil {
+				t.Fatalf("Failed to verify header %d, err %s", unfinalized[i].Number, err)
+			}
+			continue
+		}
+		if !errors.Is(err, errUnfinalizedCheckpoint) {
+			t.Fatalf("Expect error %v, got %v", errUnfinalizedCheckpoint, err)
+		}
+		break
+	}
+}
+
+func TestLightVerifierTrippPeriod(t *testing.T) {
+	// The checkpoints 10 and 20 are in the same period, the checkpoint 30 is a
+	// period block switching the validator set
+	dayStart := (uint64(time.Now().Unix())/dayInSeconds - 1) * dayInSeconds
+	timestamp := func(number uint64) uint64 { return dayStart + 5 + 3*number - 90 }
+	chainConfig := &params.ChainConfig{
+		ChainID:      big.NewInt(2021),
+		ShillinBlock: big.NewInt(0),
+		BubaBlock:    big.NewInt(0),
+		TrippBlock:   big.NewInt(0),
+		TrippPeriod:  new(big.Int).SetUint64(dayStart/dayInSeconds - 2),
+		Consortium:   &params.ConsortiumConfig{EpochV2: 10, Period: 3},
+	}
+	first := newLightTestValidators(t, 4)
+	second := newLightTestValidators(t, 4)
+	sets := map[uint64][]lightTestValidator{10: first, 30: second}
+	headers := newLightTestChain(t, chainConfig, 10, 35, sets, map[uint64][]lightTestValidator{
+		21: first[:3],
+		31: first[1:],
+		34: second[:3],
+	}, timestamp)
+
+	trusted, err := NewLightCheckpoint(chainConfig, headers[0], nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create trusted checkpoint, err %s", err)
+	}
+	if trusted.Period != timestamp(10)/dayInSeconds {
+		t.Fatalf("Expect period %d, got %d", timestamp(10)/dayInSeconds, trusted.Period)
+	}
+	// A checkpoint not carrying the validator set can not be trusted
+	if _, err := NewLightCheckpoint(chainConfig, headers[10], nil, nil); !errors.Is(err, errMissingCheckpointValidators) {
+		t.Fatalf("Expect error %v, got %v", errMissingCheckpointValidators, err)
+	}
+
+	verifier, _ := NewLightVerifier(chainConfig, trusted)
+	for _, number := range []uint64{20, 30} {
+		origin, amount, err := verifier.NextCheckpoint()
+		if err != nil {
+			t.Fatalf("Failed to get next checkpoint, err %s", err)
+		}
+		checkpoint, err := verifier.VerifyCheckpoint(headers[origin-10 : origin-10+uint64(amount)])
+		if err != nil {
+			t.Fatalf("Failed to verify checkpoint %d, err %s", number, err)
+		}
+		if checkpoint.Number != number || checkpoint.Apply != number+2 {
+			t.Fatalf("Unexpected checkpoint %d, apply %d", checkpoint.Number, checkpoint.Apply)
+		}
+	}
+	checkpoint := verifier.Checkpoint()
+	if checkpoint.Period != timestamp(30)/dayInSeconds || checkpoint.Period != trusted.Period+1 {
+		t.Fatalf("Expect period %d, got %d", timestamp(30)/dayInSeconds, checkpoint.Period)
+	}
+	if checkpoint.Validators[0].Address != second[0].info.Address || checkpoint.PreviousValidators[0].Address != first[0].info.Address {
+		t.Fatal("Expect the validator set switched at the period block")
+	}
+
+	// The headers after the period block are verified against the new set
+	verifier, _ = NewLightVerifier(chainConfig, trusted)
+	if _, err := verifier.VerifyCheckpoint(headers[9:13]); err != nil {
+		t.Fatalf("Failed to verify checkpoint, err %s", err)
+	}
+	for i := 11; i < len(headers); i++ {
+		if err := verifier.VerifyHeader(nil, headers[i], headers[:i]); err != nil {
+			t.Fatalf("Failed to verify header %d, err %s", headers[i].Number, err)
+		}
+	}
+	if verifier.Checkpoint().Number != 30 {
+		t.Fatalf("Expect checkpoint 30, got %d", verifier.Checkpoint().Number)
+	}
+
+	// The difficulty must match the turn of the producer
+	parents := headers[:25]
+	header := types.CopyHeader(headers[25])
+	header.Difficulty = new(big.Int).Set(diffNoTurn)
+	sealLightTestHeader(t, chainConfig, header, second[35%4])
+	if err := verifier.VerifyHeader(nil, header, parents); !errors.Is(err, consortiumCommon.ErrWrongDifficulty) {
+		t.Fatalf("Expect error %v, got %v", consortiumCommon.ErrWrongDifficulty, err)
+	}
+	header = types.CopyHeader(headers[25])
+	sealLightTestHeader(t, chainConfig, header, second[(35+1)%4])
+	if err := verifier.VerifyHeader(nil, header, parents); !errors.Is(err, consortiumCommon.ErrWrongDifficulty) {
+		t.Fatalf("Expect error %v, got %v", consortiumCommon.ErrWrongDifficulty, err)
+	}
+	header.Difficulty = new(big.Int).Set(diffNoTurn)
+	sealLightTestHeader(t, chainConfig, header, second[(35+1)%4])
+	if err := verifier.VerifyHeader(nil, header, parents); err != nil {
+		t.Fatalf("Failed to verify out of turn header, err %s", err)
+	}
+
+	// The header must not be sealed before the block period nor in the future
+	for _, at := range []uint64{headers[24].Time + 2, uint64(time.Now().Unix()) + 60} {
+		header = types.CopyHeader(headers[25])
+		header.Time = at
+		sealLightTestHeader(t, chainConfig, header, second[35%4])
+		if err := verifier.VerifyHeader(nil, header, parents); !errors.Is(err, consensus.ErrFutureBlock) {
+			t.Fatalf("Expect error %v at time %d, got %v", consensus.ErrFutureBlock, at, err)
+		}
+	}
+}
+
+func TestLightTotalDifficulty(t *testing.T) {
+	genesisTd := big.NewInt(1)
+	td := LightTotalDifficulty(genesisTd, 100)
+	if td.Cmp(big.NewInt(301)) != 0 {
+		t.Fatalf("Expect td 301, got %v", td)
+	}
+	for _, td := range []*big.Int{big.NewInt(301), big.NewInt(501), big.NewInt(701)} {
+		if err := VerifyLightTotalDifficulty(genesisTd, 100, td); err != nil {
+			t.Fatalf("Failed to verify td %v, err %s", td, err)
+		}
+	}
+	for _, td := range []*big.Int{nil, big.NewInt(300), big.NewInt(702)} {
+		if err := VerifyLightTotalDifficulty(genesisTd, 100, td); !errors.Is(err, errInvalidLightTd) {
+			t.Fatalf("Expect error %v for td %v, got %v", errInvalidLightTd, td, err)
+		}
+	}
+}
@@ -517,9 +517,9 @@ func (bc *BlockChain) loadLatestDirtyAccounts() {
 	}
 }
 
-func (bc *BlockChain) StartDoubleSignMonitor() {
+func (bc *BlockChain) StartDoubleSignMonitor(alerter *monitor.AlertManager) {
 	log.Info("Starting double sign monitor")
-	doubleSignMonitor, err := monitor.NewDoubleSignMonitor()
+	doubleSignMonitor, err := monitor.NewDoubleSignMonitor(bc.db, alerter)
 	if err != nil {
 		log.Error("Double sign monitor creation failed", "err", err)
 		return
@@ -549,7 +549,7 @@ func (bc *BlockChain) StartDoubleSignMonitor() {
 	}
 }
 
-func (bc *BlockChain) StartFinalityVoteMonitor() {
+func (bc *BlockChain) StartFinalityVoteMonitor(alerter *monitor.AlertManager) {
 	log.Info("Starting finality vote monitor")
 
 	consensus, ok := bc.engine.(consensus.FastFinalityPoSA)
@@ -557,7 +557,7 @@ func (bc *BlockChain) StartFinalityVoteMonitor() {
 		log.Error("Not a fast finality consensus, stop finality vote monitor")
 		return
 	}
-	finalityVoteMonitor, err := monitor.NewFinalityVoteMonitor(bc, consensus)
+	finalityVoteMonitor, err := monitor.NewFinalityVoteMonitor(bc, consensus, alerter)
 	if err != nil {
 		log.Error("Finality vote monitor creation failed", "err", err)
 		return
@@ -593,6 +593,26 @@ func (bc *BlockChain) StartFinalityVoteMonitor() {
 	}
 }
 
+// StartValidatorUptimeMonitor feeds the canonical blocks to the validator uptime monitor
+func (bc *BlockChain) StartValidatorUptimeMonitor(uptimeMonitor *monitor.ValidatorUptimeMonitor) {
+	log.Info("Starting validator uptime monitor")
+
+	chainEventCh := make(chan ChainEvent)
+	chainEventSub := bc.SubscribeChainEvent(chainEventCh)
+	defer chainEventSub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-chainEventCh:
+			uptimeMonitor.CheckBlock(ev.Block.Header())
+		case <-chainEventSub.Err():
+			return
+		case <-bc.quit:
+			return
+		}
+	}
+}
+
 func (bc *BlockChain) EnableAdditionalChainEvent() {
 	bc.enableAdditionalChainEvent = true
 }
@@ -497,21 +497,27 @@ func (bc *BlockChain) ReadDirtyAccounts(hash common.Hash) []*types.DirtyStateAcc
 
 func (bc *BlockChain) OpEvents() []*vm.PublishEvent {
 	if bc.enableAdditionalChainEvent {
-		return []*vm.PublishEvent{
-			{
-				OpCodes: []vm.OpCode{
-					vm.CALL,
-					vm.DELEGATECALL,
-					vm.CREATE,
-					vm.CREATE2,
-				},
-				Event: &InternalTransactionEvent{},
-			},
-		}
+		return InternalTransactionOpEvents()
 	}
 	return nil
 }
 
+// InternalTransactionOpEvents returns the publish events which record internal
+// transactions (calls and contract creations) while a block is processed.
+func InternalTransactionOpEvents() []*vm.PublishEvent {
+	return []*vm.PublishEvent{
+		{
+			OpCodes: []vm.OpCode{
+				vm.CALL,
+				vm.DELEGATECALL,
+				vm.CREATE,
+				vm.CREATE2,
+			},
+			Event: &InternalTransactionEvent{},
+		},
+	}
+}
+
 type InternalTransactionEvent struct{}
 
 func (tx *InternalTransactionEvent) Publish(
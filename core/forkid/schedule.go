@@ -0,0 +1,121 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package forkid
+
+import (
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Fork is a block based fork rule of the chain config along with the fork id
+// the chain yields once the fork is activated.
+type Fork struct {
+	Name     string          `json:"name"`
+	Block    *hexutil.Big    `json:"block"`              // nil if the fork is not scheduled
+	Active   bool            `json:"active"`             // whether the fork is active at head
+	ForkHash *hexutil.Bytes  `json:"forkHash,omitempty"` // fork id hash at the fork block
+	ForkNext *hexutil.Uint64 `json:"forkNext,omitempty"` // fork id next at the fork block
+}
+
+// Conflict is a fork rule whose activation block differs between two chain
+// configs while the chain is already past the fork in either of them.
+type Conflict struct {
+	Name        string   `json:"name"`
+	StoredBlock *big.Int `json:"storedBlock"`
+	NewBlock    *big.Int `json:"newBlock"`
+}
+
+// forkRule is a block based fork rule field of the chain config.
+type forkRule struct {
+	name  string
+	index int
+}
+
+// gatherForkRules returns the block based fork rules of the chain config via
+// reflection, in the order of the config fields.
+func gatherForkRules() []forkRule {
+	kind := reflect.TypeOf(params.ChainConfig{})
+
+	var rules []forkRule
+	for i := 0; i < kind.NumField(); i++ {
+		field := kind.Field(i)
+		if !strings.HasSuffix(field.Name, "Block") || field.Type != reflect.TypeOf(new(big.Int)) {
+			continue
+		}
+		rules = append(rules, forkRule{name: strings.TrimSuffix(field.Name, "Block"), index: i})
+	}
+	return rules
+}
+
+// forkBlock returns the activation block of the fork rule in the chain config.
+func (r forkRule) forkBlock(config *params.ChainConfig) *big.Int {
+	return reflect.ValueOf(config).Elem().Field(r.index).Interface().(*big.Int)
+}
+
+// Schedule returns every block based fork of the chain config sorted by their
+// activation block, the unscheduled ones are listed last.
+func Schedule(config *params.ChainConfig, genesis common.Hash, head uint64) []Fork {
+	var forks []Fork
+	for _, rule := range gatherForkRules() {
+		fork := Fork{Name: rule.name}
+		if block := rule.forkBlock(config); block != nil {
+			var (
+				id   = NewID(config, genesis, block.Uint64())
+				hash = hexutil.Bytes(id.Hash[:])
+				next = hexutil.Uint64(id.Next)
+			)
+			fork.Block = (*hexutil.Big)(new(big.Int).Set(block))
+			fork.Active = block.Uint64() <= head
+			fork.ForkHash = &hash
+			fork.ForkNext = &next
+		}
+		forks = append(forks, fork)
+	}
+	sort.SliceStable(forks, func(i, j int) bool {
+		if forks[i].Block == nil || forks[j].Block == nil {
+			return forks[j].Block == nil && forks[i].Block != nil
+		}
+		return forks[i].Block.ToInt().Cmp(forks[j].Block.ToInt()) < 0
+	})
+	return forks
+}
+
+// Conflicts returns all the fork rules which can not be rescheduled from the
+// stored chain config to the new one, because the chain at head is already past
+// the fork in either of them.
+func Conflicts(stored, newcfg *params.ChainConfig, head uint64) []Conflict {
+	var (
+		conflicts []Conflict
+		number    = new(big.Int).SetUint64(head)
+	)
+	for _, rule := range gatherForkRules() {
+		s, n := rule.forkBlock(stored), rule.forkBlock(newcfg)
+		if s == nil && n == nil || s != nil && n != nil && s.Cmp(n) == 0 {
+			continue
+		}
+		if s != nil && s.Cmp(number) <= 0 || n != nil && n.Cmp(number) <= 0 {
+			conflicts = append(conflicts, Conflict{Name: rule.name, StoredBlock: s, NewBlock: n})
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package forkid
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TestSchedule tests that the forks are listed in activation order along with
+// the fork id yielded at their block.
+func TestSchedule(t *testing.T) {
+	config := &params.ChainConfig{
+		ChainID:        big.NewInt(2021),
+		HomesteadBlock: big.NewInt(0),
+		ShillinBlock:   big.NewInt(20),
+		OdysseusBlock:  big.NewInt(10),
+		MikoBlock:      big.NewInt(20),
+	}
+	genesis := common.HexToHash("0x01")
+	forks := Schedule(config, genesis, 15)
+
+	want := []struct {
+		name   string
+		block  int64
+		active bool
+	}{
+		{"Homestead", 0, true},
+		{"Odysseus", 10, true},
+		{"Shillin", 20, false},
+		{"Miko", 20, false},
+	}
+	for i, fork := range want {
+		if forks[i].Name != fork.name || forks[i].Block.ToInt().Int64() != fork.block || forks[i].Active != fork.active {
+			t.Errorf("fork %d: have %s at %v (active %v), want %s at %d (active %v)", i, forks[i].Name, forks[i].Block, forks[i].Active, fork.name, fork.block, fork.active)
+		}
+		id := NewID(config, genesis, uint64(fork.block))
+		if [4]byte(*forks[i].ForkHash) != id.Hash || uint64(*forks[i].ForkNext) != id.Next {
+			t.Errorf("fork %d: fork id mismatch: have %x/%d, want %x/%d", i, *forks[i].ForkHash, *forks[i].ForkNext, id.Hash, id.Next)
+		}
+	}
+	for _, fork := range forks[len(want):] {
+		if fork.Block != nil || fork.Active || fork.ForkHash != nil {
+			t.Errorf("fork %s: unexpected schedule %v", fork.Name, fork.Block)
+		}
+	}
+}
+
+// TestConflicts tests that only the forks rescheduled across the head are
+// reported as conflicts.
+func TestConflicts(t *testing.T) {
+	stored := &params.ChainConfig{
+		OdysseusBlock: big.NewInt(10),
+		FenixBlock:    big.NewInt(20),
+		ShillinBlock:  big.NewInt(30),
+		MikoBlock:     big.NewInt(40),
+	}
+	newcfg := &params.ChainConfig{
+		OdysseusBlock: big.NewInt(10), // unchanged
+		FenixBlock:    big.NewInt(22), // rescheduled in the past
+		ShillinBlock:  big.NewInt(35), // rescheduled in the future
+		TrippBlock:    big.NewInt(24), // scheduled in the past
+	}
+	conflicts := Conflicts(stored, newcfg, 25)
+	if len(conflicts) != 2 || conflicts[0].Name != "Fenix" || conflicts[1].Name != "Tripp" {
+		t.Fatalf("unexpected conflicts: %+v", conflicts)
+	}
+	if conflicts[1].StoredBlock != nil || conflicts[1].NewBlock.Uint64() != 24 {
+		t.Errorf("unexpected conflict blocks: %+v", conflicts[1])
+	}
+}
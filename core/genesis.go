@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/forkid"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -300,6 +301,7 @@ func SetupGenesisBlockWithOverride(db ethdb.Database, triedb *trie.Database, gen
 		}
 		// force update chainconfig if forceOverrideChainConfig is enabled
 		if forceOverrideChainConfig {
+			warnForkConflicts(db, rawdb.ReadChainConfig(db, stored), genesis.Config)
 			rawdb.WriteChainConfig(db, stored, genesis.Config)
 			return genesis.Config, stored, nil
 		}
@@ -338,6 +340,22 @@ func SetupGenesisBlockWithOverride(db ethdb.Database, triedb *trie.Database, gen
 	return newcfg, stored, nil
 }
 
+// warnForkConflicts logs the forks which are rescheduled across the current head
+// when the stored chain config is forcibly overridden, since the chain before
+// the head was processed under the stored rules.
+func warnForkConflicts(db ethdb.Database, storedcfg, newcfg *params.ChainConfig) {
+	if storedcfg == nil {
+		return
+	}
+	height := rawdb.ReadHeaderNumber(db, rawdb.ReadHeadHeaderHash(db))
+	if height == nil {
+		return
+	}
+	for _, conflict := range forkid.Conflicts(storedcfg, newcfg, *height) {
+		log.Warn("Overriding fork activated before the head", "fork", conflict.Name, "stored", conflict.StoredBlock, "new", conflict.NewBlock, "head", *height)
+	}
+}
+
 // LoadChainConfig loads the stored chain config if the chain config
 // is already present in database, otherwise, return the config in the
 // provided genesis specification.
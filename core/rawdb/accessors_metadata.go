@@ -178,3 +178,35 @@ func WriteHighestFinalityVote(db ethdb.KeyValueStore, highestFinalityVote uint64
 		log.Crit("Failed to store highest finality vote", "err", err)
 	}
 }
+
+// internalTxsBackfillProgress is the persisted marker of an internal transactions
+// backfill, the range start is kept so that only the same range can be resumed.
+type internalTxsBackfillProgress struct {
+	From uint64
+	Next uint64
+}
+
+// ReadInternalTxsBackfillProgress retrieves the start of the range and the next
+// block to be processed of the last interrupted internal transactions backfill.
+func ReadInternalTxsBackfillProgress(db ethdb.KeyValueReader) (uint64, uint64, bool) {
+	enc, _ := db.Get(internalTxsBackfillProgressKey)
+	if len(enc) == 0 {
+		return 0, 0, false
+	}
+	var progress internalTxsBackfillProgress
+	if err := rlp.DecodeBytes(enc, &progress); err != nil {
+		return 0, 0, false
+	}
+	return progress.From, progress.Next, true
+}
+
+// WriteInternalTxsBackfillProgress stores the progress of an internal transactions backfill.
+func WriteInternalTxsBackfillProgress(db ethdb.KeyValueWriter, from uint64, next uint64) {
+	enc, err := rlp.EncodeToBytes(&internalTxsBackfillProgress{From: from, Next: next})
+	if err != nil {
+		log.Crit("Failed to encode internal txs backfill progress", "err", err)
+	}
+	if err = db.Put(internalTxsBackfillProgressKey, enc); err != nil {
+		log.Crit("Failed to store internal txs backfill progress", "err", err)
+	}
+}
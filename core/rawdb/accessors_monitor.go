@@ -0,0 +1,88 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// DoubleSignEvidence is a pair of different headers at the same height which
+// are sealed by the same signer.
+type DoubleSignEvidence struct {
+	Signer  common.Address
+	Header1 *types.Header
+	Header2 *types.Header
+}
+
+// ReadDoubleSignEvidence retrieves the double sign evidence of the signer at
+// the given block number.
+func ReadDoubleSignEvidence(db ethdb.KeyValueReader, number uint64, signer common.Address) *DoubleSignEvidence {
+	data, _ := db.Get(doubleSignEvidenceKey(number, signer))
+	if len(data) == 0 {
+		return nil
+	}
+	evidence := new(DoubleSignEvidence)
+	if err := rlp.DecodeBytes(data, evidence); err != nil {
+		log.Error("Invalid double sign evidence RLP", "number", number, "signer", signer, "err", err)
+		return nil
+	}
+	return evidence
+}
+
+// ReadDoubleSignEvidencesInRange retrieves all the double sign evidences stored
+// for the blocks in range [first, last].
+func ReadDoubleSignEvidencesInRange(db ethdb.Iteratee, first, last uint64) []*DoubleSignEvidence {
+	var (
+		keyLength = len(doubleSignEvidencePrefix) + 8 + common.AddressLength
+		evidences = make([]*DoubleSignEvidence, 0)
+		it        = db.NewIterator(doubleSignEvidencePrefix, encodeBlockNumber(first))
+	)
+	defer it.Release()
+	for it.Next() {
+		key := it.Key()
+		if len(key) != keyLength {
+			continue
+		}
+		if binary.BigEndian.Uint64(key[len(doubleSignEvidencePrefix):len(doubleSignEvidencePrefix)+8]) > last {
+			break
+		}
+		evidence := new(DoubleSignEvidence)
+		if err := rlp.DecodeBytes(it.Value(), evidence); err != nil {
+			log.Error("Invalid double sign evidence RLP", "key", common.Bytes2Hex(key), "err", err)
+			continue
+		}
+		evidences = append(evidences, evidence)
+	}
+	return evidences
+}
+
+// WriteDoubleSignEvidence stores the double sign evidence into the database.
+func WriteDoubleSignEvidence(db ethdb.KeyValueWriter, evidence *DoubleSignEvidence) {
+	data, err := rlp.EncodeToBytes(evidence)
+	if err != nil {
+		log.Crit("Failed to RLP encode double sign evidence", "err", err)
+	}
+	if err := db.Put(doubleSignEvidenceKey(evidence.Header1.Number.Uint64(), evidence.Signer), data); err != nil {
+		log.Crit("Failed to store double sign evidence", "err", err)
+	}
+}
@@ -231,3 +231,18 @@ func WriteSnapshotConsortium(db ethdb.KeyValueWriter, hash common.Hash, snapshot
 func DeleteSnapshotConsortium(db ethdb.KeyValueWriter, hash common.Hash) error {
 	return db.Delete(snapshotConsortiumKey(hash))
 }
+
+// ReadFinalityParticipation retrieves the cached finality participation of the
+// epoch ending at the given block
+func ReadFinalityParticipation(db ethdb.KeyValueReader, hash common.Hash) []byte {
+	data, _ := db.Get(finalityParticipationKey(hash))
+	return data
+}
+
+// WriteFinalityParticipation stores the finality participation of the epoch
+// ending at the given block
+func WriteFinalityParticipation(db ethdb.KeyValueWriter, hash common.Hash, participation []byte) {
+	if err := db.Put(finalityParticipationKey(hash), participation); err != nil {
+		log.Crit("Failed to store finality participation", "err", err)
+	}
+}
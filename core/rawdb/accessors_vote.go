@@ -0,0 +1,121 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// SignedVote is the target of a finality vote signed by a local BLS key.
+type SignedVote struct {
+	TargetNumber uint64
+	TargetHash   common.Hash
+}
+
+// ReadSignedVote retrieves the target hash of the finality vote signed by the
+// BLS key at the given block number.
+func ReadSignedVote(db ethdb.KeyValueReader, publicKey []byte, number uint64) (common.Hash, bool) {
+	data, _ := db.Get(blsSignedVoteKey(publicKey, number))
+	if len(data) != common.HashLength {
+		return common.Hash{}, false
+	}
+	return common.BytesToHash(data), true
+}
+
+// ReadSignedVotes retrieves all the recorded finality votes signed by the BLS
+// key, in ascending target number order.
+func ReadSignedVotes(db ethdb.Iteratee, publicKey []byte) []*SignedVote {
+	var (
+		prefix = blsSignedVoteKey(publicKey, 0)[:len(blsSignedVotePrefix)+len(publicKey)]
+		votes  = make([]*SignedVote, 0)
+		it     = db.NewIterator(prefix, nil)
+	)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(prefix)+8 || len(it.Value()) != common.HashLength {
+			continue
+		}
+		votes = append(votes, &SignedVote{
+			TargetNumber: binary.BigEndian.Uint64(key[len(prefix):]),
+			TargetHash:   common.BytesToHash(it.Value()),
+		})
+	}
+	return votes
+}
+
+// WriteSignedVote stores the finality vote signed by the BLS key.
+func WriteSignedVote(db ethdb.KeyValueWriter, publicKey []byte, vote *SignedVote) {
+	if err := db.Put(blsSignedVoteKey(publicKey, vote.TargetNumber), vote.TargetHash.Bytes()); err != nil {
+		log.Crit("Failed to store signed vote", "err", err)
+	}
+}
+
+// DeleteSignedVote removes the finality vote signed by the BLS key at the given
+// block number.
+func DeleteSignedVote(db ethdb.KeyValueWriter, publicKey []byte, number uint64) {
+	if err := db.Delete(blsSignedVoteKey(publicKey, number)); err != nil {
+		log.Crit("Failed to delete signed vote", "err", err)
+	}
+}
+
+// ReadLatestSignedVote retrieves the finality vote with the highest target
+// number signed by the BLS key.
+func ReadLatestSignedVote(db ethdb.KeyValueReader, publicKey []byte) *SignedVote {
+	data, _ := db.Get(blsLatestVoteKey(publicKey))
+	if len(data) == 0 {
+		return nil
+	}
+	vote := new(SignedVote)
+	if err := rlp.DecodeBytes(data, vote); err != nil {
+		log.Error("Invalid latest signed vote RLP", "err", err)
+		return nil
+	}
+	return vote
+}
+
+// WriteLatestSignedVote stores the finality vote with the highest target number
+// signed by the BLS key.
+func WriteLatestSignedVote(db ethdb.KeyValueWriter, publicKey []byte, vote *SignedVote) {
+	data, err := rlp.EncodeToBytes(vote)
+	if err != nil {
+		log.Crit("Failed to encode latest signed vote", "err", err)
+	}
+	if err := db.Put(blsLatestVoteKey(publicKey), data); err != nil {
+		log.Crit("Failed to store latest signed vote", "err", err)
+	}
+}
+
+// ReadSignedVotePublicKeys retrieves the BLS keys having signed finality votes.
+func ReadSignedVotePublicKeys(db ethdb.Iteratee) [][]byte {
+	var (
+		keys [][]byte
+		it   = db.NewIterator(blsLatestVotePrefix, nil)
+	)
+	defer it.Release()
+
+	for it.Next() {
+		keys = append(keys, common.CopyBytes(it.Key()[len(blsLatestVotePrefix):]))
+	}
+	return keys
+}
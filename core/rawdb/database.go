@@ -458,6 +458,9 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		bloomBits       stat
 		cliqueSnaps     stat
 		consortiumSnaps stat
+		participations  stat
+		signedVotes     stat
+		evidences       stat
 
 		// Les statistic
 		chtTrieNodes   stat
@@ -520,6 +523,12 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			cliqueSnaps.Add(size)
 		case bytes.HasPrefix(key, snapshotConsortiumPrefix) && len(key) == len(snapshotConsortiumPrefix)+common.HashLength:
 			consortiumSnaps.Add(size)
+		case bytes.HasPrefix(key, finalityParticipationPrefix) && len(key) == len(finalityParticipationPrefix)+common.HashLength:
+			participations.Add(size)
+		case bytes.HasPrefix(key, blsSignedVotePrefix) || bytes.HasPrefix(key, blsLatestVotePrefix):
+			signedVotes.Add(size)
+		case bytes.HasPrefix(key, doubleSignEvidencePrefix) && len(key) == len(doubleSignEvidencePrefix)+8+common.AddressLength:
+			evidences.Add(size)
 		case bytes.HasPrefix(key, []byte("cht-")) ||
 			bytes.HasPrefix(key, []byte("chtIndexV2-")) ||
 			bytes.HasPrefix(key, []byte("chtRootV2-")): // Canonical hash trie
@@ -535,6 +544,7 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 				fastTrieProgressKey, snapshotDisabledKey, SnapshotRootKey, snapshotJournalKey,
 				snapshotGeneratorKey, snapshotRecoveryKey, txIndexTailKey, fastTxLookupLimitKey,
 				uncleanShutdownKey, badBlockKey, highestFinalityVoteKey, storeInternalTxsEnabledKey,
+				internalTxsBackfillProgressKey,
 				snapshotSyncStatusKey, persistentStateIDKey, trieJournalKey, snapshotSyncStatusKey, snapSyncStatusFlagKey,
 			} {
 				if bytes.Equal(key, meta) {
@@ -574,6 +584,9 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		{"Key-Value store", "Storage snapshot", storageSnaps.Size(), storageSnaps.Count()},
 		{"Key-Value store", "Clique snapshots", cliqueSnaps.Size(), cliqueSnaps.Count()},
 		{"Key-Value store", "Consortium snapshots", consortiumSnaps.Size(), consortiumSnaps.Count()},
+		{"Key-Value store", "Finality participations", participations.Size(), participations.Count()},
+		{"Key-Value store", "Slashing protection votes", signedVotes.Size(), signedVotes.Count()},
+		{"Key-Value store", "Double sign evidences", evidences.Size(), evidences.Count()},
 		{"Key-Value store", "Singleton metadata", metadata.Size(), metadata.Count()},
 		{"Light client", "CHT trie nodes", chtTrieNodes.Size(), chtTrieNodes.Count()},
 		{"Light client", "Bloom trie nodes", bloomTrieNodes.Size(), bloomTrieNodes.Count()},
@@ -85,11 +85,16 @@ var (
 	// storeInternalTxsEnabledKey flags that internal transactions will be stored into db
 	storeInternalTxsEnabledKey = []byte("storeInternalTxsEnabled")
 
+	// internalTxsBackfillProgressKey tracks the progress of the internal transactions backfill
+	internalTxsBackfillProgressKey = []byte("InternalTxsBackfillProgress")
+
 	// lastFinalityVoteKey tracks the highest finality vote
 	highestFinalityVoteKey = []byte("HighestFinalityVote")
 
 	snapshotConsortiumPrefix = []byte("consortium-") // key = ConsortiumSnapshotPrefix + block hash
 
+	finalityParticipationPrefix = []byte("finality-participation-") // key = finalityParticipationPrefix + epoch last block hash
+
 	// snapSyncStatusFlagKey flags that status of snap sync.
 	snapSyncStatusFlagKey = []byte("SnapSyncStatus")
 
@@ -113,6 +118,11 @@ var (
 	internalTxsPrefix = []byte("itxs") // internalTxsPrefix + block hash -> internal transactions
 	dirtyAccountsKey  = []byte("dacc") // dirtyAccountsPrefix + block hash -> dirty accounts
 
+	doubleSignEvidencePrefix = []byte("dse") // doubleSignEvidencePrefix + num (uint64 big endian) + signer -> conflicting headers
+
+	blsSignedVotePrefix = []byte("bls-signed-vote-") // blsSignedVotePrefix + BLS public key + num (uint64 big endian) -> target hash
+	blsLatestVotePrefix = []byte("bls-latest-vote-") // blsLatestVotePrefix + BLS public key -> latest signed vote
+
 	// Path-based storage scheme of merkle patricia trie.
 	TrieNodeAccountPrefix = []byte("A") // TrieNodeAccountPrefix + hexPath -> trie node
 	TrieNodeStoragePrefix = []byte("O") // TrieNodeStoragePrefix + accountHash + hexPath -> trie node
@@ -189,6 +199,21 @@ func internalTxsKey(hash common.Hash) []byte {
 	return append(internalTxsPrefix, hash.Bytes()...)
 }
 
+// doubleSignEvidenceKey = doubleSignEvidencePrefix + num (uint64 big endian) + signer
+func doubleSignEvidenceKey(number uint64, signer common.Address) []byte {
+	return append(append(doubleSignEvidencePrefix, encodeBlockNumber(number)...), signer.Bytes()...)
+}
+
+// blsSignedVoteKey = blsSignedVotePrefix + public key + num (uint64 big endian)
+func blsSignedVoteKey(publicKey []byte, number uint64) []byte {
+	return append(append(append([]byte{}, blsSignedVotePrefix...), publicKey...), encodeBlockNumber(number)...)
+}
+
+// blsLatestVoteKey = blsLatestVotePrefix + public key
+func blsLatestVoteKey(publicKey []byte) []byte {
+	return append(append([]byte{}, blsLatestVotePrefix...), publicKey...)
+}
+
 // txLookupKey = txLookupPrefix + hash
 func txLookupKey(hash common.Hash) []byte {
 	return append(txLookupPrefix, hash.Bytes()...)
@@ -262,6 +287,11 @@ func snapshotConsortiumKey(hash common.Hash) []byte {
 	return append(snapshotConsortiumPrefix, hash.Bytes()...)
 }
 
+// finalityParticipationKey = finalityParticipationPrefix + hash
+func finalityParticipationKey(hash common.Hash) []byte {
+	return append(finalityParticipationPrefix, hash.Bytes()...)
+}
+
 // IsLegacyTrieNode reports whether a provided database entry is a legacy trie
 // node. The characteristics of legacy trie node are:
 // - the key length is 32 bytes
@@ -1656,6 +1656,23 @@ func (p *BlobPool) ContentFrom(addr common.Address) ([]*types.Transaction, []*ty
 	return []*types.Transaction{}, []*types.Transaction{}
 }
 
+// ContentFromPayer retrieves the sponsored transactions paid by this address,
+// returning the pending as well as queued ones grouped by sender and sorted by
+// nonce.
+//
+// There are no sponsored transactions in the blob pool.
+func (p *BlobPool) ContentFromPayer(payer common.Address) (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
+	return map[common.Address][]*types.Transaction{}, map[common.Address][]*types.Transaction{}
+}
+
+// PayerCosts retrieves the total gas fee of the pending sponsored transactions
+// charged to each payer.
+//
+// There are no sponsored transactions in the blob pool.
+func (p *BlobPool) PayerCosts() map[common.Address]*big.Int {
+	return map[common.Address]*big.Int{}
+}
+
 // Locals retrieves the accounts currently considered local by the pool.
 //
 // There is no notion of local accounts in the blob pool.
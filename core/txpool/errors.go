@@ -56,4 +56,12 @@ var (
 	// input transaction of non-blob type when a blob transaction from this sender
 	// remains pending (and vice-versa).
 	ErrAlreadyReserved = errors.New("address already reserved")
+
+	// ErrConditionalNotSupported is returned if conditions are attached to a
+	// transaction of a type whose subpool does not support them.
+	ErrConditionalNotSupported = errors.New("conditional transactions not supported")
+
+	// ErrConditionalOverflow is returned if the pool already tracks as many
+	// conditional transactions, or storage slot conditions, as it accepts.
+	ErrConditionalOverflow = errors.New("too many conditional transactions")
 )
@@ -52,6 +52,16 @@ const (
 	// more expensive to propagate; larger transactions also take more resources
 	// to validate whether they fit into the pool or not.
 	txMaxSize = 4 * txSlotSize // 128KB
+
+	// maxConditionals is the maximum number of conditional transactions the pool
+	// tracks at once. The conditions of every one of them are re-checked against
+	// the new head on each reset, so both their count and the total number of
+	// storage slots they check are capped.
+	maxConditionals = 1024
+
+	// maxConditionalSlots is the maximum number of storage slots checked by all
+	// the tracked conditional transactions together.
+	maxConditionalSlots = 8 * types.MaxConditionalSlots
 )
 
 var (
@@ -65,6 +75,8 @@ var (
 	pendingReplaceMeter   = metrics.NewRegisteredMeter("txpool/pending/replace", nil)
 	pendingRateLimitMeter = metrics.NewRegisteredMeter("txpool/pending/ratelimit", nil) // Dropped due to rate limiting
 	pendingNofundsMeter   = metrics.NewRegisteredMeter("txpool/pending/nofunds", nil)   // Dropped due to out-of-funds
+	pendingExpiredMeter   = metrics.NewRegisteredMeter("txpool/pending/expired", nil)   // Dropped due to sponsored transaction expiry
+	pendingConditionMeter = metrics.NewRegisteredMeter("txpool/pending/condition", nil) // Dropped due to failed inclusion conditions
 
 	// Metrics for the queued pool
 	queuedDiscardMeter   = metrics.NewRegisteredMeter("txpool/queued/discard", nil)
@@ -72,6 +84,7 @@ var (
 	queuedRateLimitMeter = metrics.NewRegisteredMeter("txpool/queued/ratelimit", nil) // Dropped due to rate limiting
 	queuedNofundsMeter   = metrics.NewRegisteredMeter("txpool/queued/nofunds", nil)   // Dropped due to out-of-funds
 	queuedEvictionMeter  = metrics.NewRegisteredMeter("txpool/queued/eviction", nil)  // Dropped due to lifetime
+	queuedExpiredMeter   = metrics.NewRegisteredMeter("txpool/queued/expired", nil)   // Dropped due to sponsored transaction expiry
 
 	// General tx metrics
 	knownTxMeter       = metrics.NewRegisteredMeter("txpool/known", nil)
@@ -222,6 +235,9 @@ type LegacyPool struct {
 	changesSinceReorg int // A counter for how many drops we've performed in-between reorg.
 
 	totalPendingPayerCost map[common.Address]*big.Int // The total cost of pending transactions for each payer
+
+	conditionals     map[common.Hash]*types.TransactionConditional // Inclusion conditions of the conditional transactions
+	conditionalSlots int                                           // Number of storage slots checked by the tracked conditions
 }
 
 type txpoolResetRequest struct {
@@ -251,6 +267,7 @@ func New(config Config, chainconfig *params.ChainConfig, chain blockChain) *Lega
 		reorgShutdownCh:       make(chan struct{}),
 		initDoneCh:            make(chan struct{}),
 		totalPendingPayerCost: make(map[common.Address]*big.Int),
+		conditionals:          make(map[common.Hash]*types.TransactionConditional),
 	}
 	pool.locals = newAccountSet(pool.signer)
 	for _, addr := range config.Locals {
@@ -358,9 +375,10 @@ func (pool *LegacyPool) loop() {
 				prevPending, prevQueued, prevStales = pending, queued, stales
 			}
 
-		// Handle inactive account transaction eviction
+		// Handle inactive account and expired sponsored transaction eviction
 		case <-evict.C:
 			pool.mu.Lock()
+			pool.evictExpired(uint64(time.Now().Unix()))
 			for addr := range pool.queue {
 				// Skip local transactions from the eviction mechanism
 				if pool.locals.contains(addr) {
@@ -390,6 +408,47 @@ func (pool *LegacyPool) loop() {
 	}
 }
 
+// evictExpired removes all sponsored transactions whose expired time has passed,
+// local ones included, as they can never be included in a block anymore.
+func (pool *LegacyPool) evictExpired(now uint64) {
+	var pending, queued []common.Hash
+	for _, list := range pool.pending {
+		for _, tx := range list.Flatten() {
+			if isExpired(tx, now) {
+				pending = append(pending, tx.Hash())
+			}
+		}
+	}
+	for _, list := range pool.queue {
+		for _, tx := range list.Flatten() {
+			if isExpired(tx, now) {
+				queued = append(queued, tx.Hash())
+			}
+		}
+	}
+	for _, hash := range pending {
+		pool.removeTx(hash, true, true)
+	}
+	for _, hash := range queued {
+		pool.removeTx(hash, true, true)
+	}
+	if len(pending) > 0 || len(queued) > 0 {
+		log.Debug("Evicted expired sponsored transactions", "pending", len(pending), "queued", len(queued))
+	}
+	pendingExpiredMeter.Mark(int64(len(pending)))
+	queuedExpiredMeter.Mark(int64(len(queued)))
+}
+
+// isExpired returns whether the transaction is a sponsored transaction whose
+// expired time has passed at the given time.
+func isExpired(tx *types.Transaction, now uint64) bool {
+	if tx.Type() != types.SponsoredTxType {
+		return false
+	}
+	expiredTime := tx.ExpiredTime()
+	return expiredTime != 0 && expiredTime <= now
+}
+
 // Stop terminates the transaction pool.
 func (pool *LegacyPool) Close() error {
 	close(pool.reorgShutdownCh)
@@ -506,6 +565,49 @@ func (pool *LegacyPool) ContentFrom(addr common.Address) ([]*types.Transaction,
 	return pending, queued
 }
 
+// ContentFromPayer retrieves the sponsored transactions paid by this address,
+// returning the pending as well as queued ones grouped by sender and sorted by
+// nonce.
+func (pool *LegacyPool) ContentFromPayer(payer common.Address) (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return pool.sponsoredBy(payer, pool.pending), pool.sponsoredBy(payer, pool.queue)
+}
+
+// sponsoredBy filters the sponsored transactions paid by the payer out of the
+// given lists, grouped by sender.
+func (pool *LegacyPool) sponsoredBy(payer common.Address, lists map[common.Address]*list) map[common.Address][]*types.Transaction {
+	sponsored := make(map[common.Address][]*types.Transaction)
+	for addr, list := range lists {
+		if _, ok := list.payers[payer]; !ok {
+			continue
+		}
+		for _, tx := range list.Flatten() {
+			if tx.Type() != types.SponsoredTxType {
+				continue
+			}
+			if txPayer, err := types.Payer(pool.signer, tx); err == nil && txPayer == payer {
+				sponsored[addr] = append(sponsored[addr], tx)
+			}
+		}
+	}
+	return sponsored
+}
+
+// PayerCosts retrieves the total gas fee of the pending sponsored transactions
+// charged to each payer.
+func (pool *LegacyPool) PayerCosts() map[common.Address]*big.Int {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	costs := make(map[common.Address]*big.Int, len(pool.totalPendingPayerCost))
+	for payer, cost := range pool.totalPendingPayerCost {
+		costs[payer] = new(big.Int).Set(cost)
+	}
+	return costs
+}
+
 // Pending retrieves all currently processable transactions, grouped by origin
 // account and sorted by nonce.
 func (pool *LegacyPool) Pending(filter *txpool.PendingFilter) map[common.Address][]*txpool.LazyTransaction {
@@ -523,6 +625,15 @@ func (pool *LegacyPool) Pending(filter *txpool.PendingFilter) map[common.Address
 	for addr, list := range pool.pending {
 		txs := list.Flatten()
 
+		// Conditional transactions are never announced to the network
+		if filter.NoConditionals && len(pool.conditionals) > 0 {
+			for i, tx := range txs {
+				if _, ok := pool.conditionals[tx.Hash()]; ok {
+					txs = txs[:i]
+					break
+				}
+			}
+		}
 		// If the miner requests tip enforcement, cap the lists now
 		if filter.EnforceTip && !pool.locals.contains(addr) {
 			for i, tx := range txs {
@@ -536,14 +647,15 @@ func (pool *LegacyPool) Pending(filter *txpool.PendingFilter) map[common.Address
 			lazies := make([]*txpool.LazyTransaction, len(txs))
 			for i := 0; i < len(txs); i++ {
 				lazies[i] = &txpool.LazyTransaction{
-					Pool:      pool,
-					Hash:      txs[i].Hash(),
-					Tx:        txs[i],
-					Time:      txs[i].Time(),
-					GasFeeCap: uint256.MustFromBig(txs[i].GasFeeCap()),
-					GasTipCap: uint256.MustFromBig(txs[i].GasTipCap()),
-					Gas:       txs[i].Gas(),
-					BlobGas:   txs[i].BlobGas(),
+					Pool:        pool,
+					Hash:        txs[i].Hash(),
+					Tx:          txs[i],
+					Time:        txs[i].Time(),
+					GasFeeCap:   uint256.MustFromBig(txs[i].GasFeeCap()),
+					GasTipCap:   uint256.MustFromBig(txs[i].GasTipCap()),
+					Gas:         txs[i].Gas(),
+					BlobGas:     txs[i].BlobGas(),
+					Conditional: pool.conditionals[txs[i].Hash()],
 				}
 			}
 			pending[addr] = lazies
@@ -562,7 +674,8 @@ func (pool *LegacyPool) Locals() []common.Address {
 
 // local retrieves all currently known local transactions, grouped by origin
 // account and sorted by nonce. The returned transaction set is a copy and can be
-// freely modified by calling code.
+// freely modified by calling code. Conditional transactions are left out, as
+// their conditions are not journaled.
 func (pool *LegacyPool) local() map[common.Address]types.Transactions {
 	txs := make(map[common.Address]types.Transactions)
 	for addr := range pool.locals.accounts {
@@ -572,10 +685,26 @@ func (pool *LegacyPool) local() map[common.Address]types.Transactions {
 		if queued := pool.queue[addr]; queued != nil {
 			txs[addr] = append(txs[addr], queued.Flatten()...)
 		}
+		if len(pool.conditionals) > 0 {
+			txs[addr] = pool.withoutConditionals(txs[addr])
+		}
 	}
 	return txs
 }
 
+// withoutConditionals filters the conditional transactions out of the given list.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *LegacyPool) withoutConditionals(txs types.Transactions) types.Transactions {
+	filtered := make(types.Transactions, 0, len(txs))
+	for _, tx := range txs {
+		if _, ok := pool.conditionals[tx.Hash()]; !ok {
+			filtered = append(filtered, tx)
+		}
+	}
+	return filtered
+}
+
 func (pool *LegacyPool) getAccountPendingCost(account common.Address) *big.Int {
 	pendingCost := new(big.Int)
 	if list := pool.pending[account]; list != nil {
@@ -611,6 +740,34 @@ func (pool *LegacyPool) validateTxBasics(tx *types.Transaction, local bool) erro
 	return nil
 }
 
+// ValidateTx checks whether a transaction would be accepted by the pool from a
+// remote peer, without adding it. As the transaction does not enter the pool, the
+// nonce ordering and the pool limits are not checked, and the balances are only
+// checked against the transaction itself.
+func (pool *LegacyPool) ValidateTx(tx *types.Transaction) error {
+	if err := pool.validateTxBasics(tx, false); err != nil {
+		return err
+	}
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	opts := &txpool.ValidationOptionsWithState{
+		Config: pool.chainconfig,
+		State:  pool.currentState,
+		Head:   pool.currentHead.Load(),
+		UsedAndLeftSlots: func(addr common.Address) (int, int) {
+			return 0, math.MaxInt
+		},
+		ExistingExpenditure: func(addr common.Address) *big.Int {
+			return new(big.Int)
+		},
+		ExistingCost: func(addr common.Address, nonce uint64) *big.Int {
+			return nil
+		},
+	}
+	return txpool.ValidateTransactionWithState(tx, pool.signer, opts)
+}
+
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
@@ -871,6 +1028,10 @@ func (pool *LegacyPool) journalTx(from common.Address, tx *types.Transaction) {
 	if pool.journal == nil || !pool.locals.contains(from) {
 		return
 	}
+	// Skip conditional transactions, their conditions are not journaled
+	if _, ok := pool.conditionals[tx.Hash()]; ok {
+		return
+	}
 	if err := pool.journal.insert(tx); err != nil {
 		log.Warn("Failed to journal local transaction", "err", err)
 	}
@@ -930,6 +1091,57 @@ func (pool *LegacyPool) AddLocal(tx *types.Transaction) error {
 	return errs[0]
 }
 
+// AddConditional enqueues a local transaction which is only included in a block
+// while the given conditions hold. The conditions are enforced by the miner, and
+// the transaction is dropped once they fail against the chain head.
+//
+// Conditional transactions are neither announced to the network nor journaled,
+// since other nodes and a restarted pool would include them without the checks.
+func (pool *LegacyPool) AddConditional(tx *types.Transaction, cond *types.TransactionConditional) error {
+	local := !pool.config.NoLocals
+	if err := pool.validateTxBasics(tx, local); err != nil {
+		invalidTxMeter.Mark(1)
+		return err
+	}
+	hash := tx.Hash()
+
+	pool.mu.Lock()
+	if pool.all.Get(hash) != nil {
+		pool.mu.Unlock()
+		knownTxMeter.Mark(1)
+		return txpool.ErrAlreadyKnown
+	}
+	if len(pool.conditionals) >= maxConditionals || pool.conditionalSlots+cond.Slots() > maxConditionalSlots {
+		pool.mu.Unlock()
+		return txpool.ErrConditionalOverflow
+	}
+	// Attach the conditions before the transaction becomes visible to the miner
+	pool.conditionals[hash] = cond
+	pool.conditionalSlots += cond.Slots()
+
+	errs, dirtyAddrs := pool.addTxsLocked([]*types.Transaction{tx}, local)
+	if errs[0] != nil {
+		pool.forgetConditional(hash)
+	}
+	pool.mu.Unlock()
+
+	if errs[0] != nil {
+		return errs[0]
+	}
+	<-pool.requestPromoteExecutables(dirtyAddrs)
+	return nil
+}
+
+// forgetConditional releases the inclusion conditions of a transaction.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *LegacyPool) forgetConditional(hash common.Hash) {
+	if cond := pool.conditionals[hash]; cond != nil {
+		pool.conditionalSlots -= cond.Slots()
+		delete(pool.conditionals, hash)
+	}
+}
+
 // AddRemotes enqueues a batch of transactions into the pool if they are valid. If the
 // senders are not among the locally tracked ones, full pricing constraints will apply.
 //
@@ -1285,6 +1497,7 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 	// because of another transaction (e.g. higher gas price).
 	if reset != nil {
 		pool.demoteUnexecutables()
+		pool.dropConditionals()
 		if reset.newHead != nil {
 			if pool.chainconfig.IsLondon(new(big.Int).Add(reset.newHead.Number, big.NewInt(1))) {
 				// london fork enabled, reset given the base fee
@@ -1307,6 +1520,20 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 	pool.truncatePending()
 	pool.truncateQueue()
 
+	// Conditional transactions are only handed to the local miner, keep them out
+	// of the announcements so that peers never include them unchecked
+	if len(pool.conditionals) > 0 {
+		promoted = pool.withoutConditionals(promoted)
+		for addr, set := range events {
+			set.Filter(func(tx *types.Transaction) bool {
+				_, ok := pool.conditionals[tx.Hash()]
+				return ok
+			})
+			if set.Len() == 0 {
+				delete(events, addr)
+			}
+		}
+	}
 	dropBetweenReorgHistogram.Update(int64(pool.changesSinceReorg))
 	pool.changesSinceReorg = 0 // Reset change counter
 	pool.mu.Unlock()
@@ -1444,12 +1671,17 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 		head := pool.currentHead.Load()
 		maxGas := txpool.CurrentBlockMaxGas(pool.chainconfig, head)
 		drops, _ := list.Filter(pool.currentState.GetBalance(addr), maxGas, payerCostLimit, head.Time)
+		var expired int
 		for _, tx := range drops {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			if isExpired(tx, head.Time) {
+				expired++
+			}
 		}
 		log.Trace("Removed unpayable queued transactions", "count", len(drops))
-		queuedNofundsMeter.Mark(int64(len(drops)))
+		queuedNofundsMeter.Mark(int64(len(drops) - expired))
+		queuedExpiredMeter.Mark(int64(expired))
 
 		// Gather all executable transactions and promote them
 		readies := list.Ready(pool.pendingNonces.get(addr))
@@ -1623,6 +1855,29 @@ func (pool *LegacyPool) truncateQueue() {
 	}
 }
 
+// dropConditionals removes the transactions whose inclusion conditions can no
+// longer hold on top of the current head, and forgets the conditions of the
+// transactions which left the pool.
+func (pool *LegacyPool) dropConditionals() {
+	head := pool.currentHead.Load()
+	for hash, cond := range pool.conditionals {
+		if pool.all.Get(hash) == nil {
+			pool.forgetConditional(hash)
+			continue
+		}
+		err := cond.CheckAfter(head)
+		if err == nil {
+			err = cond.CheckState(pool.currentState)
+		}
+		if err != nil {
+			log.Debug("Dropped conditional transaction", "hash", hash, "reason", err)
+			pool.removeTx(hash, true, true)
+			pool.forgetConditional(hash)
+			pendingConditionMeter.Mark(1)
+		}
+	}
+}
+
 // demoteUnexecutables removes invalid and processed transactions from the pools
 // executable/pending queue and any subsequent transactions that become unexecutable
 // are moved back into the future queue.
@@ -1652,12 +1907,17 @@ func (pool *LegacyPool) demoteUnexecutables() {
 		head := pool.currentHead.Load()
 		maxGas := txpool.CurrentBlockMaxGas(pool.chainconfig, head)
 		drops, invalids := list.Filter(pool.currentState.GetBalance(addr), maxGas, payerCostLimit, head.Time)
+		var expired int
 		for _, tx := range drops {
 			hash := tx.Hash()
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
 			pool.all.Remove(hash)
+			if isExpired(tx, head.Time) {
+				expired++
+			}
 		}
-		pendingNofundsMeter.Mark(int64(len(drops)))
+		pendingNofundsMeter.Mark(int64(len(drops) - expired))
+		pendingExpiredMeter.Mark(int64(expired))
 
 		for _, tx := range invalids {
 			hash := tx.Hash()
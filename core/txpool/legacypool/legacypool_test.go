@@ -3342,3 +3342,203 @@ func TestFeeCapCheckVenoki(t *testing.T) {
 		t.Fatalf("Pending txpool, expect %d get %d", 0, pending)
 	}
 }
+
+// TestSponsoredTxContentFromPayerAndEviction tests that the sponsored txs are
+// retrievable by their payer and that the expired ones are evicted.
+func TestSponsoredTxContentFromPayerAndEviction(t *testing.T) {
+	var chainConfig params.ChainConfig
+
+	chainConfig.EIP155Block = common.Big0
+	chainConfig.MikoBlock = common.Big0
+	chainConfig.ChainID = big.NewInt(2020)
+
+	recipient := common.HexToAddress("1000000000000000000000000000000000000001")
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{10000000, statedb, new(event.Feed), 0}
+
+	txpool := New(testTxPoolConfig, &chainConfig, blockchain)
+	defer txpool.Close()
+	txpool.Init(
+		testTxPoolConfig.PriceLimit,
+		blockchain.CurrentBlock().Header(),
+		func(addr common.Address, reserve bool) error { return nil },
+	)
+
+	senderKey, _ := crypto.GenerateKey()
+	senderAddr := crypto.PubkeyToAddress(senderKey.PublicKey)
+	payerKey, _ := crypto.GenerateKey()
+	payerAddr := crypto.PubkeyToAddress(payerKey.PublicKey)
+
+	mikoSigner := types.NewMikoSigner(big.NewInt(2020))
+	gasFee := new(big.Int).Mul(big.NewInt(100000), big.NewInt(30000))
+	statedb.SetBalance(payerAddr, new(big.Int).Mul(gasFee, big.NewInt(2)))
+	statedb.SetBalance(senderAddr, big.NewInt(20))
+
+	// The first transaction expires, the second one never does
+	for nonce, expiredTime := range []uint64{100, 0} {
+		innerTx := types.SponsoredTx{
+			ChainID:     big.NewInt(2020),
+			Nonce:       uint64(nonce),
+			GasTipCap:   big.NewInt(100000),
+			GasFeeCap:   big.NewInt(100000),
+			Gas:         30000,
+			To:          &recipient,
+			Value:       big.NewInt(10),
+			ExpiredTime: expiredTime,
+		}
+		var err error
+		innerTx.PayerR, innerTx.PayerS, innerTx.PayerV, err = types.PayerSign(payerKey, mikoSigner, senderAddr, &innerTx)
+		if err != nil {
+			t.Fatalf("Payer fails to sign transaction, err %s", err)
+		}
+		tx, err := types.SignNewTx(senderKey, mikoSigner, &innerTx)
+		if err != nil {
+			t.Fatalf("Fail to sign transaction, err %s", err)
+		}
+		if err := txpool.addRemoteSync(tx); err != nil {
+			t.Fatalf("Expect successfully add tx, get %s", err)
+		}
+	}
+
+	pending, queued := txpool.ContentFromPayer(payerAddr)
+	if len(pending[senderAddr]) != 2 || len(queued) != 0 {
+		t.Fatalf("Expect 2 pending sponsored txs, got %d pending and %d queued", len(pending[senderAddr]), len(queued))
+	}
+	if pending, queued := txpool.ContentFromPayer(senderAddr); len(pending) != 0 || len(queued) != 0 {
+		t.Fatalf("Expect no sponsored txs paid by sender, got %d pending and %d queued", len(pending), len(queued))
+	}
+	expected := new(big.Int).Mul(gasFee, big.NewInt(2))
+	if cost := txpool.PayerCosts()[payerAddr]; cost == nil || cost.Cmp(expected) != 0 {
+		t.Fatalf("Expect payer cost %s, got %s", expected, cost)
+	}
+
+	// The expired transaction is evicted and the following one is demoted
+	txpool.mu.Lock()
+	txpool.evictExpired(200)
+	txpool.mu.Unlock()
+
+	pending, queued = txpool.ContentFromPayer(payerAddr)
+	if len(pending) != 0 || len(queued[senderAddr]) != 1 {
+		t.Fatalf("Expect 1 queued sponsored tx, got %d pending and %d queued", len(pending), len(queued[senderAddr]))
+	}
+	if queued[senderAddr][0].Nonce() != 1 {
+		t.Fatalf("Expect queued tx with nonce 1, got %d", queued[senderAddr][0].Nonce())
+	}
+	if cost := txpool.PayerCosts()[payerAddr]; cost != nil {
+		t.Fatalf("Expect no payer cost, got %s", cost)
+	}
+	if err := validatePoolInternals(txpool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the inclusion conditions of a transaction are handed to the miner
+// and that the transaction is dropped once they fail against the chain head.
+func TestConditionalTransactions(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	var (
+		addr     = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.Address{0xc0}
+		slot     = common.Hash{0x01}
+		max      = uint64(5)
+	)
+	testAddBalance(pool, addr, big.NewInt(1000000))
+
+	events := make(chan core.NewTxsEvent, 4)
+	sub := pool.txFeed.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	cond := &types.TransactionConditional{
+		KnownAccounts:  map[common.Address]map[common.Hash]common.Hash{contract: {slot: {}}},
+		BlockNumberMax: &max,
+	}
+	tx0, tx1 := transaction(0, 100000, key), transaction(1, 100000, key)
+	if err := pool.AddConditional(tx0, cond); err != nil {
+		t.Fatalf("failed to add conditional transaction: %v", err)
+	}
+	if err := pool.AddConditional(tx0, cond); !errors.Is(err, txpool.ErrAlreadyKnown) {
+		t.Fatalf("known transaction error mismatch: have %v, want %v", err, txpool.ErrAlreadyKnown)
+	}
+	if err := pool.addRemoteSync(tx1); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	pending := pool.Pending(&txpool.PendingFilter{})[addr]
+	if len(pending) != 2 {
+		t.Fatalf("pending transactions mismatch: have %d, want 2", len(pending))
+	}
+	if pending[0].Conditional != cond || pending[1].Conditional != nil {
+		t.Fatal("conditions not attached to the pending transactions")
+	}
+	// Conditional transactions are neither announced nor journaled
+	if err := validateEvents(events, 1); err != nil {
+		t.Fatalf("event firing failed: %v", err)
+	}
+	if announced := pool.Pending(&txpool.PendingFilter{NoConditionals: true})[addr]; len(announced) != 0 {
+		t.Fatalf("announced transactions mismatch: have %d, want 0", len(announced))
+	}
+	pool.mu.Lock()
+	journaled := pool.local()[addr]
+	pool.mu.Unlock()
+	if len(journaled) != 1 || journaled[0].Hash() != tx1.Hash() {
+		t.Fatalf("journaled transactions mismatch: have %d, want only the plain one", len(journaled))
+	}
+	// Conditions still holding keep the transaction in the pool
+	<-pool.requestReset(nil, nil)
+	if pool.Get(tx0.Hash()) == nil {
+		t.Fatal("conditional transaction dropped while the conditions hold")
+	}
+	// Once the storage slot changes, the transaction is dropped
+	pool.mu.Lock()
+	pool.currentState.SetState(contract, slot, common.Hash{0x01})
+	pool.mu.Unlock()
+
+	<-pool.requestReset(nil, nil)
+	if pool.Get(tx0.Hash()) != nil {
+		t.Fatal("conditional transaction not dropped")
+	}
+	if len(pool.conditionals) != 0 || pool.conditionalSlots != 0 {
+		t.Fatalf("conditions not released: %d left, %d slots", len(pool.conditionals), pool.conditionalSlots)
+	}
+	if pending, queued := pool.Stats(); pending != 0 || queued != 1 {
+		t.Fatalf("pool stats mismatch: have %d pending, %d queued, want 0 and 1", pending, queued)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the pool caps the number of conditional transactions it tracks.
+func TestConditionalTransactionsLimit(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000))
+
+	cond := &types.TransactionConditional{
+		KnownAccounts: map[common.Address]map[common.Hash]common.Hash{{0xc0}: {{0x01}: {}}},
+	}
+	pool.mu.Lock()
+	pool.conditionalSlots = maxConditionalSlots
+	pool.mu.Unlock()
+
+	tx := transaction(0, 100000, key)
+	if err := pool.AddConditional(tx, cond); !errors.Is(err, txpool.ErrConditionalOverflow) {
+		t.Fatalf("overflow error mismatch: have %v, want %v", err, txpool.ErrConditionalOverflow)
+	}
+	if pool.Get(tx.Hash()) != nil {
+		t.Fatal("rejected conditional transaction added to the pool")
+	}
+	pool.mu.Lock()
+	pool.conditionalSlots = 0
+	pool.mu.Unlock()
+
+	if err := pool.AddConditional(tx, cond); err != nil {
+		t.Fatalf("failed to add conditional transaction: %v", err)
+	}
+}
@@ -41,6 +41,8 @@ type LazyTransaction struct {
 
 	Gas     uint64 // Amount of gas required by the transaction
 	BlobGas uint64 // Amount of blob gas required by the transaction
+
+	Conditional *types.TransactionConditional // Conditions the transaction is only included under
 }
 
 // Resolve retrieves the full transaction belonging to a lazy handle if it is still
@@ -88,6 +90,8 @@ type PendingFilter struct {
 
 	OnlyPlainTxs bool // Return only plain EVM transactions (peer-join announces, block space filling)
 	OnlyBlobTxs  bool // Return only blob transactions (block blob-space filling)
+
+	NoConditionals bool // Skip the transactions carrying inclusion conditions (peer-join announces)
 }
 
 // SubPool represents a specialized transaction pool that lives on its own (e.g.
@@ -164,6 +168,15 @@ type SubPool interface {
 	// pending as well as queued transactions of this address, grouped by nonce.
 	ContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction)
 
+	// ContentFromPayer retrieves the sponsored transactions paid by this address,
+	// returning the pending as well as queued ones grouped by sender and sorted
+	// by nonce.
+	ContentFromPayer(payer common.Address) (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction)
+
+	// PayerCosts retrieves the total gas fee of the pending sponsored transactions
+	// charged to each payer.
+	PayerCosts() map[common.Address]*big.Int
+
 	// Locals retrieves the accounts currently considered local by the pool.
 	Locals() []common.Address
 
@@ -171,3 +184,20 @@ type SubPool interface {
 	// identified by their hashes.
 	Status(hash common.Hash) TxStatus
 }
+
+// ConditionalSubPool is implemented by the subpools accepting transactions which
+// are only included in a block while a set of conditions hold.
+type ConditionalSubPool interface {
+	// AddConditional enqueues a local transaction along with its conditions. The
+	// conditions are enforced by the miner and the transaction is dropped once
+	// they fail against the chain head.
+	AddConditional(tx *types.Transaction, cond *types.TransactionConditional) error
+}
+
+// ValidatingSubPool is implemented by the subpools able to validate transactions
+// which do not enter the pool, such as the transactions of a bundle.
+type ValidatingSubPool interface {
+	// ValidateTx checks whether a transaction would be accepted by the pool from
+	// a remote peer, without adding it.
+	ValidateTx(tx *types.Transaction) error
+}
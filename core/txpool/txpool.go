@@ -300,6 +300,36 @@ func (p *TxPool) GetRLP(hash common.Hash) []byte {
 	return nil
 }
 
+// AddConditional enqueues a local transaction which is only included in a block
+// while the given conditions hold.
+func (p *TxPool) AddConditional(tx *types.Transaction, cond *types.TransactionConditional) error {
+	for _, subpool := range p.subpools {
+		if subpool.Filter(tx) {
+			pool, ok := subpool.(ConditionalSubPool)
+			if !ok {
+				return ErrConditionalNotSupported
+			}
+			return pool.AddConditional(tx, cond)
+		}
+	}
+	return core.ErrTxTypeNotSupported
+}
+
+// ValidateTx checks whether a transaction would be accepted by the subpool it
+// belongs to from a remote peer, without adding it.
+func (p *TxPool) ValidateTx(tx *types.Transaction) error {
+	for _, subpool := range p.subpools {
+		if subpool.Filter(tx) {
+			pool, ok := subpool.(ValidatingSubPool)
+			if !ok {
+				return fmt.Errorf("%w: tx type %v", core.ErrTxTypeNotSupported, tx.Type())
+			}
+			return pool.ValidateTx(tx)
+		}
+	}
+	return core.ErrTxTypeNotSupported
+}
+
 // Add enqueues a batch of transactions into the pool if they are valid. Due
 // to the large transaction churn, add may postpone fully integrating the tx
 // to a later point to batch multiple ones together.
@@ -431,6 +461,42 @@ func (p *TxPool) ContentFrom(addr common.Address) ([]*types.Transaction, []*type
 	return []*types.Transaction{}, []*types.Transaction{}
 }
 
+// ContentFromPayer retrieves the sponsored transactions paid by this address,
+// returning the pending as well as queued ones grouped by sender and sorted by
+// nonce.
+func (p *TxPool) ContentFromPayer(payer common.Address) (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
+	var (
+		runnable = make(map[common.Address][]*types.Transaction)
+		blocked  = make(map[common.Address][]*types.Transaction)
+	)
+	for _, subpool := range p.subpools {
+		run, block := subpool.ContentFromPayer(payer)
+
+		for addr, txs := range run {
+			runnable[addr] = txs
+		}
+		for addr, txs := range block {
+			blocked[addr] = txs
+		}
+	}
+	return runnable, blocked
+}
+
+// PayerCosts retrieves the total gas fee of the pending sponsored transactions
+// charged to each payer.
+func (p *TxPool) PayerCosts() map[common.Address]*big.Int {
+	costs := make(map[common.Address]*big.Int)
+	for _, subpool := range p.subpools {
+		for payer, cost := range subpool.PayerCosts() {
+			if costs[payer] == nil {
+				costs[payer] = new(big.Int)
+			}
+			costs[payer].Add(costs[payer], cost)
+		}
+	}
+	return costs
+}
+
 // Locals retrieves the accounts currently considered local by the pool.
 func (p *TxPool) Locals() []common.Address {
 	// Retrieve the locals from each subpool and deduplicate them
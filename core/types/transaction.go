@@ -675,6 +675,14 @@ func (tx *Transaction) AsMessage(s Signer, baseFee *big.Int) (Message, error) {
 	}
 }
 
+// WithPayer returns a copy of the message whose gas fee is paid by the payer,
+// as in a sponsored transaction whose payer signature expires at expiredTime.
+func (m Message) WithPayer(payer common.Address, expiredTime uint64) Message {
+	m.payer = payer
+	m.expiredTime = expiredTime
+	return m
+}
+
 func (m Message) From() common.Address   { return m.from }
 func (m Message) To() *common.Address    { return m.to }
 func (m Message) GasPrice() *big.Int     { return m.gasPrice }
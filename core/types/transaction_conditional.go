@@ -0,0 +1,118 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MaxConditionalSlots is the maximum number of storage slots the conditions of a
+// transaction may check.
+const MaxConditionalSlots = 1000
+
+var (
+	ErrConditionalTooLarge    = fmt.Errorf("conditions check more than %d storage slots", MaxConditionalSlots)
+	ErrConditionalRange       = errors.New("invalid conditional range")
+	ErrConditionalBlockNumber = errors.New("block number condition not met")
+	ErrConditionalTimestamp   = errors.New("timestamp condition not met")
+	ErrConditionalStorage     = errors.New("storage condition not met")
+)
+
+// StorageReader is the state access needed to check the storage conditions of a
+// transaction.
+type StorageReader interface {
+	GetState(addr common.Address, hash common.Hash) common.Hash
+}
+
+// TransactionConditional is a set of conditions a transaction is only included in
+// a block under. All of the bounds are inclusive.
+type TransactionConditional struct {
+	KnownAccounts  map[common.Address]map[common.Hash]common.Hash // Expected storage slot values
+	BlockNumberMin *uint64
+	BlockNumberMax *uint64
+	TimestampMin   *uint64
+	TimestampMax   *uint64
+}
+
+// Slots returns the number of storage slots checked by the conditions.
+func (c *TransactionConditional) Slots() int {
+	var slots int
+	for _, storage := range c.KnownAccounts {
+		slots += len(storage)
+	}
+	return slots
+}
+
+// Validate checks the sanity of the conditions.
+func (c *TransactionConditional) Validate() error {
+	if c.Slots() > MaxConditionalSlots {
+		return ErrConditionalTooLarge
+	}
+	if c.BlockNumberMin != nil && c.BlockNumberMax != nil && *c.BlockNumberMin > *c.BlockNumberMax {
+		return fmt.Errorf("%w: block number min %d above max %d", ErrConditionalRange, *c.BlockNumberMin, *c.BlockNumberMax)
+	}
+	if c.TimestampMin != nil && c.TimestampMax != nil && *c.TimestampMin > *c.TimestampMax {
+		return fmt.Errorf("%w: timestamp min %d above max %d", ErrConditionalRange, *c.TimestampMin, *c.TimestampMax)
+	}
+	return nil
+}
+
+// CheckHeader checks the block number and timestamp bounds against the header of
+// the block the transaction is included in.
+func (c *TransactionConditional) CheckHeader(header *Header) error {
+	number := header.Number.Uint64()
+	if c.BlockNumberMin != nil && number < *c.BlockNumberMin {
+		return fmt.Errorf("%w: block %d below min %d", ErrConditionalBlockNumber, number, *c.BlockNumberMin)
+	}
+	if c.BlockNumberMax != nil && number > *c.BlockNumberMax {
+		return fmt.Errorf("%w: block %d above max %d", ErrConditionalBlockNumber, number, *c.BlockNumberMax)
+	}
+	if c.TimestampMin != nil && header.Time < *c.TimestampMin {
+		return fmt.Errorf("%w: timestamp %d below min %d", ErrConditionalTimestamp, header.Time, *c.TimestampMin)
+	}
+	if c.TimestampMax != nil && header.Time > *c.TimestampMax {
+		return fmt.Errorf("%w: timestamp %d above max %d", ErrConditionalTimestamp, header.Time, *c.TimestampMax)
+	}
+	return nil
+}
+
+// CheckAfter returns an error if no block after the given head can meet the block
+// number and timestamp bounds anymore.
+func (c *TransactionConditional) CheckAfter(head *Header) error {
+	if number := head.Number.Uint64() + 1; c.BlockNumberMax != nil && number > *c.BlockNumberMax {
+		return fmt.Errorf("%w: block %d above max %d", ErrConditionalBlockNumber, number, *c.BlockNumberMax)
+	}
+	if c.TimestampMax != nil && head.Time >= *c.TimestampMax {
+		return fmt.Errorf("%w: head timestamp %d not below max %d", ErrConditionalTimestamp, head.Time, *c.TimestampMax)
+	}
+	return nil
+}
+
+// CheckState checks the expected storage slot values against the given state.
+func (c *TransactionConditional) CheckState(state StorageReader) error {
+	for addr, storage := range c.KnownAccounts {
+		for slot, want := range storage {
+			if have := state.GetState(addr, slot); have != want {
+				return fmt.Errorf("%w: account %s slot %s is %s, want %s", ErrConditionalStorage, addr, slot, have, want)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type testStorage map[common.Address]map[common.Hash]common.Hash
+
+func (s testStorage) GetState(addr common.Address, slot common.Hash) common.Hash {
+	return s[addr][slot]
+}
+
+func TestTransactionConditional(t *testing.T) {
+	bound := func(v uint64) *uint64 { return &v }
+	cond := &TransactionConditional{
+		KnownAccounts:  map[common.Address]map[common.Hash]common.Hash{{0x01}: {{0x02}: {0x03}}},
+		BlockNumberMin: bound(10),
+		BlockNumberMax: bound(20),
+		TimestampMin:   bound(100),
+		TimestampMax:   bound(200),
+	}
+	if err := cond.Validate(); err != nil {
+		t.Fatalf("valid conditions rejected: %v", err)
+	}
+	invalid := &TransactionConditional{BlockNumberMin: bound(2), BlockNumberMax: bound(1)}
+	if err := invalid.Validate(); !errors.Is(err, ErrConditionalRange) {
+		t.Errorf("range error mismatch: have %v, want %v", err, ErrConditionalRange)
+	}
+	headers := []struct {
+		number, time uint64
+		err          error
+	}{
+		{10, 100, nil},
+		{20, 200, nil},
+		{9, 150, ErrConditionalBlockNumber},
+		{21, 150, ErrConditionalBlockNumber},
+		{15, 99, ErrConditionalTimestamp},
+		{15, 201, ErrConditionalTimestamp},
+	}
+	for i, tt := range headers {
+		header := &Header{Number: new(big.Int).SetUint64(tt.number), Time: tt.time}
+		if err := cond.CheckHeader(header); !errors.Is(err, tt.err) {
+			t.Errorf("header %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	heads := []struct {
+		number, time uint64
+		err          error
+	}{
+		{5, 50, nil},
+		{19, 199, nil},
+		{20, 150, ErrConditionalBlockNumber},
+		{15, 200, ErrConditionalTimestamp},
+	}
+	for i, tt := range heads {
+		head := &Header{Number: new(big.Int).SetUint64(tt.number), Time: tt.time}
+		if err := cond.CheckAfter(head); !errors.Is(err, tt.err) {
+			t.Errorf("head %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	if err := cond.CheckState(testStorage{{0x01}: {{0x02}: {0x03}}}); err != nil {
+		t.Errorf("matching storage rejected: %v", err)
+	}
+	if err := cond.CheckState(testStorage{}); !errors.Is(err, ErrConditionalStorage) {
+		t.Errorf("storage error mismatch: have %v, want %v", err, ErrConditionalStorage)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 var (
@@ -124,8 +125,11 @@ func SignNewTx(prv *ecdsa.PrivateKey, s Signer, txdata TxData) (*Transaction, er
 	return tx.WithSignature(s, sig)
 }
 
-func PayerSign(prv *ecdsa.PrivateKey, signer Signer, sender common.Address, txdata TxData) (r, s, v *big.Int, err error) {
-	payerHash := rlpHash([]interface{}{
+// payerSigningFields returns the fields signed by the payer of a sponsored
+// transaction, they commit to the sender and all the transaction fields except
+// the signatures.
+func payerSigningFields(signer Signer, sender common.Address, txdata TxData) []interface{} {
+	return []interface{}{
 		signer.ChainID(),
 		sender,
 		txdata.nonce(),
@@ -136,7 +140,22 @@ func PayerSign(prv *ecdsa.PrivateKey, signer Signer, sender common.Address, txda
 		txdata.value(),
 		txdata.data(),
 		txdata.expiredTime(),
-	})
+	}
+}
+
+// PayerHash returns the hash signed by the payer of a sponsored transaction.
+func PayerHash(signer Signer, sender common.Address, txdata TxData) common.Hash {
+	return rlpHash(payerSigningFields(signer, sender, txdata))
+}
+
+// PayerSigningData returns the RLP encoded fields whose keccak256 hash is the
+// PayerHash, for the wallets which hash the data they sign.
+func PayerSigningData(signer Signer, sender common.Address, txdata TxData) ([]byte, error) {
+	return rlp.EncodeToBytes(payerSigningFields(signer, sender, txdata))
+}
+
+func PayerSign(prv *ecdsa.PrivateKey, signer Signer, sender common.Address, txdata TxData) (r, s, v *big.Int, err error) {
+	payerHash := PayerHash(signer, sender, txdata)
 
 	sig, err := crypto.Sign(payerHash[:], prv)
 	if err != nil {
@@ -474,6 +474,37 @@ func (c *consortiumVerifyHeaders) Run(input []byte) ([]byte, error) {
 	return smcAbi.Methods[verifyHeaders].Outputs.Pack(output)
 }
 
+// EncodeDoubleSignHeader encodes the header in the format expected by the
+// consortiumVerifyHeaders precompiled contract for the header arguments.
+func EncodeDoubleSignHeader(header *types.Header, chainId *big.Int, isVenoki bool) ([]byte, error) {
+	rawAbi := rawConsortiumVerifyHeadersAbi
+	if isVenoki {
+		rawAbi = rawConsortiumVerifyHeadersV2Abi
+	}
+	return types.FromHeader(header, chainId, isVenoki).Bytes(rawAbi, getHeader)
+}
+
+// EncodeDoubleSignProof packs the 2 headers sealed by consensusAddr into the input
+// of the consortiumVerifyHeaders precompiled contract.
+func EncodeDoubleSignProof(consensusAddr common.Address, header1, header2 *types.Header, chainId *big.Int, isVenoki bool) ([]byte, error) {
+	contractIdx := VerifyHeaders
+	if isVenoki {
+		contractIdx = VerifyHeadersVenoki
+	}
+	if unmarshalledABIs[contractIdx] == nil {
+		return nil, errors.New("invalid contract index")
+	}
+	encodedHeader1, err := EncodeDoubleSignHeader(header1, chainId, isVenoki)
+	if err != nil {
+		return nil, err
+	}
+	encodedHeader2, err := EncodeDoubleSignHeader(header2, chainId, isVenoki)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalledABIs[contractIdx].Pack(verifyHeaders, consensusAddr, encodedHeader1, encodedHeader2)
+}
+
 func (c *consortiumVerifyHeaders) unpackHeader(abi abi.ABI, input []byte, isVenoki bool) (types.BlockHeader, error) {
 	if isVenoki {
 		var blochHeader types.BlockHeaderV2
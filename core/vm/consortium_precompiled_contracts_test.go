@@ -2437,3 +2437,69 @@ func Test_consortiumVerifyHeaders_unpackHeader(t *testing.T) {
 	rec := recoveredHeader.ToHeader()
 	assert.EqualValues(t, header, rec)
 }
+
+func TestEncodeDoubleSignProof(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	evm, err := newEVM(caller, statedb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainId := big1
+
+	for _, isVenoki := range []bool{false, true} {
+		var header1, header2 *types.Header
+		if isVenoki {
+			header1, header2 = mockVenokiHeader(key), mockVenokiHeader(key)
+			header2.Root = common.HexToHash("0x1234")
+			for _, header := range []*types.Header{header1, header2} {
+				header.Extra = make([]byte, extraVanity+crypto.SignatureLength)
+				signHeader(key, header, chainId, true)
+			}
+		} else {
+			if header1, header2, err = prepareHeader(chainId); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// The proof built by the monitor is accepted by the precompiled contract
+		input, err := EncodeDoubleSignProof(header1.Coinbase, header1, header2, chainId, isVenoki)
+		if err != nil {
+			t.Fatalf("venoki %t: failed to encode proof: %v", isVenoki, err)
+		}
+		evm.chainRules.IsVenoki = isVenoki
+		c := &consortiumVerifyHeaders{evm: evm, caller: AccountRef(caller), test: true}
+		result, err := c.Run(input)
+		if err != nil {
+			t.Fatalf("venoki %t: failed to run: %v", isVenoki, err)
+		}
+		if len(result) != 32 || result[31] != 1 {
+			t.Fatalf("venoki %t: proof rejected, got %x", isVenoki, result)
+		}
+
+		// The headers are decoded unchanged
+		contractIdx := VerifyHeaders
+		if isVenoki {
+			contractIdx = VerifyHeadersVenoki
+		}
+		smcAbi, _, args, err := loadMethodAndArgs(contractIdx, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if args[0].(common.Address) != header1.Coinbase {
+			t.Errorf("venoki %t: consensus address mismatch, got %x", isVenoki, args[0])
+		}
+		for i, header := range []*types.Header{header1, header2} {
+			decoded, err := c.unpackHeader(smcAbi, args[i+1].([]byte), isVenoki)
+			if err != nil {
+				t.Fatalf("venoki %t: failed to unpack header %d: %v", isVenoki, i+1, err)
+			}
+			if decoded.ToHeader().Hash() != header.Hash() {
+				t.Errorf("venoki %t: header %d mismatch", isVenoki, i+1)
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package vote
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	blsCommon "github.com/ethereum/go-ethereum/crypto/bls/common"
+)
+
+// KeyRegistry returns the BLS public key registered on-chain for the local
+// validator, the vote signer selects the matching key to sign the votes.
+type KeyRegistry interface {
+	RegisteredKey(header *types.Header) ([]byte, error)
+}
+
+// BlsKeyReader reads the BLS public key of a validator from the system contracts,
+// it is implemented by the consortium contract integrator.
+type BlsKeyReader interface {
+	GetBlsPublicKey(blockHash common.Hash, blockNumber *big.Int, validator common.Address) (blsCommon.PublicKey, error)
+}
+
+// contractKeyRegistry reads the BLS public key registered in the profile contract.
+type contractKeyRegistry struct {
+	contract  BlsKeyReader
+	validator func() common.Address
+}
+
+// NewContractKeyRegistry creates a key registry reading the BLS public key of
+// the validator from the profile contract.
+func NewContractKeyRegistry(contract BlsKeyReader, validator func() common.Address) KeyRegistry {
+	return &contractKeyRegistry{
+		contract:  contract,
+		validator: validator,
+	}
+}
+
+// RegisteredKey returns the BLS public key of the validator in the profile
+// contract at the given block.
+func (r *contractKeyRegistry) RegisteredKey(header *types.Header) ([]byte, error) {
+	key, err := r.contract.GetBlsPublicKey(header.Hash(), header.Number, r.validator())
+	if err != nil {
+		return nil, err
+	}
+	return key.Marshal(), nil
+}
@@ -0,0 +1,265 @@
+package vote
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/bls"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// The remote signer speaks the Web3Signer eth2 signing API: the same endpoints,
+// request and response bodies, content negotiation and status codes. The votes
+// are sent with their own signing type, so the signer must know how to sign it,
+// e.g. Web3Signer with a plugin or a signing service in front of an HSM.
+//
+//	GET  /api/v1/eth2/publicKeys         hex encoded BLS public keys held by the signer
+//	POST /api/v1/eth2/sign/<public key>  signs the RemoteSignRequest, answers with a
+//	                                     RemoteSignResponse or the plain hex signature,
+//	                                     404 for unknown keys and 412 when refused by
+//	                                     slashing protection
+const (
+	// remoteSignerPublicKeysPath lists the BLS public keys held by the remote signer
+	remoteSignerPublicKeysPath = "/api/v1/eth2/publicKeys"
+
+	// remoteSignerSignPath is the signing endpoint of the remote signer, followed
+	// by the hex encoded BLS public key to sign with
+	remoteSignerSignPath = "/api/v1/eth2/sign/"
+
+	// RemoteSignerVoteType is the signing type of the finality votes sent to the
+	// remote signer
+	RemoteSignerVoteType = "RONIN_FINALITY_VOTE"
+
+	// maxRemoteSignerResponseSize is the maximum size of the remote signer responses
+	maxRemoteSignerResponseSize = 1024 * 1024
+)
+
+// RemoteSignerConfig is the configuration of the remote signer holding the BLS
+// key used for the finality votes.
+type RemoteSignerConfig struct {
+	URL       string        // Base URL of the remote signer
+	PublicKey string        // Hex encoded BLS public key to sign with, the first key of the signer if empty
+	TLSCert   string        // Path to the TLS client certificate
+	TLSKey    string        // Path to the TLS client private key
+	TLSCA     string        // Path to the CA certificate verifying the remote signer, system roots if empty
+	Timeout   time.Duration // Timeout of a request to the remote signer
+}
+
+// RemoteSignRequest is the body of the signing request sent to the remote
+// signer. As in the Web3Signer requests, the signing root comes with the signed
+// object under the lower case signing type, so the remote signer can check the
+// root and run its own slashing protection.
+//
+//	{
+//	  "type": "RONIN_FINALITY_VOTE",
+//	  "signingRoot": "0x...",
+//	  "ronin_finality_vote": {
+//	    "target_number": "100",
+//	    "target_hash": "0x..."
+//	  }
+//	}
+type RemoteSignRequest struct {
+	Type        string          `json:"type"`
+	SigningRoot common.Hash     `json:"signingRoot"`
+	Vote        *RemoteSignVote `json:"ronin_finality_vote,omitempty"`
+}
+
+// RemoteSignVote is the vote data, with the number encoded as a decimal string
+// like the Web3Signer uint64 fields.
+type RemoteSignVote struct {
+	TargetNumber string      `json:"target_number"`
+	TargetHash   common.Hash `json:"target_hash"`
+}
+
+// RemoteSignResponse is the JSON response of the remote signer, the signature
+// may also be returned as plain text.
+type RemoteSignResponse struct {
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// remoteSigner signs the vote data hashes with a BLS key held by a remote signer.
+type remoteSigner struct {
+	url     string
+	client  *http.Client
+	timeout time.Duration
+}
+
+func newRemoteSigner(config *RemoteSignerConfig) (*remoteSigner, error) {
+	tlsConfig, err := config.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = voteSignerTimeout
+	}
+	return &remoteSigner{
+		url: strings.TrimSuffix(config.URL, "/"),
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+		},
+		timeout: timeout,
+	}, nil
+}
+
+// tlsConfig loads the TLS client certificate and the CA certificate, it returns
+// nil when none is configured.
+func (config *RemoteSignerConfig) tlsConfig() (*tls.Config, error) {
+	if config.TLSCert == "" && config.TLSKey == "" && config.TLSCA == "" {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.TLSCert != "" || config.TLSKey != "" {
+		if config.TLSCert == "" || config.TLSKey == "" {
+			return nil, errors.New("both the TLS client certificate and key of the remote signer are required")
+		}
+		cert, err := tls.LoadX509KeyPair(config.TLSCert, config.TLSKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not load the remote signer TLS client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if config.TLSCA != "" {
+		ca, err := os.ReadFile(config.TLSCA)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read the remote signer CA certificate")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.New("no certificate found in the remote signer CA file")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// publicKeys fetches the BLS public keys held by the remote signer.
+func (s *remoteSigner) publicKeys(ctx context.Context) ([][params.BLSPubkeyLength]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url+remoteSignerPublicKeysPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	body, _, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	var encoded []hexutil.Bytes
+	if err := json.Unmarshal(body, &encoded); err != nil {
+		return nil, errors.Wrap(err, "invalid public keys response from remote signer")
+	}
+	pubKeys := make([][params.BLSPubkeyLength]byte, 0, len(encoded))
+	for _, key := range encoded {
+		if len(key) != params.BLSPubkeyLength {
+			return nil, fmt.Errorf("invalid public key length %d from remote signer", len(key))
+		}
+		var pubKey [params.BLSPubkeyLength]byte
+		copy(pubKey[:], key)
+		pubKeys = append(pubKeys, pubKey)
+	}
+	return pubKeys, nil
+}
+
+// sign requests the remote signer to sign the vote data hash with the public
+// key, the vote is attached to the request.
+func (s *remoteSigner) sign(ctx context.Context, pubKey []byte, vote *types.VoteData) (bls.Signature, error) {
+	signingRoot := vote.Hash()
+	request := &RemoteSignRequest{
+		Type:        RemoteSignerVoteType,
+		SigningRoot: signingRoot,
+		Vote: &RemoteSignVote{
+			TargetNumber: strconv.FormatUint(vote.TargetNumber, 10),
+			TargetHash:   vote.TargetHash,
+		},
+	}
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url+remoteSignerSignPath+hexutil.Encode(pubKey), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	body, contentType, err := s.do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// The remote signer answers with a JSON object or the plain hex encoded
+	// signature depending on the accepted content type, handle both
+	var signature []byte
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "application/json" {
+		var resp RemoteSignResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, errors.Wrap(err, "invalid signing response from remote signer")
+		}
+		signature = resp.Signature
+	} else {
+		if signature, err = hexutil.Decode(strings.TrimSpace(string(body))); err != nil {
+			return nil, errors.Wrap(err, "invalid signing response from remote signer")
+		}
+	}
+	sig, err := bls.SignatureFromBytes(signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid signature from remote signer")
+	}
+	// Never trust the remote signer, an invalid signature would get the vote
+	// rejected by the other validators
+	blsPubKey, err := bls.PublicKeyFromBytes(pubKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert public key from bytes to bls failed")
+	}
+	if !sig.Verify(blsPubKey, signingRoot[:]) {
+		return nil, errors.New("invalid signature from remote signer")
+	}
+	return sig, nil
+}
+
+// do sends the request and returns the response body and content type, the non
+// 200 statuses are reported as errors.
+func (s *remoteSigner) do(req *http.Request) ([]byte, string, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		var netErr net.Error
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return nil, "", errors.Wrap(errVoteSignerTimeout, err.Error())
+		}
+		return nil, "", errors.Wrap(err, "remote signer request failed")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteSignerResponseSize))
+	if err != nil {
+		return nil, "", errors.Wrap(err, "could not read remote signer response")
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, resp.Header.Get("Content-Type"), nil
+	case http.StatusNotFound:
+		return nil, "", errors.New("remote signer: public key not found")
+	case http.StatusPreconditionFailed:
+		return nil, "", errors.New("remote signer: signing refused by slashing protection")
+	default:
+		return nil, "", fmt.Errorf("remote signer: unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+}
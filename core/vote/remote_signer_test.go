@@ -0,0 +1,233 @@
+package vote
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/bls"
+)
+
+// testRemoteSigner is a local stand-in for a remote signer implementing the
+// finality vote signing API, holding a single BLS key.
+type testRemoteSigner struct {
+	*httptest.Server
+	secretKey bls.SecretKey
+
+	lock      sync.Mutex
+	delay     time.Duration
+	plainText bool
+	requests  []*RemoteSignRequest
+}
+
+func (s *testRemoteSigner) set(delay time.Duration, plainText bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.delay, s.plainText = delay, plainText
+}
+
+func newTestRemoteSigner(t *testing.T) *testRemoteSigner {
+	secretKey, err := bls.RandKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := &testRemoteSigner{secretKey: secretKey}
+	pubKey := hexutil.Encode(secretKey.PublicKey().Marshal())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(remoteSignerPublicKeysPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]string{pubKey})
+	})
+	mux.HandleFunc(remoteSignerSignPath, func(w http.ResponseWriter, r *http.Request) {
+		signer.lock.Lock()
+		delay, plainText := signer.delay, signer.plainText
+		signer.lock.Unlock()
+
+		time.Sleep(delay)
+		if strings.TrimPrefix(r.URL.Path, remoteSignerSignPath) != pubKey {
+			http.Error(w, "unknown public key", http.StatusNotFound)
+			return
+		}
+		var req RemoteSignRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		signer.lock.Lock()
+		signer.requests = append(signer.requests, &req)
+		signer.lock.Unlock()
+
+		signature := hexutil.Encode(secretKey.Sign(req.SigningRoot[:]).Marshal())
+		if plainText {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(signature))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"signature": signature})
+	})
+	signer.Server = httptest.NewUnstartedServer(mux)
+	return signer
+}
+
+// testCertificates creates a CA, a server certificate for the loopback address
+// and a client certificate signed by the CA, written in PEM files to dir.
+func testCertificates(t *testing.T, dir string) (caPool *x509.CertPool, server tls.Certificate) {
+	newKey := func() *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+	writePEM := func(name, kind string, data []byte) {
+		if err := os.WriteFile(filepath.Join(dir, name), pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: data}), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	caKey := newKey()
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, _ := x509.ParseCertificate(caDER)
+	caPool = x509.NewCertPool()
+	caPool.AddCert(ca)
+	writePEM("ca.pem", "CERTIFICATE", caDER)
+
+	issue := func(serial int64, usage x509.ExtKeyUsage, name string) {
+		key := newKey()
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writePEM(name+".pem", "CERTIFICATE", der)
+		writePEM(name+".key", "EC PRIVATE KEY", keyDER)
+	}
+	issue(2, x509.ExtKeyUsageServerAuth, "server")
+	issue(3, x509.ExtKeyUsageClientAuth, "client")
+
+	server, err = tls.LoadX509KeyPair(filepath.Join(dir, "server.pem"), filepath.Join(dir, "server.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return caPool, server
+}
+
+func TestRemoteVoteSigner(t *testing.T) {
+	dir := t.TempDir()
+	caPool, serverCert := testCertificates(t, dir)
+
+	remote := newTestRemoteSigner(t)
+	remote.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    caPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	remote.StartTLS()
+	defer remote.Close()
+
+	config := &RemoteSignerConfig{
+		URL:     remote.URL,
+		TLSCert: filepath.Join(dir, "client.pem"),
+		TLSKey:  filepath.Join(dir, "client.key"),
+		TLSCA:   filepath.Join(dir, "ca.pem"),
+		Timeout: 500 * time.Millisecond,
+	}
+	signer, err := NewRemoteVoteSigner(config)
+	if err != nil {
+		t.Fatalf("Failed to create remote vote signer, err %s", err)
+	}
+	pubKey := remote.secretKey.PublicKey()
+	if types.BLSPublicKey(signer.pubKey) != types.BLSPublicKey(pubKey.Marshal()) {
+		t.Fatalf("Expect public key %x, got %x", pubKey.Marshal(), signer.pubKey)
+	}
+
+	for _, plainText := range []bool{false, true} {
+		remote.set(0, plainText)
+		vote := &types.VoteEnvelope{
+			RawVoteEnvelope: types.RawVoteEnvelope{
+				Data: &types.VoteData{TargetNumber: 10, TargetHash: common.Hash{0x1}},
+			},
+		}
+		if err := signer.SignVote(vote); err != nil {
+			t.Fatalf("Failed to sign vote, plain text %t, err %s", plainText, err)
+		}
+		if err := vote.Verify(); err != nil {
+			t.Fatalf("Invalid vote signature, plain text %t, err %s", plainText, err)
+		}
+	}
+	remote.lock.Lock()
+	last := remote.requests[len(remote.requests)-1]
+	remote.lock.Unlock()
+	if last.Type != RemoteSignerVoteType || last.Vote == nil || last.Vote.TargetNumber != "10" || last.Vote.TargetHash != (common.Hash{0x1}) {
+		t.Fatalf("Unexpected signing request %+v", last)
+	}
+
+	// Requests over the timeout fail
+	remote.set(time.Second, false)
+	vote := &types.VoteEnvelope{
+		RawVoteEnvelope: types.RawVoteEnvelope{
+			Data: &types.VoteData{TargetNumber: 11, TargetHash: common.Hash{0x2}},
+		},
+	}
+	if err := signer.SignVote(vote); !errors.Is(err, errVoteSignerTimeout) {
+		t.Fatalf("Expect signing to time out, got %v", err)
+	}
+	remote.set(0, false)
+
+	// An unknown public key is rejected
+	config.PublicKey = hexutil.Encode(make([]byte, 48))
+	if _, err := NewRemoteVoteSigner(config); err == nil {
+		t.Fatal("Expect unknown public key to be rejected")
+	}
+	config.PublicKey = ""
+
+	// The remote signer rejects the clients without certificate
+	config.TLSCert, config.TLSKey = "", ""
+	if _, err := NewRemoteVoteSigner(config); err == nil {
+		t.Fatal("Expect client without certificate to be rejected")
+	}
+}
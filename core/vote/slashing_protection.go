@@ -0,0 +1,211 @@
+package vote
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// InterchangeFormatVersion is the version of the slashing protection
+	// interchange format
+	InterchangeFormatVersion = "1"
+
+	// slashingProtectionHistory is the number of blocks below the latest signed
+	// vote whose signed votes are kept, older ones are never signable again so
+	// they are pruned
+	slashingProtectionHistory = 256
+)
+
+var (
+	// ErrDoubleVote is returned when the vote targets a block at the same height
+	// as a signed vote but with a different hash
+	ErrDoubleVote = errors.New("conflicting vote at the same height is already signed")
+
+	// ErrSurroundVote is returned when the vote targets a block lower than the
+	// latest signed vote. A finality vote only carries its target, so any vote
+	// below the latest signed one may surround or be surrounded by it.
+	ErrSurroundVote = errors.New("vote is below the latest signed vote")
+
+	slashingProtectionRefusedCounter = metrics.NewRegisteredCounter("votesSigner/slashingProtection/refused", nil)
+)
+
+// SlashingProtection records the finality votes signed by the local BLS keys
+// in the database, and refuses to sign votes conflicting with them. The records
+// can be moved to another node with the interchange format, so a failover node
+// never signs a vote conflicting with the votes of the previous one.
+type SlashingProtection struct {
+	db   ethdb.KeyValueStore
+	lock sync.Mutex
+}
+
+func NewSlashingProtection(db ethdb.KeyValueStore) *SlashingProtection {
+	return &SlashingProtection{db: db}
+}
+
+// CheckAndRecord checks the vote against the votes signed by the BLS key and
+// records it when it is safe to sign. Signing the same vote again is allowed.
+func (p *SlashingProtection) CheckAndRecord(publicKey types.BLSPublicKey, vote *types.VoteData) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if latest := rawdb.ReadLatestSignedVote(p.db, publicKey[:]); latest != nil {
+		if vote.TargetNumber < latest.TargetNumber {
+			slashingProtectionRefusedCounter.Inc(1)
+			return fmt.Errorf("%w: target %d, latest %d", ErrSurroundVote, vote.TargetNumber, latest.TargetNumber)
+		}
+		if vote.TargetNumber == latest.TargetNumber {
+			if vote.TargetHash != latest.TargetHash {
+				slashingProtectionRefusedCounter.Inc(1)
+				return fmt.Errorf("%w: target %d, signed hash %s", ErrDoubleVote, vote.TargetNumber, latest.TargetHash)
+			}
+			return nil
+		}
+	}
+
+	signed := &rawdb.SignedVote{TargetNumber: vote.TargetNumber, TargetHash: vote.TargetHash}
+	batch := p.db.NewBatch()
+	rawdb.WriteSignedVote(batch, publicKey[:], signed)
+	rawdb.WriteLatestSignedVote(batch, publicKey[:], signed)
+	if vote.TargetNumber > slashingProtectionHistory {
+		for _, old := range rawdb.ReadSignedVotes(p.db, publicKey[:]) {
+			if old.TargetNumber >= vote.TargetNumber-slashingProtectionHistory {
+				break
+			}
+			rawdb.DeleteSignedVote(batch, publicKey[:], old.TargetNumber)
+		}
+	}
+	return batch.Write()
+}
+
+// Interchange is the slashing protection interchange format, it is adapted from
+// EIP-3076 to the finality votes, which only carry a target block:
+//
+//	{
+//	  "metadata": {
+//	    "interchange_format_version": "1",
+//	    "genesis_hash": "0x..."
+//	  },
+//	  "data": [
+//	    {
+//	      "pubkey": "0x...",
+//	      "signed_votes": [
+//	        {
+//	          "target_number": "100",
+//	          "target_hash": "0x...",
+//	          "signing_root": "0x..."
+//	        }
+//	      ]
+//	    }
+//	  ]
+//	}
+//
+// The block numbers are decimal strings and signing_root is the hash signed by
+// the BLS key, it is optional on import.
+type Interchange struct {
+	Metadata InterchangeMetadata  `json:"metadata"`
+	Data     []*InterchangeRecord `json:"data"`
+}
+
+type InterchangeMetadata struct {
+	InterchangeFormatVersion string      `json:"interchange_format_version"`
+	GenesisHash              common.Hash `json:"genesis_hash"`
+}
+
+type InterchangeRecord struct {
+	PublicKey   hexutil.Bytes      `json:"pubkey"`
+	SignedVotes []*InterchangeVote `json:"signed_votes"`
+}
+
+type InterchangeVote struct {
+	TargetNumber string       `json:"target_number"`
+	TargetHash   common.Hash  `json:"target_hash"`
+	SigningRoot  *common.Hash `json:"signing_root,omitempty"`
+}
+
+// Export returns the votes recorded for all BLS keys in the interchange format.
+func (p *SlashingProtection) Export(genesisHash common.Hash) *Interchange {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	interchange := &Interchange{
+		Metadata: InterchangeMetadata{
+			InterchangeFormatVersion: InterchangeFormatVersion,
+			GenesisHash:              genesisHash,
+		},
+		Data: make([]*InterchangeRecord, 0),
+	}
+	for _, publicKey := range rawdb.ReadSignedVotePublicKeys(p.db) {
+		record := &InterchangeRecord{PublicKey: publicKey}
+		for _, vote := range rawdb.ReadSignedVotes(p.db, publicKey) {
+			signingRoot := (&types.VoteData{TargetNumber: vote.TargetNumber, TargetHash: vote.TargetHash}).Hash()
+			record.SignedVotes = append(record.SignedVotes, &InterchangeVote{
+				TargetNumber: strconv.FormatUint(vote.TargetNumber, 10),
+				TargetHash:   vote.TargetHash,
+				SigningRoot:  &signingRoot,
+			})
+		}
+		interchange.Data = append(interchange.Data, record)
+	}
+	return interchange
+}
+
+// Import merges the votes in the interchange format into the local records. The
+// existing records are kept on conflict, as both votes are already signed and
+// any later vote must be above them anyway.
+func (p *SlashingProtection) Import(interchange *Interchange, genesisHash common.Hash) error {
+	if interchange.Metadata.InterchangeFormatVersion != InterchangeFormatVersion {
+		return fmt.Errorf("unsupported interchange format version %q", interchange.Metadata.InterchangeFormatVersion)
+	}
+	if interchange.Metadata.GenesisHash != genesisHash {
+		return fmt.Errorf("genesis hash mismatch, local %s, interchange %s", genesisHash, interchange.Metadata.GenesisHash)
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	batch := p.db.NewBatch()
+	for _, record := range interchange.Data {
+		if len(record.PublicKey) != len(types.BLSPublicKey{}) {
+			return fmt.Errorf("invalid BLS public key %s", record.PublicKey)
+		}
+		latest := rawdb.ReadLatestSignedVote(p.db, record.PublicKey)
+		for _, vote := range record.SignedVotes {
+			number, err := strconv.ParseUint(vote.TargetNumber, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid target number %q: %v", vote.TargetNumber, err)
+			}
+			signed := &rawdb.SignedVote{TargetNumber: number, TargetHash: vote.TargetHash}
+			if vote.SigningRoot != nil {
+				signingRoot := (&types.VoteData{TargetNumber: number, TargetHash: vote.TargetHash}).Hash()
+				if signingRoot != *vote.SigningRoot {
+					return fmt.Errorf("signing root mismatch at target %d", number)
+				}
+			}
+			if hash, ok := rawdb.ReadSignedVote(p.db, record.PublicKey, number); ok {
+				if hash != vote.TargetHash {
+					log.Warn("Conflicting signed vote in interchange, keeping the local one",
+						"pubkey", record.PublicKey, "number", number, "local", hash, "imported", vote.TargetHash)
+				}
+				continue
+			}
+			rawdb.WriteSignedVote(batch, record.PublicKey, signed)
+			if latest == nil || number > latest.TargetNumber {
+				latest = signed
+			}
+		}
+		if latest != nil {
+			rawdb.WriteLatestSignedVote(batch, record.PublicKey, latest)
+		}
+	}
+	return batch.Write()
+}
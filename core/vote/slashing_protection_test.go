@@ -0,0 +1,64 @@
+package vote
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestSlashingProtection(t *testing.T) {
+	var (
+		db         = rawdb.NewMemoryDatabase()
+		protection = NewSlashingProtection(db)
+		publicKey  = types.BLSPublicKey{0x1}
+	)
+
+	if err := protection.CheckAndRecord(publicKey, &types.VoteData{TargetNumber: 10, TargetHash: common.Hash{0x1}}); err != nil {
+		t.Fatalf("Failed to sign first vote, err %s", err)
+	}
+	// Signing the same vote again is allowed
+	if err := protection.CheckAndRecord(publicKey, &types.VoteData{TargetNumber: 10, TargetHash: common.Hash{0x1}}); err != nil {
+		t.Fatalf("Failed to sign the same vote again, err %s", err)
+	}
+	if err := protection.CheckAndRecord(publicKey, &types.VoteData{TargetNumber: 10, TargetHash: common.Hash{0x2}}); !errors.Is(err, ErrDoubleVote) {
+		t.Fatalf("Expect error %s, got %v", ErrDoubleVote, err)
+	}
+	if err := protection.CheckAndRecord(publicKey, &types.VoteData{TargetNumber: 9, TargetHash: common.Hash{0x3}}); !errors.Is(err, ErrSurroundVote) {
+		t.Fatalf("Expect error %s, got %v", ErrSurroundVote, err)
+	}
+	if err := protection.CheckAndRecord(publicKey, &types.VoteData{TargetNumber: 11, TargetHash: common.Hash{0x4}}); err != nil {
+		t.Fatalf("Failed to sign higher vote, err %s", err)
+	}
+
+	// The records are moved to another node through the interchange format
+	genesisHash := common.Hash{0xff}
+	data, err := json.Marshal(protection.Export(genesisHash))
+	if err != nil {
+		t.Fatalf("Failed to encode interchange, err %s", err)
+	}
+	interchange := new(Interchange)
+	if err := json.Unmarshal(data, interchange); err != nil {
+		t.Fatalf("Failed to decode interchange, err %s", err)
+	}
+	if err := NewSlashingProtection(rawdb.NewMemoryDatabase()).Import(interchange, common.Hash{0xfe}); err == nil {
+		t.Fatal("Expect import with mismatched genesis hash to fail")
+	}
+
+	imported := NewSlashingProtection(rawdb.NewMemoryDatabase())
+	if err := imported.Import(interchange, genesisHash); err != nil {
+		t.Fatalf("Failed to import interchange, err %s", err)
+	}
+	if err := imported.CheckAndRecord(publicKey, &types.VoteData{TargetNumber: 11, TargetHash: common.Hash{0x5}}); !errors.Is(err, ErrDoubleVote) {
+		t.Fatalf("Expect error %s after import, got %v", ErrDoubleVote, err)
+	}
+	if err := imported.CheckAndRecord(publicKey, &types.VoteData{TargetNumber: 10, TargetHash: common.Hash{0x1}}); !errors.Is(err, ErrSurroundVote) {
+		t.Fatalf("Expect error %s after import, got %v", ErrSurroundVote, err)
+	}
+	if err := imported.CheckAndRecord(publicKey, &types.VoteData{TargetNumber: 12, TargetHash: common.Hash{0x6}}); err != nil {
+		t.Fatalf("Failed to sign vote after import, err %s", err)
+	}
+}
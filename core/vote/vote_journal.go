@@ -0,0 +1,131 @@
+package vote
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// errNoActiveJournal is returned if a vote is attempted to be inserted
+// into the journal, but no such file is currently open.
+var errNoActiveJournal = errors.New("no active journal")
+
+// devNull is a WriteCloser that just discards anything written into it. Its
+// goal is to allow the vote journal to write into a fake journal when loading
+// votes on startup without printing warnings due to no file being read for write.
+type devNull struct{}
+
+func (*devNull) Write(p []byte) (n int, err error) { return len(p), nil }
+func (*devNull) Close() error                      { return nil }
+
+// voteJournal is a rotating log of finality votes with the aim of storing the
+// votes in the pool to allow them to survive node restarts.
+type voteJournal struct {
+	path   string         // Filesystem path to store the votes at
+	writer io.WriteCloser // Output stream to write new votes into
+}
+
+// newVoteJournal creates a new vote journal to
+func newVoteJournal(path string) *voteJournal {
+	return &voteJournal{
+		path: path,
+	}
+}
+
+// load parses a vote journal dump from disk, loading its contents into the
+// specified pool. The votes outside of the pool's block range are dropped by add.
+func (journal *voteJournal) load(add func(vote *types.VoteEnvelope)) error {
+	// Skip the parsing if the journal file doesn't exist at all
+	if _, err := os.Stat(journal.path); os.IsNotExist(err) {
+		return nil
+	}
+	// Open the journal for loading any past votes
+	input, err := os.Open(journal.path)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	// Temporarily discard any journal additions (don't double add on load)
+	journal.writer = new(devNull)
+	defer func() { journal.writer = nil }()
+
+	stream := rlp.NewStream(input, 0)
+	total := 0
+	for {
+		// Parse the next vote and terminate on error
+		rawVote := new(types.RawVoteEnvelope)
+		if err = stream.Decode(rawVote); err != nil {
+			if err != io.EOF {
+				log.Info("Loaded finality vote journal", "votes", total)
+				return err
+			}
+			break
+		}
+		total++
+		add(&types.VoteEnvelope{RawVoteEnvelope: *rawVote})
+	}
+	log.Info("Loaded finality vote journal", "votes", total)
+
+	return nil
+}
+
+// insert adds the specified vote to the local disk journal.
+func (journal *voteJournal) insert(vote *types.VoteEnvelope) error {
+	if journal.writer == nil {
+		return errNoActiveJournal
+	}
+	return rlp.Encode(journal.writer, vote.Raw())
+}
+
+// rotate regenerates the vote journal based on the current contents of the
+// vote pool, so the pruned votes are dropped from the journal.
+func (journal *voteJournal) rotate(votes []*types.VoteEnvelope) error {
+	// Close the current journal (if any is open)
+	if journal.writer != nil {
+		if err := journal.writer.Close(); err != nil {
+			return err
+		}
+		journal.writer = nil
+	}
+	// Generate a new journal with the contents of the current pool
+	replacement, err := os.OpenFile(journal.path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	for _, vote := range votes {
+		if err = rlp.Encode(replacement, vote.Raw()); err != nil {
+			replacement.Close()
+			return err
+		}
+	}
+	replacement.Close()
+
+	// Replace the live journal with the newly generated one
+	if err = os.Rename(journal.path+".new", journal.path); err != nil {
+		return err
+	}
+	sink, err := os.OpenFile(journal.path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	journal.writer = sink
+	log.Debug("Regenerated finality vote journal", "votes", len(votes))
+
+	return nil
+}
+
+// close flushes the vote journal contents to disk and closes the file.
+func (journal *voteJournal) close() error {
+	var err error
+
+	if journal.writer != nil {
+		err = journal.writer.Close()
+		journal.writer = nil
+	}
+	return err
+}
@@ -2,6 +2,7 @@ package vote
 
 import (
 	"encoding/hex"
+	"errors"
 
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
@@ -38,8 +39,10 @@ type VoteManager struct {
 	chainHeadCh  chan core.ChainHeadEvent
 	chainHeadSub event.Subscription
 
-	pool   *VotePool
-	signer *VoteSigner
+	pool       *VotePool
+	signer     *VoteSigner
+	protection *SlashingProtection
+	registry   KeyRegistry
 
 	engine consensus.FastFinalityPoSA
 
@@ -55,6 +58,8 @@ func NewVoteManager(
 	pool *VotePool,
 	enableSign bool,
 	blsPasswordPath, blsWalletPath string,
+	remoteSigner *RemoteSignerConfig,
+	registry KeyRegistry,
 	engine consensus.FastFinalityPoSA,
 	debug *Debug,
 ) (*VoteManager, error) {
@@ -66,19 +71,30 @@ func NewVoteManager(
 		chainconfig: chainconfig,
 		chainHeadCh: make(chan core.ChainHeadEvent, chainHeadChanSize),
 
-		pool:   pool,
-		engine: engine,
-		debug:  debug,
+		pool:     pool,
+		registry: registry,
+		engine:   engine,
+		debug:    debug,
 	}
 
 	if enableSign {
-		// Create voteSigner.
-		voteSigner, err := NewVoteSigner(blsPasswordPath, blsWalletPath)
+		// Create voteSigner, the remote signer takes precedence over the local wallet.
+		var (
+			voteSigner *VoteSigner
+			err        error
+		)
+		if remoteSigner != nil && remoteSigner.URL != "" {
+			voteSigner, err = NewRemoteVoteSigner(remoteSigner)
+		} else {
+			voteSigner, err = NewVoteSigner(blsPasswordPath, blsWalletPath)
+		}
 		if err != nil {
 			return nil, err
 		}
-		log.Info("BLS voter public key", "public key", hex.EncodeToString(voteSigner.pubKey[:]))
+		pubKey := voteSigner.PublicKey()
+		log.Info("BLS voter public key", "public key", hex.EncodeToString(pubKey[:]), "keys", len(voteSigner.PublicKeys()))
 		voteManager.signer = voteSigner
+		voteManager.protection = NewSlashingProtection(db)
 	}
 
 	// Subscribe to chain head event.
@@ -147,6 +163,14 @@ func (voteManager *VoteManager) loop() {
 				continue
 			}
 
+			// Sign with the key registered on-chain, so the rotations take effect
+			// without restarting the node
+			pubKey, err := voteManager.selectKey(curHead)
+			if err != nil {
+				log.Warn("Failed to select the registered BLS key, skip vote", "number", curHead.Number, "hash", curHead.Hash(), "err", err)
+				continue
+			}
+
 			// Vote for curBlockHeader block.
 			vote := &types.VoteData{
 				TargetNumber: curHead.Number.Uint64(),
@@ -161,12 +185,21 @@ func (voteManager *VoteManager) loop() {
 			// Put Vote into journal and VotesPool if we are active validator and allow to sign it.
 			if ok := voteManager.UnderRules(curHead); ok {
 				log.Debug("curHead is underRules for voting")
-				if err := voteManager.signer.SignVote(voteMessage); err != nil {
+				// Record the vote before signing it, so a restart after signing can
+				// never lead to a conflicting vote for the same height.
+				rawdb.WriteHighestFinalityVote(voteManager.db, curHead.Number.Uint64())
+				if err := voteManager.protection.CheckAndRecord(types.BLSPublicKey(pubKey), vote); err != nil {
+					log.Warn("Refused to sign vote by slashing protection", "err", err, "votedBlockNumber", vote.TargetNumber, "votedBlockHash", vote.TargetHash)
+					continue
+				}
+				if err := voteManager.signer.signVote(voteMessage, pubKey); err != nil {
 					log.Error("Failed to sign vote", "err", err, "votedBlockNumber", voteMessage.Data.TargetNumber, "votedBlockHash", voteMessage.Data.TargetHash, "voteMessageHash", voteMessage.Hash())
 					votesSigningErrorCounter.Inc(1)
+					if errors.Is(err, errVoteSignerTimeout) {
+						votesSigningTimeoutCounter.Inc(1)
+					}
 					continue
 				}
-				rawdb.WriteHighestFinalityVote(voteManager.db, curHead.Number.Uint64())
 
 				log.Debug("vote manager produced vote", "votedBlockNumber", voteMessage.Data.TargetNumber, "votedBlockHash", voteMessage.Data.TargetHash, "voteMessageHash", voteMessage.Hash())
 				// This is a local vote so just pass the dummy peer information
@@ -180,6 +213,34 @@ func (voteManager *VoteManager) loop() {
 	}
 }
 
+// selectKey selects the loaded BLS key registered on-chain at the header and
+// returns it, the selected key is returned when there is no registry.
+func (voteManager *VoteManager) selectKey(header *types.Header) ([params.BLSPubkeyLength]byte, error) {
+	if voteManager.registry != nil {
+		registered, err := voteManager.registry.RegisteredKey(header)
+		if err != nil {
+			return [params.BLSPubkeyLength]byte{}, err
+		}
+		if _, err := voteManager.signer.Select(registered); err != nil {
+			return [params.BLSPubkeyLength]byte{}, err
+		}
+	}
+	return voteManager.signer.PublicKey(), nil
+}
+
+// ReloadKeys reloads the BLS keys of the vote signer, so the keys rotated
+// on-chain can be added without restarting the node. It returns the loaded
+// public keys.
+func (voteManager *VoteManager) ReloadKeys() ([][params.BLSPubkeyLength]byte, error) {
+	if voteManager.signer == nil {
+		return nil, errors.New("finality vote signing is disabled")
+	}
+	if err := voteManager.signer.Reload(); err != nil {
+		return nil, err
+	}
+	return voteManager.signer.PublicKeys(), nil
+}
+
 // UnderRules checks if the produced header under the following rules:
 // A validator must not publish two distinct votes for the same height. (Rule 1)
 // Validators always vote for their canonical chain’s latest block. (Rule 2)
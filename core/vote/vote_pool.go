@@ -72,6 +72,10 @@ type VotePool struct {
 	justifiedBlockNumber uint64
 
 	dropPeer peerDropFn
+
+	journal   *voteJournal  // Journal of votes to back up across restarts, nil if disabled
+	rejournal time.Duration // Time interval to regenerate the vote journal
+	quit      chan struct{}
 }
 
 type votesPriorityQueue []*types.VoteData
@@ -81,6 +85,8 @@ func NewVotePool(
 	engine consensus.FastFinalityPoSA,
 	maxCurVoteAmountPerBlock int,
 	dropPeer peerDropFn,
+	journalPath string,
+	rejournal time.Duration,
 ) *VotePool {
 	votePool := &VotePool{
 		chain:                    chain,
@@ -95,6 +101,23 @@ func NewVotePool(
 		numFutureVotePerPeer:     make(map[string]uint64),
 		lastFutureVoteBlock:      make(map[string]uint64),
 		originatedFrom:           make(map[common.Hash]string),
+		quit:                     make(chan struct{}),
+	}
+
+	// If journaling is enabled, load the votes from disk
+	if journalPath != "" {
+		if rejournal < time.Second {
+			log.Warn("Sanitizing invalid vote journal time", "provided", rejournal, "updated", time.Second)
+			rejournal = time.Second
+		}
+		votePool.rejournal = rejournal
+		votePool.journal = newVoteJournal(journalPath)
+		if err := votePool.journal.load(votePool.putJournaledVote); err != nil {
+			log.Warn("Failed to load vote journal", "err", err)
+		}
+		if err := votePool.journal.rotate(votePool.allVotes()); err != nil {
+			log.Warn("Failed to rotate vote journal", "err", err)
+		}
 	}
 
 	// Subscribe events from blockchain and start the main event loop.
@@ -106,6 +129,14 @@ func NewVotePool(
 
 // loop is the vote pool's main even loop, waiting for and reacting to outside blockchain events and votes channel event.
 func (pool *VotePool) loop() {
+	var journalC <-chan time.Time
+	if pool.journal != nil {
+		journal := time.NewTicker(pool.rejournal)
+		defer journal.Stop()
+		journalC = journal.C
+	}
+	defer pool.closeJournal()
+
 	for {
 		select {
 		// Handle ChainHeadEvent.
@@ -122,6 +153,14 @@ func (pool *VotePool) loop() {
 			}
 		case <-pool.chainHeadSub.Err():
 			return
+		case <-pool.quit:
+			return
+
+		// Regenerate the vote journal to drop the pruned votes
+		case <-journalC:
+			if err := pool.journal.rotate(pool.allVotes()); err != nil {
+				log.Warn("Failed to rotate vote journal", "err", err)
+			}
 
 		// Handle votes channel and put the vote into vote pool.
 		case vote := <-pool.votesCh:
@@ -133,8 +172,49 @@ func (pool *VotePool) loop() {
 	}
 }
 
+// PutVote queues a vote for the main loop, it is dropped once the pool is stopped.
 func (pool *VotePool) PutVote(peer string, vote *types.VoteEnvelope) {
-	pool.votesCh <- &voteWithPeer{vote: vote, peer: peer}
+	select {
+	case pool.votesCh <- &voteWithPeer{vote: vote, peer: peer}:
+	case <-pool.quit:
+	}
+}
+
+// Stop terminates the vote pool main loop and closes the vote journal.
+func (pool *VotePool) Stop() {
+	pool.chainHeadSub.Unsubscribe()
+	close(pool.quit)
+}
+
+// putJournaledVote adds a vote loaded from the journal, the journaled votes
+// have no sender so no peer is dropped on failure.
+func (pool *VotePool) putJournaledVote(vote *types.VoteEnvelope) {
+	pool.putIntoVotePool(&voteWithPeer{vote: vote})
+}
+
+// allVotes returns all current and future votes in the pool.
+func (pool *VotePool) allVotes() []*types.VoteEnvelope {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	votes := make([]*types.VoteEnvelope, 0)
+	for _, voteMap := range []map[common.Hash]*VoteBox{pool.curVotes, pool.futureVotes} {
+		for _, voteBox := range voteMap {
+			for _, vote := range voteBox.voteMessages {
+				votes = append(votes, vote.vote)
+			}
+		}
+	}
+	return votes
+}
+
+func (pool *VotePool) closeJournal() {
+	if pool.journal == nil {
+		return
+	}
+	if err := pool.journal.close(); err != nil {
+		log.Warn("Failed to close vote journal", "err", err)
+	}
 }
 
 // putIntoVotePool returns false when the vote fails critical verification
@@ -221,6 +301,11 @@ func (pool *VotePool) putIntoVotePool(voteWithPeerInfo *voteWithPeer) bool {
 
 	pool.putVote(votes, votesPq, voteWithPeerInfo, voteData, voteHash, isFutureVote)
 	pool.originatedFrom[voteHash] = peer
+	if pool.journal != nil {
+		if err := pool.journal.insert(vote); err != nil {
+			log.Warn("Failed to journal finality vote", "hash", voteHash, "err", err)
+		}
+	}
 	// Update the peer feature vote counter, and its last block for pruning
 	if isFutureVote {
 		pool.numFutureVotePerPeer[peer]++
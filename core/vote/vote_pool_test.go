@@ -108,7 +108,7 @@ func testVotePool(t *testing.T, isValidRules bool) {
 	mockEngine := &mockPOSA{}
 
 	// Create vote pool
-	votePool := NewVotePool(chain, mockEngine, 22, nil)
+	votePool := NewVotePool(chain, mockEngine, 22, nil, "", 0)
 
 	// Create vote manager
 	// Create a temporary file for the votes journal
@@ -127,9 +127,9 @@ func testVotePool(t *testing.T, isValidRules bool) {
 		voteManager *VoteManager
 	)
 	if isValidRules {
-		voteManager, err = NewVoteManager(newTestBackend(), db, params.TestChainConfig, chain, votePool, true, walletPasswordDir, walletDir, mockEngine, nil)
+		voteManager, err = NewVoteManager(newTestBackend(), db, params.TestChainConfig, chain, votePool, true, walletPasswordDir, walletDir, nil, nil, mockEngine, nil)
 	} else {
-		voteManager, err = NewVoteManager(newTestBackend(), db, params.TestChainConfig, chain, votePool, true, walletPasswordDir, walletDir, mockEngine, &Debug{ValidateRule: func(header *types.Header) error {
+		voteManager, err = NewVoteManager(newTestBackend(), db, params.TestChainConfig, chain, votePool, true, walletPasswordDir, walletDir, nil, nil, mockEngine, &Debug{ValidateRule: func(header *types.Header) error {
 			return errors.New("mock error")
 		}})
 	}
@@ -407,7 +407,7 @@ func TestVotePoolDosProtection(t *testing.T) {
 	mockEngine := &mockPOSA{}
 
 	// Create vote pool
-	votePool := NewVotePool(chain, mockEngine, 22, nil)
+	votePool := NewVotePool(chain, mockEngine, 22, nil, "", 0)
 
 	for i := 0; i < maxFutureVotePerPeer; i++ {
 		vote := generateVote(1, common.BigToHash(big.NewInt(int64(i+1))), secretKey)
@@ -506,7 +506,7 @@ func testVotePoolPruneFutureVoteCounter(t *testing.T, scheme string) {
 
 	// Create vote pool
 	voteNum := lowerLimitOfVoteBlockNumber
-	votePool := NewVotePool(chain, mockEngine, voteNum, nil)
+	votePool := NewVotePool(chain, mockEngine, voteNum, nil, "", 0)
 	secretKey, err := bls.RandKey()
 	if err != nil {
 		t.Fatalf("Failed to create secret key, err %s", err)
@@ -606,7 +606,7 @@ func TestVotePoolWrongTargetNumber(t *testing.T) {
 	mockEngine := &mockPOSAv2{}
 
 	// Create vote pool
-	votePool := NewVotePool(chain, mockEngine, 22, nil)
+	votePool := NewVotePool(chain, mockEngine, 22, nil, "", 0)
 
 	// bs[0] is the block 1 so the target block number must be 1.
 	// Here we provide wrong target number 0
@@ -618,3 +618,78 @@ func TestVotePoolWrongTargetNumber(t *testing.T) {
 		t.Fatalf("Current vote length, expect %d have %d", 0, len(votePool.curVotes))
 	}
 }
+
+func TestVotePoolPutVoteAfterStop(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	gspec := &core.Genesis{
+		Config:  params.TestChainConfig,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	chain, _ := core.NewBlockChain(db, nil, gspec, nil, ethash.NewFullFaker(), vm.Config{}, nil, nil)
+	defer chain.Stop()
+
+	votePool := NewVotePool(chain, &mockPOSAv2{}, 22, nil, "", 0)
+	votePool.Stop()
+
+	// The votes beyond the buffer must not block once the main loop is gone
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i <= voteBufferForPut; i++ {
+			votePool.PutVote("AAAA", &types.VoteEnvelope{RawVoteEnvelope: types.RawVoteEnvelope{Data: &types.VoteData{TargetNumber: uint64(i)}}})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("PutVote blocked after the vote pool was stopped")
+	}
+}
+
+func TestVoteJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "votes.rlp")
+	journal := newVoteJournal(path)
+
+	newVote := func(number uint64) *types.VoteEnvelope {
+		return &types.VoteEnvelope{
+			RawVoteEnvelope: types.RawVoteEnvelope{
+				PublicKey: types.BLSPublicKey{byte(number)},
+				Data: &types.VoteData{
+					TargetNumber: number,
+					TargetHash:   common.Hash{byte(number)},
+				},
+			},
+		}
+	}
+	// The first rotation drops the votes which are not in the pool anymore
+	if err := journal.rotate([]*types.VoteEnvelope{newVote(1)}); err != nil {
+		t.Fatalf("Failed to rotate journal, err %s", err)
+	}
+	if err := journal.insert(newVote(2)); err != nil {
+		t.Fatalf("Failed to insert vote, err %s", err)
+	}
+	if err := journal.rotate([]*types.VoteEnvelope{newVote(2)}); err != nil {
+		t.Fatalf("Failed to rotate journal, err %s", err)
+	}
+	if err := journal.insert(newVote(3)); err != nil {
+		t.Fatalf("Failed to insert vote, err %s", err)
+	}
+	if err := journal.close(); err != nil {
+		t.Fatalf("Failed to close journal, err %s", err)
+	}
+
+	var loaded []*types.VoteEnvelope
+	if err := newVoteJournal(path).load(func(vote *types.VoteEnvelope) {
+		loaded = append(loaded, vote)
+	}); err != nil {
+		t.Fatalf("Failed to load journal, err %s", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("Expect 2 journaled votes, got %d", len(loaded))
+	}
+	for i, vote := range loaded {
+		if vote.Hash() != newVote(uint64(i+2)).Hash() {
+			t.Fatalf("Mismatch journaled vote %d", i)
+		}
+	}
+}
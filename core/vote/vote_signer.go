@@ -1,7 +1,11 @@
 package vote
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
+	"strings"
+	"sync"
 	"time"
 
 	wallet "github.com/ethereum/go-ethereum/accounts/bls"
@@ -19,18 +23,117 @@ const (
 	voteSignerTimeout = time.Second * 5
 )
 
-var votesSigningErrorCounter = metrics.NewRegisteredCounter("votesSigner/error", nil)
+var (
+	votesSigningErrorCounter   = metrics.NewRegisteredCounter("votesSigner/error", nil)
+	votesSigningTimeoutCounter = metrics.NewRegisteredCounter("votesSigner/timeout", nil)
 
+	errNoBLSKey          = errors.New("no BLS key found")
+	errVoteSignerTimeout = errors.New("vote signing timed out")
+)
+
+// VoteSigner signs the finality votes with the BLS keys of a local wallet or of
+// a remote signer. All the keys of the wallet are loaded, the one signing is
+// selected to match the key registered on-chain and the keys can be reloaded
+// without restarting the node.
 type VoteSigner struct {
-	km     *wallet.KeyManager
-	pubKey [params.BLSPubkeyLength]byte
+	blsPasswordPath string
+	blsWalletPath   string
+	remoteConfig    *RemoteSignerConfig
+
+	lock    sync.RWMutex
+	km      *wallet.KeyManager
+	remote  *remoteSigner
+	pubKeys [][params.BLSPubkeyLength]byte
+	pubKey  [params.BLSPubkeyLength]byte
 }
 
 func NewVoteSigner(blsPasswordPath, blsWalletPath string) (*VoteSigner, error) {
+	signer := &VoteSigner{
+		blsPasswordPath: blsPasswordPath,
+		blsWalletPath:   blsWalletPath,
+	}
+	if err := signer.Reload(); err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+// NewRemoteVoteSigner creates a vote signer using the BLS keys held by a remote
+// signer. The configured public key is selected first, or the
+// first key of the remote signer when no public key is configured.
+func NewRemoteVoteSigner(config *RemoteSignerConfig) (*VoteSigner, error) {
+	signer := &VoteSigner{remoteConfig: config}
+	if err := signer.Reload(); err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+// Reload loads the BLS keys again from the wallet or the remote signer. The
+// selected key is kept if it is still available, the first key is selected
+// otherwise.
+func (signer *VoteSigner) Reload() error {
+	var (
+		km        *wallet.KeyManager
+		remote    *remoteSigner
+		pubKeys   [][params.BLSPubkeyLength]byte
+		preferred []byte
+		err       error
+	)
+	if signer.remoteConfig != nil {
+		if signer.remoteConfig.PublicKey != "" {
+			preferred, err = hex.DecodeString(strings.TrimPrefix(signer.remoteConfig.PublicKey, "0x"))
+			if err != nil || len(preferred) != params.BLSPubkeyLength {
+				return errors.Errorf("invalid BLS public key %s", signer.remoteConfig.PublicKey)
+			}
+		}
+		remote, pubKeys, err = loadRemoteKeys(signer.remoteConfig)
+	} else {
+		km, pubKeys, err = loadWalletKeys(signer.blsPasswordPath, signer.blsWalletPath)
+	}
+	if err != nil {
+		return err
+	}
+	if len(pubKeys) < 1 {
+		return errNoBLSKey
+	}
+
+	signer.lock.Lock()
+	defer signer.lock.Unlock()
+
+	// Keep the selected key on reload, unless a key is configured
+	if preferred == nil && signer.pubKeys != nil {
+		preferred = signer.pubKey[:]
+	}
+	selected := pubKeys[0]
+	if preferred != nil {
+		found := false
+		for _, key := range pubKeys {
+			if bytes.Equal(key[:], preferred) {
+				selected, found = key, true
+				break
+			}
+		}
+		if !found {
+			if signer.remoteConfig != nil && signer.remoteConfig.PublicKey != "" {
+				return errors.Errorf("BLS public key %s not found in remote signer", signer.remoteConfig.PublicKey)
+			}
+			log.Warn("Selected BLS key is no longer available, switching to the first key",
+				"old", hex.EncodeToString(preferred), "new", hex.EncodeToString(selected[:]))
+		}
+	}
+	signer.km, signer.remote = km, remote
+	signer.pubKeys, signer.pubKey = pubKeys, selected
+
+	log.Info("Loaded BLS keys", "keys", len(pubKeys), "selected", hex.EncodeToString(selected[:]))
+	return nil
+}
+
+func loadWalletKeys(blsPasswordPath, blsWalletPath string) (*wallet.KeyManager, [][params.BLSPubkeyLength]byte, error) {
 	w, err := wallet.New(blsWalletPath, blsPasswordPath)
 	if err != nil {
 		log.Error("Failed to open BLS wallet", "err", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	log.Info("Read BLS wallet password successfully")
@@ -38,7 +141,7 @@ func NewVoteSigner(blsPasswordPath, blsWalletPath string) (*VoteSigner, error) {
 	km, err := wallet.NewKeyManager(context.Background(), w)
 	if err != nil {
 		log.Error("Initialize key manager failed", "err", err)
-		return nil, err
+		return nil, nil, err
 	}
 	log.Info("Initialized keymanager successfully")
 
@@ -47,22 +150,75 @@ func NewVoteSigner(blsPasswordPath, blsWalletPath string) (*VoteSigner, error) {
 
 	pubKeys, err := km.FetchValidatingPublicKeys(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "could not fetch validating public keys")
+		return nil, nil, errors.Wrap(err, "could not fetch validating public keys")
 	}
+	return km, pubKeys, nil
+}
 
-	if len(pubKeys) < 1 {
-		return nil, errors.New("no BLS key in keystore")
+func loadRemoteKeys(config *RemoteSignerConfig) (*remoteSigner, [][params.BLSPubkeyLength]byte, error) {
+	remote, err := newRemoteSigner(config)
+	if err != nil {
+		log.Error("Failed to create BLS remote signer", "err", err)
+		return nil, nil, err
 	}
 
-	return &VoteSigner{
-		km:     km,
-		pubKey: pubKeys[0],
-	}, nil
+	ctx, cancel := context.WithTimeout(context.Background(), remote.timeout)
+	defer cancel()
+
+	pubKeys, err := remote.publicKeys(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not fetch public keys from remote signer")
+	}
+	log.Info("Initialized BLS remote signer successfully", "url", remote.url)
+	return remote, pubKeys, nil
+}
+
+// PublicKeys returns all the loaded BLS public keys.
+func (signer *VoteSigner) PublicKeys() [][params.BLSPubkeyLength]byte {
+	signer.lock.RLock()
+	defer signer.lock.RUnlock()
+
+	return append([][params.BLSPubkeyLength]byte(nil), signer.pubKeys...)
 }
 
+// PublicKey returns the BLS public key selected to sign the votes.
+func (signer *VoteSigner) PublicKey() [params.BLSPubkeyLength]byte {
+	signer.lock.RLock()
+	defer signer.lock.RUnlock()
+
+	return signer.pubKey
+}
+
+// Select selects the loaded BLS key matching the public key to sign the votes.
+// It returns whether the selected key changed.
+func (signer *VoteSigner) Select(pubKey []byte) (bool, error) {
+	signer.lock.Lock()
+	defer signer.lock.Unlock()
+
+	if bytes.Equal(signer.pubKey[:], pubKey) {
+		return false, nil
+	}
+	for _, key := range signer.pubKeys {
+		if bytes.Equal(key[:], pubKey) {
+			log.Info("Switched BLS key", "old", hex.EncodeToString(signer.pubKey[:]), "new", hex.EncodeToString(key[:]))
+			signer.pubKey = key
+			return true, nil
+		}
+	}
+	return false, errors.Errorf("BLS public key %x is not loaded", pubKey)
+}
+
+// SignVote signs the vote with the selected BLS key.
 func (signer *VoteSigner) SignVote(vote *types.VoteEnvelope) error {
-	// Sign the vote, fetch the first pubKey as validator's bls public key.
-	pubKey := signer.pubKey
+	return signer.signVote(vote, signer.PublicKey())
+}
+
+// signVote signs the vote with the given BLS key, which must be loaded.
+func (signer *VoteSigner) signVote(vote *types.VoteEnvelope, pubKey [params.BLSPubkeyLength]byte) error {
+	signer.lock.RLock()
+	km, remote := signer.km, signer.remote
+	signer.lock.RUnlock()
+
 	blsPubKey, err := bls.PublicKeyFromBytes(pubKey[:])
 	if err != nil {
 		return errors.Wrap(err, "convert public key from bytes to bls failed")
@@ -70,14 +226,26 @@ func (signer *VoteSigner) SignVote(vote *types.VoteEnvelope) error {
 
 	voteDataHash := vote.Data.Hash()
 
-	ctx, cancel := context.WithTimeout(context.Background(), voteSignerTimeout)
+	timeout := voteSignerTimeout
+	if remote != nil {
+		timeout = remote.timeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	signature, err := (*signer.km).Sign(ctx, &wallet.SignRequest{
-		PublicKey:   pubKey[:],
-		SigningRoot: voteDataHash[:],
-	})
+	var signature bls.Signature
+	if remote != nil {
+		signature, err = remote.sign(ctx, pubKey[:], vote.Data)
+	} else {
+		signature, err = km.Sign(ctx, &wallet.SignRequest{
+			PublicKey:   pubKey[:],
+			SigningRoot: voteDataHash[:],
+		})
+	}
 	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded && !errors.Is(err, errVoteSignerTimeout) {
+			return errors.Wrap(errVoteSignerTimeout, err.Error())
+		}
 		return err
 	}
 
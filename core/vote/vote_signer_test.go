@@ -0,0 +1,168 @@
+package vote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	wallet "github.com/ethereum/go-ethereum/accounts/bls"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/bls"
+	blsCommon "github.com/ethereum/go-ethereum/crypto/bls/common"
+	"github.com/google/uuid"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+)
+
+// importBLSKey imports a new random BLS key into the wallet and returns its
+// public key.
+func importBLSKey(t *testing.T, walletPasswordDir, walletDir string) []byte {
+	w, err := wallet.New(walletDir, walletPasswordDir)
+	if err != nil {
+		t.Fatalf("failed to open wallet: %v", err)
+	}
+	km, err := wallet.NewKeyManager(context.Background(), w)
+	if err != nil {
+		t.Fatalf("failed to create key manager: %v", err)
+	}
+	secretKey, _ := bls.RandKey()
+	encryptor := keystorev4.New()
+	pubKeyBytes := secretKey.PublicKey().Marshal()
+	cryptoFields, err := encryptor.Encrypt(secretKey.Marshal(), password)
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+	id, _ := uuid.NewRandom()
+	keystore := &wallet.Keystore{
+		Crypto:  cryptoFields,
+		ID:      id.String(),
+		Pubkey:  fmt.Sprintf("%x", pubKeyBytes),
+		Version: encryptor.Version(),
+		Name:    encryptor.Name(),
+	}
+	if _, err := km.ImportKeystores(context.Background(), []*wallet.Keystore{keystore}, []string{password}); err != nil {
+		t.Fatalf("failed to import keystore: %v", err)
+	}
+	return pubKeyBytes
+}
+
+type testKeyRegistry struct {
+	key []byte
+	err error
+}
+
+func (r *testKeyRegistry) RegisteredKey(header *types.Header) ([]byte, error) {
+	return r.key, r.err
+}
+
+func TestVoteSignerKeyRotation(t *testing.T) {
+	walletPasswordDir, walletDir := setUpKeyManager(t)
+	signer, err := NewVoteSigner(walletPasswordDir, walletDir)
+	if err != nil {
+		t.Fatalf("Failed to create vote signer, err %s", err)
+	}
+	if len(signer.PublicKeys()) != 1 {
+		t.Fatalf("Expect 1 key, got %d", len(signer.PublicKeys()))
+	}
+	first := signer.PublicKey()
+
+	// The new key is loaded on reload, the selected key is kept
+	second := importBLSKey(t, walletPasswordDir, walletDir)
+	if _, err := signer.Select(second); err == nil {
+		t.Fatal("Expect the key not loaded yet to be rejected")
+	}
+	if err := signer.Reload(); err != nil {
+		t.Fatalf("Failed to reload keys, err %s", err)
+	}
+	if len(signer.PublicKeys()) != 2 {
+		t.Fatalf("Expect 2 keys, got %d", len(signer.PublicKeys()))
+	}
+	if signer.PublicKey() != first {
+		t.Fatalf("Expect selected key %x, got %x", first, signer.PublicKey())
+	}
+
+	// The key registered on-chain is selected to sign the votes
+	registry := &testKeyRegistry{key: second}
+	voteManager := &VoteManager{signer: signer, registry: registry}
+	pubKey, err := voteManager.selectKey(&types.Header{})
+	if err != nil {
+		t.Fatalf("Failed to select key, err %s", err)
+	}
+	if types.BLSPublicKey(pubKey) != types.BLSPublicKey(second) {
+		t.Fatalf("Expect selected key %x, got %x", second, pubKey)
+	}
+	vote := &types.VoteEnvelope{
+		RawVoteEnvelope: types.RawVoteEnvelope{
+			Data: &types.VoteData{TargetNumber: 10, TargetHash: common.Hash{0x1}},
+		},
+	}
+	if err := signer.signVote(vote, pubKey); err != nil {
+		t.Fatalf("Failed to sign vote, err %s", err)
+	}
+	if vote.PublicKey != types.BLSPublicKey(second) {
+		t.Fatalf("Expect vote signed by %x, got %x", second, vote.PublicKey)
+	}
+	if err := vote.Verify(); err != nil {
+		t.Fatalf("Invalid vote signature, err %s", err)
+	}
+
+	// Voting is skipped when the registered key is not loaded
+	registry.key = make([]byte, 48)
+	if _, err := voteManager.selectKey(&types.Header{}); err == nil {
+		t.Fatal("Expect unknown registered key to be rejected")
+	}
+	if signer.PublicKey() != types.BLSPublicKey(second) {
+		t.Fatalf("Expect selected key %x, got %x", second, signer.PublicKey())
+	}
+
+	// A failed lookup only skips the vote at that header
+	registry.key, registry.err = nil, errors.New("lookup failed")
+	if _, err := voteManager.selectKey(&types.Header{}); err == nil {
+		t.Fatal("Expect failed lookup to be reported")
+	}
+	registry.key, registry.err = first[:], nil
+	if pubKey, err := voteManager.selectKey(&types.Header{}); err != nil || types.BLSPublicKey(pubKey) != types.BLSPublicKey(first) {
+		t.Fatalf("Expect key %x selected after a failed lookup, got %x (err %v)", first, pubKey, err)
+	}
+
+	keys, err := voteManager.ReloadKeys()
+	if err != nil {
+		t.Fatalf("Failed to reload keys, err %s", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Expect 2 keys, got %d", len(keys))
+	}
+}
+
+type testBlsKeyReader struct {
+	keys map[common.Hash]blsCommon.PublicKey
+}
+
+func (r *testBlsKeyReader) GetBlsPublicKey(blockHash common.Hash, blockNumber *big.Int, validator common.Address) (blsCommon.PublicKey, error) {
+	key, ok := r.keys[blockHash]
+	if !ok {
+		return nil, errors.New("unknown block")
+	}
+	return key, nil
+}
+
+func TestContractKeyRegistry(t *testing.T) {
+	secretKey, _ := bls.RandKey()
+	header := &types.Header{Number: big.NewInt(10)}
+	reader := &testBlsKeyReader{keys: map[common.Hash]blsCommon.PublicKey{header.Hash(): secretKey.PublicKey()}}
+	registry := NewContractKeyRegistry(reader, func() common.Address { return common.Address{0x1} })
+
+	// The key is read at the block hash, not only its number
+	key, err := registry.RegisteredKey(header)
+	if err != nil {
+		t.Fatalf("Failed to read registered key, err %s", err)
+	}
+	if types.BLSPublicKey(key) != types.BLSPublicKey(secretKey.PublicKey().Marshal()) {
+		t.Fatalf("Expect registered key %x, got %x", secretKey.PublicKey().Marshal(), key)
+	}
+	if _, err := registry.RegisteredKey(&types.Header{Number: big.NewInt(10), Extra: []byte{0x1}}); err == nil {
+		t.Fatal("Expect the key of an unknown block to be rejected")
+	}
+}
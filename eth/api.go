@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/forkid"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -280,6 +281,27 @@ func (api *PrivateAdminAPI) ImportChain(file string) (bool, error) {
 	return true, nil
 }
 
+// ReloadBLSKeys reloads the BLS keys signing the finality votes from the wallet
+// or the remote signer, and returns the loaded public keys.
+func (api *PrivateAdminAPI) ReloadBLSKeys() ([]hexutil.Bytes, error) {
+	pubKeys, err := api.eth.ReloadVoteKeys()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]hexutil.Bytes, 0, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		keys = append(keys, common.CopyBytes(pubKey[:]))
+	}
+	return keys, nil
+}
+
+// ForkSchedule returns every block based fork of the chain config along with
+// its activation state at the current head and the fork id it yields.
+func (api *PrivateAdminAPI) ForkSchedule() []forkid.Fork {
+	chain := api.eth.BlockChain()
+	return forkid.Schedule(chain.Config(), chain.Genesis().Hash(), chain.CurrentHeader().Number.Uint64())
+}
+
 // PublicDebugAPI is the collection of Ethereum full node APIs exposed
 // over the public debugging endpoint.
 type PublicDebugAPI struct {
@@ -308,6 +308,10 @@ func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	return b.eth.txPool.Add([]*types.Transaction{signedTx}, true, false)[0]
 }
 
+func (b *EthAPIBackend) SendTxConditional(ctx context.Context, signedTx *types.Transaction, cond *types.TransactionConditional) error {
+	return b.eth.txPool.AddConditional(signedTx, cond)
+}
+
 func (b *EthAPIBackend) GetPoolTransactions() (types.Transactions, error) {
 	pending := b.eth.txPool.Pending(&txpool.PendingFilter{})
 	var txs types.Transactions
@@ -344,6 +348,14 @@ func (b *EthAPIBackend) TxPoolContentFrom(addr common.Address) ([]*types.Transac
 	return b.eth.TxPool().ContentFrom(addr)
 }
 
+func (b *EthAPIBackend) TxPoolContentFromPayer(payer common.Address) (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
+	return b.eth.TxPool().ContentFromPayer(payer)
+}
+
+func (b *EthAPIBackend) TxPoolPayerCosts() map[common.Address]*big.Int {
+	return b.eth.TxPool().PayerCosts()
+}
+
 func (b *EthAPIBackend) TxPool() *txpool.TxPool {
 	return b.eth.TxPool()
 }
@@ -430,6 +442,10 @@ func (b *EthAPIBackend) StartMining(threads int) error {
 	return b.eth.StartMining(threads)
 }
 
+func (b *EthAPIBackend) ReloadVoteKeys() ([][params.BLSPubkeyLength]byte, error) {
+	return b.eth.ReloadVoteKeys()
+}
+
 func (b *EthAPIBackend) StateAtBlock(ctx context.Context, block *types.Block, reexec uint64, base *state.StateDB, checkLive, preferDisk bool) (*state.StateDB, tracers.StateReleaseFunc, error) {
 	return b.eth.stateAtBlock(ctx, block, reexec, base, checkLive, preferDisk)
 }
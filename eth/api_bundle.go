@@ -0,0 +1,191 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/miner"
+)
+
+// PrivateBundleAPI provides an API to simulate bundles and to submit them to the
+// block producer.
+type PrivateBundleAPI struct {
+	e *Ethereum
+}
+
+// NewPrivateBundleAPI creates a new PrivateBundleAPI instance.
+func NewPrivateBundleAPI(e *Ethereum) *PrivateBundleAPI {
+	return &PrivateBundleAPI{e}
+}
+
+// CallBundleArgs represents the arguments of eth_callBundle.
+type CallBundleArgs struct {
+	Txs []hexutil.Bytes `json:"txs"`
+}
+
+// CallBundleTxResult is the outcome of a bundle transaction.
+type CallBundleTxResult struct {
+	TxHash     common.Hash     `json:"txHash"`
+	From       common.Address  `json:"from"`
+	To         *common.Address `json:"to"`
+	GasUsed    hexutil.Uint64  `json:"gasUsed"`
+	ReturnData hexutil.Bytes   `json:"returnData,omitempty"`
+	Revert     hexutil.Bytes   `json:"revert,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Logs       []*types.Log    `json:"logs"`
+}
+
+// CallBundleResult is the outcome of a bundle simulation.
+type CallBundleResult struct {
+	BundleHash       common.Hash          `json:"bundleHash"`
+	StateBlockNumber hexutil.Uint64       `json:"stateBlockNumber"`
+	TotalGasUsed     hexutil.Uint64       `json:"totalGasUsed"`
+	CoinbaseDiff     *hexutil.Big         `json:"coinbaseDiff"`
+	Results          []CallBundleTxResult `json:"results"`
+}
+
+// CallBundle executes the bundle transactions in order at the top of the pending
+// block, on the state of its parent, as the miner does. Unlike the miner, the
+// simulation carries on past reverting transactions so that all of their
+// outcomes are reported.
+func (api *PrivateBundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (*CallBundleResult, error) {
+	txs, err := decodeBundleTxs(args.Txs)
+	if err != nil {
+		return nil, err
+	}
+	if len(txs) == 0 {
+		return nil, miner.ErrBundleEmpty
+	}
+	block, _ := api.e.miner.Pending()
+	if block == nil {
+		return nil, errors.New("pending block not available")
+	}
+	parent := api.e.blockchain.GetHeaderByHash(block.ParentHash())
+	if parent == nil {
+		return nil, fmt.Errorf("parent block %x not found", block.ParentHash())
+	}
+	statedb, err := api.e.blockchain.StateAt(parent.Root)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		config   = api.e.blockchain.Config()
+		header   = block.Header()
+		coinbase = header.Coinbase
+		signer   = types.MakeSigner(config, header.Number)
+		gp       = new(core.GasPool).AddGas(txpool.CurrentBlockMaxGas(config, header))
+		usedGas  uint64
+		balance  = new(big.Int).Set(statedb.GetBalance(coinbase))
+		vmConfig = *api.e.blockchain.GetVMConfig()
+	)
+	result := &CallBundleResult{
+		BundleHash:       (&miner.Bundle{Txs: txs}).Hash(),
+		StateBlockNumber: hexutil.Uint64(parent.Number.Uint64()),
+	}
+	for i, tx := range txs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction %d [%v]: %w", i, tx.Hash(), err)
+		}
+		statedb.SetTxContext(tx.Hash(), i)
+
+		receipt, res, err := core.ApplyTransaction(config, api.e.blockchain, &coinbase, gp, statedb, header, tx, &usedGas, vmConfig, core.NewReceiptBloomGenerator())
+		if err != nil {
+			return nil, fmt.Errorf("could not apply transaction %d [%v]: %w", i, tx.Hash(), err)
+		}
+		txResult := CallBundleTxResult{
+			TxHash:  tx.Hash(),
+			From:    from,
+			To:      tx.To(),
+			GasUsed: hexutil.Uint64(receipt.GasUsed),
+			Logs:    receipt.Logs,
+		}
+		if txResult.Logs == nil {
+			txResult.Logs = []*types.Log{}
+		}
+		switch {
+		case res.Err == nil:
+			txResult.ReturnData = res.Return()
+		case errors.Is(res.Err, vm.ErrExecutionReverted):
+			txResult.Error = res.Err.Error()
+			txResult.Revert = res.Revert()
+		default:
+			txResult.Error = res.Err.Error()
+		}
+		result.Results = append(result.Results, txResult)
+	}
+	result.TotalGasUsed = hexutil.Uint64(usedGas)
+	result.CoinbaseDiff = (*hexutil.Big)(new(big.Int).Sub(statedb.GetBalance(coinbase), balance))
+	return result, nil
+}
+
+// SendBundleArgs represents the arguments of eth_sendBundle.
+type SendBundleArgs struct {
+	Txs      []hexutil.Bytes `json:"txs"`
+	MinBlock *hexutil.Uint64 `json:"minBlock"` // Defaults to the block after the head
+	MaxBlock *hexutil.Uint64 `json:"maxBlock"` // Defaults to the min block
+}
+
+// SendBundle submits a bundle to the block producer, which includes it at the
+// top of a block in the target range if all of its transactions succeed. The
+// bundle is identified by the returned hash, and resubmitting it updates its
+// target range.
+func (api *PrivateBundleAPI) SendBundle(ctx context.Context, args SendBundleArgs) (common.Hash, error) {
+	txs, err := decodeBundleTxs(args.Txs)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	head := api.e.blockchain.CurrentBlock().Header()
+	bundle := &miner.Bundle{
+		Txs:      txs,
+		MinBlock: head.Number.Uint64() + 1,
+	}
+	if args.MinBlock != nil {
+		bundle.MinBlock = uint64(*args.MinBlock)
+	}
+	bundle.MaxBlock = bundle.MinBlock
+	if args.MaxBlock != nil {
+		bundle.MaxBlock = uint64(*args.MaxBlock)
+	}
+	return api.e.miner.SendBundle(bundle)
+}
+
+// decodeBundleTxs decodes the binary encoded bundle transactions.
+func decodeBundleTxs(encoded []hexutil.Bytes) (types.Transactions, error) {
+	txs := make(types.Transactions, len(encoded))
+	for i, raw := range encoded {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(raw); err != nil {
+			return nil, fmt.Errorf("invalid transaction %d: %w", i, err)
+		}
+		txs[i] = tx
+	}
+	return txs, nil
+}
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/consensus/consortium"
+	consortiumCommon "github.com/ethereum/go-ethereum/consensus/consortium/common"
 	"github.com/ethereum/go-ethereum/core/forkid"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
@@ -57,6 +58,7 @@ import (
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/monitor"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/dnsdisc"
@@ -103,6 +105,11 @@ type Ethereum struct {
 
 	p2pServer *p2p.Server
 
+	alertManager  *monitor.AlertManager           // Alert sinks shared by the monitors
+	uptimeMonitor *monitor.ValidatorUptimeMonitor // Nil if the validator uptime monitor is disabled
+	votePool      *vote.VotePool                  // Nil if fast finality is disabled
+	voteManager   *vote.VoteManager               // Nil if fast finality is disabled
+
 	lock sync.RWMutex // Protects the variadic fields (e.g. gas price and etherbase)
 }
 
@@ -214,14 +221,31 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	chainConfig := eth.blockchain.Config()
 	genesisHash := eth.blockchain.Genesis().Hash()
 
+	if config.EnableMonitorDoubleSign || config.EnableMonitorFinalityVote || config.EnableMonitorValidatorUptime {
+		eth.alertManager = monitor.NewAlertManager(config.MonitorAlert)
+	}
 	if config.EnableMonitorDoubleSign {
-		go eth.blockchain.StartDoubleSignMonitor()
+		go eth.blockchain.StartDoubleSignMonitor(eth.alertManager)
 	}
 	if config.EnableAdditionalChainEvent {
 		eth.blockchain.EnableAdditionalChainEvent()
 	}
 	if config.EnableMonitorFinalityVote {
-		go eth.blockchain.StartFinalityVoteMonitor()
+		go eth.blockchain.StartFinalityVoteMonitor(eth.alertManager)
+	}
+	if config.EnableMonitorValidatorUptime {
+		if engine, ok := eth.engine.(consensus.FastFinalityPoSA); ok {
+			eth.uptimeMonitor = monitor.NewValidatorUptimeMonitor(
+				eth.blockchain,
+				engine,
+				config.MonitorUptimeValidators,
+				config.MonitorUptimeMissThreshold,
+				eth.alertManager,
+			)
+			go eth.blockchain.StartValidatorUptimeMonitor(eth.uptimeMonitor)
+		} else {
+			log.Error("Not a fast finality consensus, validator uptime monitor is disabled")
+		}
 	}
 
 	StartENRFilter(eth.blockchain, eth.p2pServer)
@@ -274,14 +298,46 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		if !ok {
 			return nil, errors.New("consensus engine does not support fast finality")
 		}
+		var voteJournal string
+		if nodeConfig.VoteJournal != "" {
+			voteJournal = stack.ResolvePath(nodeConfig.VoteJournal)
+		}
 		votePool = vote.NewVotePool(
 			eth.blockchain,
 			finalityEngine,
 			nodeConfig.MaxCurVoteAmountPerBlock,
 			eth.handler.removePeer,
+			voteJournal,
+			nodeConfig.VoteRejournal,
 		)
 
-		if _, err := vote.NewVoteManager(
+		var remoteSigner *vote.RemoteSignerConfig
+		if nodeConfig.BlsRemoteSignerURL != "" {
+			remoteSigner = &vote.RemoteSignerConfig{
+				URL:       nodeConfig.BlsRemoteSignerURL,
+				PublicKey: nodeConfig.BlsRemoteSignerPublicKey,
+				TLSCert:   nodeConfig.BlsRemoteSignerTLSCert,
+				TLSKey:    nodeConfig.BlsRemoteSignerTLSKey,
+				TLSCA:     nodeConfig.BlsRemoteSignerTLSCA,
+				Timeout:   nodeConfig.BlsRemoteSignerTimeout,
+			}
+		}
+		// Sign the votes with the BLS key registered in the profile contract, so
+		// the key can be rotated on-chain without restarting the node. The mock
+		// validators have no profile.
+		var registry vote.KeyRegistry
+		if nodeConfig.EnableFastFinalitySign && chainConfig.ConsortiumV2Contracts != nil &&
+			chainConfig.ConsortiumV2Contracts.ProfileContract != (common.Address{}) && consortiumCommon.Validators == nil {
+			contract, err := consortiumCommon.NewContractIntegrator(chainConfig, consortiumCommon.NewConsortiumBackend(ethAPI), nil, common.Address{}, ethAPI)
+			if err != nil {
+				return nil, err
+			}
+			registry = vote.NewContractKeyRegistry(contract, func() common.Address {
+				etherbase, _ := eth.Etherbase()
+				return etherbase
+			})
+		}
+		voteManager, err := vote.NewVoteManager(
 			eth,
 			chainDb,
 			chainConfig,
@@ -290,13 +346,18 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			nodeConfig.EnableFastFinalitySign,
 			nodeConfig.BlsPasswordPath,
 			nodeConfig.BlsWalletPath,
+			remoteSigner,
+			registry,
 			finalityEngine,
 			nil,
-		); err != nil {
+		)
+		if err != nil {
 			return nil, err
 		}
+		eth.voteManager = voteManager
 	}
 	eth.handler.votePool = votePool
+	eth.votePool = votePool
 
 	// set SCValidators function before initiating new miner to prevent miner starts without SCValidators
 	if chainConfig.Consortium != nil {
@@ -434,6 +495,11 @@ func (s *Ethereum) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   NewPublicMinerAPI(s),
 			Public:    true,
+		}, {
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   NewPrivateBundleAPI(s),
+			Public:    false,
 		}, {
 			Namespace: "eth",
 			Version:   "1.0",
@@ -467,6 +533,10 @@ func (s *Ethereum) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   s.netRPCService,
 			Public:    true,
+		}, {
+			Namespace: "monitor",
+			Version:   "1.0",
+			Service:   monitor.NewAPI(s.blockchain, s.chainDb, s.uptimeMonitor),
 		},
 	}...)
 }
@@ -475,6 +545,15 @@ func (s *Ethereum) ResetWithGenesisBlock(gb *types.Block) {
 	s.blockchain.ResetWithGenesisBlock(gb)
 }
 
+// ReloadVoteKeys reloads the BLS keys signing the finality votes and returns the
+// loaded public keys.
+func (s *Ethereum) ReloadVoteKeys() ([][params.BLSPubkeyLength]byte, error) {
+	if s.voteManager == nil {
+		return nil, errors.New("fast finality is disabled")
+	}
+	return s.voteManager.ReloadKeys()
+}
+
 func (s *Ethereum) Etherbase() (eb common.Address, err error) {
 	s.lock.RLock()
 	etherbase := s.etherbase
@@ -696,8 +775,12 @@ func (s *Ethereum) Stop() error {
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
 	s.txPool.Close()
+	if s.votePool != nil {
+		s.votePool.Stop()
+	}
 	s.miner.Close()
 	s.blockchain.Stop()
+	s.alertManager.Stop()
 	s.engine.Close()
 	rawdb.PopUncleanShutdownMarker(s.chainDb)
 	s.chainDb.Close()
@@ -41,6 +41,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/monitor"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
 )
@@ -102,6 +103,9 @@ var Defaults = Config{
 	RPCEVMTimeout: 5 * time.Second,
 	GPO:           FullNodeGPO,
 	RPCTxFeeCap:   1, // 1 ether
+	MonitorAlert:  monitor.DefaultAlertConfig,
+
+	MonitorUptimeMissThreshold: monitor.DefaultMissThreshold,
 }
 
 func init() {
@@ -223,6 +227,11 @@ type Config struct {
 	// CheckpointOracle is the configuration for checkpoint oracle.
 	CheckpointOracle *params.CheckpointOracleConfig `toml:",omitempty"`
 
+	// ConsortiumCheckpoint is the trusted epoch checkpoint header of the consortium
+	// v2 light client. If set, the light client walks the epoch checkpoints from it
+	// instead of syncing the whole header chain.
+	ConsortiumCheckpoint *ConsortiumCheckpoint `toml:",omitempty"`
+
 	// Arrow Glacier block override (TODO: remove after the fork)
 	OverrideArrowGlacier *big.Int `toml:",omitempty"`
 
@@ -232,6 +241,18 @@ type Config struct {
 	// Enable finality vote monitoring
 	EnableMonitorFinalityVote bool
 
+	// Enable validator uptime monitoring
+	EnableMonitorValidatorUptime bool
+
+	// Validators whose consecutive missed in-turn slots are alerted
+	MonitorUptimeValidators []common.Address `toml:",omitempty"`
+
+	// Number of consecutive missed in-turn slots before alerting
+	MonitorUptimeMissThreshold uint64
+
+	// Alert sinks used by the monitors
+	MonitorAlert monitor.AlertConfig
+
 	// Disable ronin p2p protocol
 	DisableRoninProtocol bool
 
@@ -239,6 +260,13 @@ type Config struct {
 	EnableAdditionalChainEvent bool
 }
 
+// ConsortiumCheckpoint is a trusted epoch checkpoint header of a consortium v2
+// chain, it must carry the checkpoint validators.
+type ConsortiumCheckpoint struct {
+	Number uint64
+	Hash   common.Hash
+}
+
 // CreateConsensusEngine creates a consensus engine for the given chain configuration.
 func CreateConsensusEngine(
 	stack *node.Node,
@@ -69,16 +69,25 @@ func Estimate(ctx context.Context, call core.Message, opts *Options, gasCap uint
 	} else {
 		feeCap = common.Big0
 	}
-	// Recap the highest gas limit with account's available balance.
+	// Recap the highest gas limit with account's available balance. The gas fee
+	// is paid by the payer, which is the sender except in sponsored transaction.
 	if feeCap.BitLen() != 0 {
-		balance := opts.State.GetBalance(call.From())
+		balance := opts.State.GetBalance(call.Payer())
 
 		available := new(big.Int).Set(balance)
 		if call.Value() != nil {
-			if call.Value().Cmp(available) >= 0 {
-				return 0, nil, core.ErrInsufficientFundsForTransfer
+			if call.Payer() != call.From() {
+				// The value is transferred from the sender's balance in sponsored
+				// transaction, so it does not limit the payer's allowance
+				if have := opts.State.GetBalance(call.From()); call.Value().Cmp(have) > 0 {
+					return 0, nil, fmt.Errorf("%w: address %v have %v want %v", core.ErrInsufficientSenderFunds, call.From().Hex(), have, call.Value())
+				}
+			} else {
+				if call.Value().Cmp(available) >= 0 {
+					return 0, nil, core.ErrInsufficientFundsForTransfer
+				}
+				available.Sub(available, call.Value())
 			}
-			available.Sub(available, call.Value())
 		}
 		if opts.Config.IsCancun(opts.Header.Number) && len(call.BlobHashes()) > 0 {
 			blobGasPerBlob := new(big.Int).SetUint64(params.BlobTxBlobGasPerBlob)
@@ -211,7 +220,7 @@ func execute(ctx context.Context, call core.Message, opts *Options, gasLimit uin
 		true,
 		call.BlobGasFeeCap(),
 		call.BlobHashes(),
-	)
+	).WithPayer(call.Payer(), call.ExpiredTime()) // Keep the payer of a sponsored transaction
 
 	// Execute the call and separate execution faults caused by a lack of gas or
 	// other non-fixable conditions
@@ -455,6 +455,9 @@ func (h *handler) runRoninExtension(peer *ronin.Peer, handler ronin.Handler) err
 		peer.Log().Info("Ronin extension registration failed", "err", err)
 		return err
 	}
+	if peer.Version() >= ronin.Ronin2 && h.votePool != nil {
+		go h.requestRecentVotes(peer)
+	}
 
 	return handler(peer)
 }
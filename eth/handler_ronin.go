@@ -1,11 +1,16 @@
 package eth
 
 import (
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/protocols/ronin"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 )
 
+// voteCatchUpBlocks is the number of recent blocks whose finality votes are
+// requested from a newly connected peer
+const voteCatchUpBlocks = 8
+
 type roninHandler handler
 
 func (r *roninHandler) RunPeer(peer *ronin.Peer, hand ronin.Handler) error {
@@ -24,17 +29,49 @@ func (r *roninHandler) PeerInfo(id enode.ID) interface{} {
 func (r *roninHandler) Handle(peer *ronin.Peer, packet ronin.Packet) error {
 	switch packet.Kind() {
 	case ronin.NewVoteMsg:
-		if r.votePool != nil {
-			votePacket := packet.(*ronin.NewVotePacket)
-			for _, rawVote := range votePacket.Vote {
-				vote := &types.VoteEnvelope{
-					RawVoteEnvelope: *rawVote,
-				}
-				r.votePool.PutVote(peer.ID(), vote)
-			}
-		} else {
-			peer.Log().Debug("Local node does not enable fast finality, drop new vote msg")
-		}
+		r.putVotes(peer, packet.(*ronin.NewVotePacket).Vote)
+	case ronin.VotesMsg:
+		r.putVotes(peer, packet.(*ronin.VotesPacket).Votes)
 	}
 	return nil
 }
+
+func (r *roninHandler) putVotes(peer *ronin.Peer, rawVotes []*types.RawVoteEnvelope) {
+	if r.votePool == nil {
+		peer.Log().Debug("Local node does not enable fast finality, drop votes")
+		return
+	}
+	for _, rawVote := range rawVotes {
+		vote := &types.VoteEnvelope{
+			RawVoteEnvelope: *rawVote,
+		}
+		r.votePool.PutVote(peer.ID(), vote)
+	}
+}
+
+func (r *roninHandler) FetchVoteByBlockHash(blockHash common.Hash) []*types.VoteEnvelope {
+	if r.votePool == nil {
+		return nil
+	}
+	return r.votePool.FetchVoteByBlockHash(blockHash)
+}
+
+// requestRecentVotes asks the peer for the finality votes of the latest blocks,
+// so that a restarted node can assemble the finality votes it missed while
+// being disconnected.
+func (h *handler) requestRecentVotes(peer *ronin.Peer) {
+	var (
+		hashes []common.Hash
+		header = h.chain.CurrentHeader()
+	)
+	for i := 0; i < voteCatchUpBlocks && header != nil; i++ {
+		hashes = append(hashes, header.Hash())
+		if header.Number.Uint64() == 0 {
+			break
+		}
+		header = h.chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	}
+	if err := peer.RequestVotesByBlockHash(hashes); err != nil {
+		peer.Log().Debug("Failed to request recent votes", "err", err)
+	}
+}
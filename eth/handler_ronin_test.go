@@ -23,6 +23,9 @@ type testRoninHandler struct {
 
 func (h *testRoninHandler) RunPeer(*ronin.Peer, ronin.Handler) error { panic("not used in tests") }
 func (h *testRoninHandler) PeerInfo(enode.ID) interface{}            { panic("not used in tests") }
+func (h *testRoninHandler) FetchVoteByBlockHash(common.Hash) []*types.VoteEnvelope {
+	return nil
+}
 
 func (h *testRoninHandler) Handle(peer *ronin.Peer, packet ronin.Packet) error {
 	switch packet.Kind() {
@@ -4,12 +4,21 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 )
 
+var (
+	voteRequestInMeter      = metrics.NewRegisteredMeter("ronin/votes/request/in", nil)
+	voteRequestOutMeter     = metrics.NewRegisteredMeter("ronin/votes/request/out", nil)
+	voteRequestDroppedMeter = metrics.NewRegisteredMeter("ronin/votes/request/dropped", nil)
+	voteRequestTimeoutMeter = metrics.NewRegisteredMeter("ronin/votes/request/timeout", nil)
+	voteUnsolicitedMeter    = metrics.NewRegisteredMeter("ronin/votes/response/unsolicited", nil)
+)
+
 // Handler is a callback to invoke from an outside runner after the boilerplate
 // exchanges have passed.
 type Handler func(peer *Peer) error
@@ -28,6 +37,10 @@ type Backend interface {
 	// the remote peer. Only packets not consumed by the protocol handler will
 	// be forwarded to the backend.
 	Handle(peer *Peer, packet Packet) error
+
+	// FetchVoteByBlockHash retrieves the known finality votes of a block to
+	// serve the votes requests.
+	FetchVoteByBlockHash(blockHash common.Hash) []*types.VoteEnvelope
 }
 
 func MakeProtocols(backend Backend) []p2p.Protocol {
@@ -112,6 +125,58 @@ func handleMessage(backend Backend, peer *Peer) error {
 		}
 
 		return backend.Handle(peer, &votePacket)
+	case GetVotesByBlockHashMsg:
+		if peer.Version() < Ronin2 {
+			return fmt.Errorf("%w: %v", errInvalidMsgCode, msg.Code)
+		}
+		var request GetVotesByBlockHashPacket
+		if err := msg.Decode(&request); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		if len(request.BlockHashes) > maxVoteRequestHashes {
+			return fmt.Errorf("%w: %d > %d", errTooManyHashes, len(request.BlockHashes), maxVoteRequestHashes)
+		}
+		voteRequestInMeter.Mark(1)
+		if !peer.allowVoteRequest() {
+			voteRequestDroppedMeter.Mark(1)
+			peer.Log().Debug("Dropping votes request over the rate limit", "id", request.RequestId)
+			return nil
+		}
+
+		var votes []*types.VoteEnvelope
+		for _, hash := range request.BlockHashes {
+			votes = append(votes, backend.FetchVoteByBlockHash(hash)...)
+			if len(votes) >= maxVotesServe {
+				votes = votes[:maxVotesServe]
+				break
+			}
+		}
+		return peer.ReplyVotes(request.RequestId, votes)
+	case VotesMsg:
+		if peer.Version() < Ronin2 {
+			return fmt.Errorf("%w: %v", errInvalidMsgCode, msg.Code)
+		}
+		var response VotesPacket
+		if err := msg.Decode(&response); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		if len(response.Votes) > maxVotesServe {
+			return fmt.Errorf("%w: %d > %d", errTooManyVotes, len(response.Votes), maxVotesServe)
+		}
+		if !peer.deliverVotes(response.RequestId) {
+			voteUnsolicitedMeter.Mark(1)
+			peer.Log().Debug("Dropping unsolicited votes response", "id", response.RequestId)
+			return nil
+		}
+		for _, packet := range response.Votes {
+			vote := types.VoteEnvelope{
+				RawVoteEnvelope: *packet,
+			}
+
+			peer.markFinalityVote(vote.Hash())
+		}
+
+		return backend.Handle(peer, &response)
 	default:
 		return fmt.Errorf("%w: %v", errInvalidMsgCode, msg.Code)
 	}
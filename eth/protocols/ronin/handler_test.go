@@ -0,0 +1,124 @@
+package ronin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+type testBackend struct {
+	votes    map[common.Hash][]*types.VoteEnvelope
+	received chan Packet
+}
+
+func (b *testBackend) RunPeer(*Peer, Handler) error  { panic("not used in tests") }
+func (b *testBackend) PeerInfo(enode.ID) interface{} { panic("not used in tests") }
+
+func (b *testBackend) Handle(peer *Peer, packet Packet) error {
+	b.received <- packet
+	return nil
+}
+
+func (b *testBackend) FetchVoteByBlockHash(blockHash common.Hash) []*types.VoteEnvelope {
+	return b.votes[blockHash]
+}
+
+func newTestPeers(t *testing.T, version uint) (*Peer, *Peer, func()) {
+	caps := []p2p.Cap{{Name: ProtocolName, Version: version}}
+	protocols := []p2p.Protocol{{Name: ProtocolName, Version: version}}
+
+	localPipe, remotePipe := p2p.MsgPipe()
+	local := NewPeer(version, p2p.NewPeerPipeWithProtocol(enode.ID{1}, "", caps, localPipe, protocols), localPipe)
+	remote := NewPeer(version, p2p.NewPeerPipeWithProtocol(enode.ID{2}, "", caps, remotePipe, protocols), remotePipe)
+	return local, remote, func() {
+		local.Close()
+		remote.Close()
+		localPipe.Close()
+		remotePipe.Close()
+	}
+}
+
+func TestVotesRequest(t *testing.T) {
+	local, remote, closeFn := newTestPeers(t, Ronin2)
+	defer closeFn()
+
+	blockHash := common.Hash{0x1}
+	remoteBackend := &testBackend{
+		votes: map[common.Hash][]*types.VoteEnvelope{
+			blockHash: {
+				{RawVoteEnvelope: types.RawVoteEnvelope{Data: &types.VoteData{TargetNumber: 1, TargetHash: blockHash}}},
+				{RawVoteEnvelope: types.RawVoteEnvelope{Data: &types.VoteData{TargetNumber: 1, TargetHash: blockHash}}},
+			},
+		},
+	}
+	localBackend := &testBackend{received: make(chan Packet, 1)}
+	go Handle(remoteBackend, remote)
+	go Handle(localBackend, local)
+
+	if err := local.RequestVotesByBlockHash([]common.Hash{blockHash, {0x2}}); err != nil {
+		t.Fatalf("Failed to request votes, err %s", err)
+	}
+	select {
+	case packet := <-localBackend.received:
+		votes, ok := packet.(*VotesPacket)
+		if !ok {
+			t.Fatalf("Expect votes packet, got %T", packet)
+		}
+		if len(votes.Votes) != 2 {
+			t.Fatalf("Expect 2 votes, got %d", len(votes.Votes))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Votes response is not received")
+	}
+
+	// The response is delivered, the request is no longer pending
+	local.pendingLock.Lock()
+	pending := len(local.pendingRequests)
+	local.pendingLock.Unlock()
+	if pending != 0 {
+		t.Fatalf("Expect no pending request, got %d", pending)
+	}
+}
+
+func TestVotesRequestLimits(t *testing.T) {
+	local, remote, closeFn := newTestPeers(t, Ronin2)
+	defer closeFn()
+
+	// Outbound requests are capped by the number of in-flight requests
+	go func() {
+		for {
+			msg, err := remote.rw.ReadMsg()
+			if err != nil {
+				return
+			}
+			msg.Discard()
+		}
+	}()
+	for i := 0; i < maxPendingVoteRequests; i++ {
+		if err := local.RequestVotesByBlockHash([]common.Hash{{0x1}}); err != nil {
+			t.Fatalf("Failed to request votes, err %s", err)
+		}
+	}
+	if err := local.RequestVotesByBlockHash([]common.Hash{{0x1}}); err != errTooManyPendingRequests {
+		t.Fatalf("Expect error %s, got %v", errTooManyPendingRequests, err)
+	}
+
+	// Unsolicited responses are not delivered
+	if local.deliverVotes(0) {
+		t.Fatal("Expect unsolicited response to be rejected")
+	}
+
+	// Inbound requests are rate limited
+	for i := 0; i < voteRequestBurst; i++ {
+		if !remote.allowVoteRequest() {
+			t.Fatalf("Expect request %d to be allowed", i)
+		}
+	}
+	if remote.allowVoteRequest() {
+		t.Fatal("Expect request over the burst to be dropped")
+	}
+}
@@ -1,6 +1,9 @@
 package ronin
 
 import (
+	"errors"
+	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -8,14 +11,29 @@ import (
 	"github.com/ethereum/go-ethereum/eth/protocols"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p"
+	"golang.org/x/time/rate"
 )
 
 const (
 	voteChannelSize = 50
 	batchInterval   = 100 * time.Millisecond
 	maxKnownVote    = 8192
+
+	// maxPendingVoteRequests is the maximum number of in-flight votes requests
+	// sent to a peer
+	maxPendingVoteRequests = 4
+	// voteRequestTimeout is the time after which an unanswered votes request is
+	// dropped
+	voteRequestTimeout = 10 * time.Second
+
+	// voteRequestRate and voteRequestBurst limit the votes requests served to
+	// a peer
+	voteRequestRate  = 2
+	voteRequestBurst = 4
 )
 
+var errTooManyPendingRequests = errors.New("too many pending votes requests")
+
 // Peer is a collection of relevant information we have about a `ronin` peer.
 type Peer struct {
 	id string // Unique ID for the peer, cached
@@ -29,6 +47,10 @@ type Peer struct {
 	logger log.Logger // Contextual logger with the peer id injected
 
 	knownFinalityVote *protocols.KnownCache // Set of finality vote hashes knowed to be known by this peer
+
+	requestLimiter  *rate.Limiter        // Limits the votes requests served to the peer
+	pendingRequests map[uint64]time.Time // Votes requests sent to the peer, waiting for response
+	pendingLock     sync.Mutex
 }
 
 // NewPeer create a wrapper for a network connection and negotiated  protocol
@@ -44,6 +66,8 @@ func NewPeer(version uint, p *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
 		term:              make(chan struct{}),
 		logger:            log.New("peer", id[:8]),
 		knownFinalityVote: protocols.NewKnownCache(maxKnownVote),
+		requestLimiter:    rate.NewLimiter(voteRequestRate, voteRequestBurst),
+		pendingRequests:   make(map[uint64]time.Time),
 	}
 	go peer.batchVote()
 
@@ -126,3 +150,69 @@ func (p *Peer) markFinalityVote(hash common.Hash) {
 	// If we reached the memory allowance, drop a previously known transaction hash
 	p.knownFinalityVote.Add(hash)
 }
+
+// RequestVotesByBlockHash asks the peer for the finality votes of the given
+// blocks, the votes are delivered back to the backend as a VotesPacket.
+func (p *Peer) RequestVotesByBlockHash(hashes []common.Hash) error {
+	if len(hashes) > maxVoteRequestHashes {
+		hashes = hashes[:maxVoteRequestHashes]
+	}
+
+	p.pendingLock.Lock()
+	for id, sent := range p.pendingRequests {
+		if time.Since(sent) > voteRequestTimeout {
+			delete(p.pendingRequests, id)
+			voteRequestTimeoutMeter.Mark(1)
+		}
+	}
+	if len(p.pendingRequests) >= maxPendingVoteRequests {
+		p.pendingLock.Unlock()
+		return errTooManyPendingRequests
+	}
+	id := rand.Uint64()
+	p.pendingRequests[id] = time.Now()
+	p.pendingLock.Unlock()
+
+	voteRequestOutMeter.Mark(1)
+	err := p2p.Send(p.rw, GetVotesByBlockHashMsg, &GetVotesByBlockHashPacket{
+		RequestId:   id,
+		BlockHashes: hashes,
+	})
+	if err != nil {
+		p.pendingLock.Lock()
+		delete(p.pendingRequests, id)
+		p.pendingLock.Unlock()
+	}
+	return err
+}
+
+// ReplyVotes sends the response to a votes request.
+func (p *Peer) ReplyVotes(id uint64, votes []*types.VoteEnvelope) error {
+	rawVotes := make([]*types.RawVoteEnvelope, 0, len(votes))
+	for _, vote := range votes {
+		rawVotes = append(rawVotes, vote.Raw())
+	}
+	return p2p.Send(p.rw, VotesMsg, &VotesPacket{
+		RequestId: id,
+		Votes:     rawVotes,
+	})
+}
+
+// allowVoteRequest reports whether an inbound votes request is within the
+// peer's rate limit.
+func (p *Peer) allowVoteRequest() bool {
+	return p.requestLimiter.Allow()
+}
+
+// deliverVotes marks the votes request as answered, it returns false if the
+// request is unknown or has already been answered.
+func (p *Peer) deliverVotes(id uint64) bool {
+	p.pendingLock.Lock()
+	defer p.pendingLock.Unlock()
+
+	if _, ok := p.pendingRequests[id]; !ok {
+		return false
+	}
+	delete(p.pendingRequests, id)
+	return true
+}
@@ -3,12 +3,14 @@ package ronin
 import (
 	"errors"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
 // Constants to match up protocol versions and messages
 const (
 	Ronin1 = 1
+	Ronin2 = 2
 )
 
 // ProtocolName is the official short name of the `ronin` protocol used during
@@ -16,23 +18,38 @@ const (
 const ProtocolName = "ronin"
 
 // ProtocolVersions are the supported versions of the `ronin` protocol
-var ProtocolVersions = []uint{Ronin1}
+var ProtocolVersions = []uint{Ronin2, Ronin1}
 
 // protocolLengths are the number of implemented message corresponding to
 // different protocol versions.
-var protocolLengths = map[uint]uint64{Ronin1: 1}
+var protocolLengths = map[uint]uint64{Ronin2: 3, Ronin1: 1}
 
 // maxMessageSize is the maximum cap on the size of a protocol message.
 const maxMessageSize = 10 * 1024 * 1024
 
 const (
+	// Protocol messages in ronin/1
 	NewVoteMsg = 0x00
+
+	// Protocol messages overloaded in ronin/2
+	GetVotesByBlockHashMsg = 0x01
+	VotesMsg               = 0x02
+)
+
+const (
+	// maxVoteRequestHashes is the maximum number of block hashes in a votes request
+	maxVoteRequestHashes = 16
+
+	// maxVotesServe is the maximum number of votes in a votes response
+	maxVotesServe = 1024
 )
 
 var (
 	errMsgTooLarge    = errors.New("message too long")
 	errDecode         = errors.New("invalid message")
 	errInvalidMsgCode = errors.New("invalid message code")
+	errTooManyHashes  = errors.New("too many block hashes requested")
+	errTooManyVotes   = errors.New("too many votes in response")
 )
 
 // Packet represents a p2p message in the `ronin` protocol.
@@ -47,3 +64,21 @@ type NewVotePacket struct {
 
 func (*NewVotePacket) Name() string { return "NewVote" }
 func (*NewVotePacket) Kind() byte   { return NewVoteMsg }
+
+// GetVotesByBlockHashPacket requests the finality votes of the given blocks.
+type GetVotesByBlockHashPacket struct {
+	RequestId   uint64
+	BlockHashes []common.Hash
+}
+
+func (*GetVotesByBlockHashPacket) Name() string { return "GetVotesByBlockHash" }
+func (*GetVotesByBlockHashPacket) Kind() byte   { return GetVotesByBlockHashMsg }
+
+// VotesPacket is the response to GetVotesByBlockHashPacket.
+type VotesPacket struct {
+	RequestId uint64
+	Votes     []*types.RawVoteEnvelope
+}
+
+func (*VotesPacket) Name() string { return "Votes" }
+func (*VotesPacket) Kind() byte   { return VotesMsg }
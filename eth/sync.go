@@ -45,7 +45,7 @@ func (h *handler) syncTransactions(p *eth.Peer) {
 	//
 	// TODO(karalabe): Figure out if we could get away with random order somehow
 	var txs types.Transactions
-	pending := h.txpool.Pending(&txpool.PendingFilter{OnlyPlainTxs: true})
+	pending := h.txpool.Pending(&txpool.PendingFilter{OnlyPlainTxs: true, NoConditionals: true})
 	for _, batch := range pending {
 		for _, tx := range batch {
 			txs = append(txs, tx.Resolve())
@@ -0,0 +1,9 @@
+// Package genesis embeds the genesis files of the Ronin networks.
+package genesis
+
+import _ "embed"
+
+// Devnet is the genesis of the Ronin devnet, carrying the system contracts.
+//
+//go:embed devnet.json
+var Devnet []byte
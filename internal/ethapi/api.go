@@ -36,6 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -230,12 +231,46 @@ func (s *PublicTxPoolAPI) ContentFrom(addr common.Address) map[string]map[string
 	return content
 }
 
-// Status returns the number of pending and queued transaction in the pool.
-func (s *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
+// ContentFromPayer returns the sponsored transactions paid by the payer within
+// the transaction pool, grouped by sender.
+func (s *PublicTxPoolAPI) ContentFromPayer(payer common.Address) map[string]map[string]map[string]*RPCTransaction {
+	content := map[string]map[string]map[string]*RPCTransaction{
+		"pending": make(map[string]map[string]*RPCTransaction),
+		"queued":  make(map[string]map[string]*RPCTransaction),
+	}
+	pending, queue := s.b.TxPoolContentFromPayer(payer)
+	curHeader := s.b.CurrentHeader()
+	// Flatten the pending transactions
+	for account, txs := range pending {
+		dump := make(map[string]*RPCTransaction)
+		for _, tx := range txs {
+			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx, curHeader, s.b.ChainConfig())
+		}
+		content["pending"][account.Hex()] = dump
+	}
+	// Flatten the queued transactions
+	for account, txs := range queue {
+		dump := make(map[string]*RPCTransaction)
+		for _, tx := range txs {
+			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx, curHeader, s.b.ChainConfig())
+		}
+		content["queued"][account.Hex()] = dump
+	}
+	return content
+}
+
+// Status returns the number of pending and queued transaction in the pool, and
+// the total gas fee of the pending sponsored transactions charged to each payer.
+func (s *PublicTxPoolAPI) Status() map[string]interface{} {
 	pending, queue := s.b.Stats()
-	return map[string]hexutil.Uint{
-		"pending": hexutil.Uint(pending),
-		"queued":  hexutil.Uint(queue),
+	payerCosts := make(map[string]*hexutil.Big)
+	for payer, cost := range s.b.TxPoolPayerCosts() {
+		payerCosts[payer.Hex()] = (*hexutil.Big)(cost)
+	}
+	return map[string]interface{}{
+		"pending":    hexutil.Uint(pending),
+		"queued":     hexutil.Uint(queue),
+		"payerCosts": payerCosts,
 	}
 }
 
@@ -398,6 +433,28 @@ func fetchKeystore(am *accounts.Manager) (*keystore.KeyStore, error) {
 	return nil, errors.New("local keystore not used")
 }
 
+// signPayerHash returns the function signing the payer hash of a sponsored
+// transaction with the keystore account of the payer, which must be unlocked.
+func signPayerHash(am *accounts.Manager, payer common.Address) func(hash common.Hash) ([]byte, error) {
+	return func(hash common.Hash) ([]byte, error) {
+		ks, err := fetchKeystore(am)
+		if err != nil {
+			return nil, err
+		}
+		return ks.SignHash(accounts.Account{Address: payer}, hash[:])
+	}
+}
+
+// toSignableTransaction assembles the transaction to be signed by the sender,
+// the sponsored transaction is signed by the payer first.
+func toSignableTransaction(b Backend, args *TransactionArgs) (*types.Transaction, error) {
+	if !args.IsSponsored() {
+		return args.toTransaction(), nil
+	}
+	signer := types.LatestSignerForChainID(b.ChainConfig().ChainID)
+	return args.toPayerSignedTransaction(signer, signPayerHash(b.AccountManager(), *args.Payer))
+}
+
 // ImportRawKey stores the given hex encoded ECDSA key into the key directory,
 // encrypting it with the passphrase.
 func (s *PrivateAccountAPI) ImportRawKey(privkey string, password string) (common.Address, error) {
@@ -467,8 +524,10 @@ func (s *PrivateAccountAPI) signTransaction(ctx context.Context, args *Transacti
 		return nil, err
 	}
 	// Assemble the transaction and sign with the wallet
-	tx := args.toTransaction()
-
+	tx, err := toSignableTransaction(s.b, args)
+	if err != nil {
+		return nil, err
+	}
 	return wallet.SignTxWithPassphrase(account, passwd, tx, s.b.ChainConfig().ChainID)
 }
 
@@ -997,6 +1056,36 @@ func (diff *BlockOverrides) Apply(blockCtx *vm.BlockContext) {
 	}
 }
 
+// MakeHeader returns a copy of the given header with the overridden fields.
+func (diff *BlockOverrides) MakeHeader(header *types.Header) *types.Header {
+	header = types.CopyHeader(header)
+	if diff == nil {
+		return header
+	}
+	if diff.Number != nil {
+		header.Number = diff.Number.ToInt()
+	}
+	if diff.Difficulty != nil {
+		header.Difficulty = diff.Difficulty.ToInt()
+	}
+	if diff.Time != nil {
+		header.Time = uint64(*diff.Time)
+	}
+	if diff.GasLimit != nil {
+		header.GasLimit = uint64(*diff.GasLimit)
+	}
+	if diff.Coinbase != nil {
+		header.Coinbase = *diff.Coinbase
+	}
+	if diff.Random != nil {
+		header.MixDigest = *diff.Random
+	}
+	if diff.BaseFee != nil {
+		header.BaseFee = diff.BaseFee.ToInt()
+	}
+	return header
+}
+
 // ChainContextBackend provides methods required to implement ChainContext.
 type ChainContextBackend interface {
 	Engine() consensus.Engine
@@ -1860,6 +1949,12 @@ func (s *PublicTransactionPoolAPI) sign(addr common.Address, tx *types.Transacti
 
 // SubmitTransaction is a helper function that submits tx to txPool and logs a message.
 func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (common.Hash, error) {
+	return submitTransaction(ctx, b, tx, nil)
+}
+
+// submitTransaction is a helper function that submits tx to txPool, along with
+// its inclusion conditions if any, and logs a message.
+func submitTransaction(ctx context.Context, b Backend, tx *types.Transaction, cond *types.TransactionConditional) (common.Hash, error) {
 	// If the transaction fee cap is already specified, ensure the
 	// fee of the given transaction is _reasonable_.
 	if err := checkTxFee(tx.GasPrice(), tx.Gas(), b.RPCTxFeeCap()); err != nil {
@@ -1869,7 +1964,11 @@ func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (c
 		// Ensure only eip155 signed transactions are submitted if EIP155Required is set.
 		return common.Hash{}, errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
 	}
-	if err := b.SendTx(ctx, tx); err != nil {
+	if cond != nil {
+		if err := b.SendTxConditional(ctx, tx, cond); err != nil {
+			return common.Hash{}, err
+		}
+	} else if err := b.SendTx(ctx, tx); err != nil {
 		return common.Hash{}, err
 	}
 	// Print a log with full tx details for manual investigations and interventions
@@ -1918,8 +2017,10 @@ func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args Tra
 		return common.Hash{}, err
 	}
 	// Assemble the transaction and sign with the wallet
-	tx := args.toTransaction()
-
+	tx, err := toSignableTransaction(s.b, &args)
+	if err != nil {
+		return common.Hash{}, err
+	}
 	signed, err := wallet.SignTx(account, tx, s.b.ChainConfig().ChainID)
 	if err != nil {
 		return common.Hash{}, err
@@ -1955,6 +2056,61 @@ func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, input
 	return SubmitTransaction(ctx, s.b, tx)
 }
 
+// TransactionConditionalArgs represents the inclusion conditions of a transaction
+// submitted through eth_sendRawTransactionConditional. The bounds are inclusive.
+type TransactionConditionalArgs struct {
+	KnownAccounts  map[common.Address]map[common.Hash]common.Hash `json:"knownAccounts"`
+	BlockNumberMin *hexutil.Uint64                                `json:"blockNumberMin"`
+	BlockNumberMax *hexutil.Uint64                                `json:"blockNumberMax"`
+	TimestampMin   *hexutil.Uint64                                `json:"timestampMin"`
+	TimestampMax   *hexutil.Uint64                                `json:"timestampMax"`
+}
+
+// toConditional converts the arguments to the conditions kept by the pool.
+func (args *TransactionConditionalArgs) toConditional() *types.TransactionConditional {
+	bound := func(v *hexutil.Uint64) *uint64 {
+		if v == nil {
+			return nil
+		}
+		u := uint64(*v)
+		return &u
+	}
+	return &types.TransactionConditional{
+		KnownAccounts:  args.KnownAccounts,
+		BlockNumberMin: bound(args.BlockNumberMin),
+		BlockNumberMax: bound(args.BlockNumberMax),
+		TimestampMin:   bound(args.TimestampMin),
+		TimestampMax:   bound(args.TimestampMax),
+	}
+}
+
+// SendRawTransactionConditional will add the signed transaction to the transaction
+// pool, to be included in a block only while the given storage slot values and
+// block number and timestamp bounds hold. The transaction is dropped from the
+// pool once the conditions fail against the chain head.
+func (s *PublicTransactionPoolAPI) SendRawTransactionConditional(ctx context.Context, input hexutil.Bytes, args TransactionConditionalArgs) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	cond := args.toConditional()
+	if err := cond.Validate(); err != nil {
+		return common.Hash{}, err
+	}
+	// Reject the transaction right away if the conditions already fail at the head
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return common.Hash{}, err
+	}
+	if err := cond.CheckAfter(header); err != nil {
+		return common.Hash{}, err
+	}
+	if err := cond.CheckState(state); err != nil {
+		return common.Hash{}, err
+	}
+	return submitTransaction(ctx, s.b, tx, cond)
+}
+
 // Sign calculates an ECDSA signature for:
 // keccack256("\x19Ethereum Signed Message:\n" + len(message) + message).
 //
@@ -2013,6 +2169,10 @@ func (s *PublicTransactionPoolAPI) SignTransaction(ctx context.Context, args Tra
 	if err := checkTxFee(tx.GasPrice(), tx.Gas(), s.b.RPCTxFeeCap()); err != nil {
 		return nil, err
 	}
+	tx, err := toSignableTransaction(s.b, &args)
+	if err != nil {
+		return nil, err
+	}
 	signed, err := s.sign(args.from(), tx)
 	if err != nil {
 		return nil, err
@@ -2288,6 +2448,171 @@ func (s *PublicRoninAPI) GetBlobSidecarsByHash(ctx context.Context, hash common.
 	return sidecars, nil
 }
 
+// maxInternalTxsPageSize is the maximum number of internal transactions that
+// can be returned by a single internal transaction query.
+const maxInternalTxsPageSize = 1000
+
+var (
+	errNonCanonicalBlock   = errors.New("block is not in the canonical chain")
+	errInternalTxsDisabled = errors.New("internal transactions are not stored, enable them with --internaltxs")
+)
+
+// PublicInternalTransactionAPI provides an API to access the internal transactions
+// stored in the database when --internaltxs is enabled.
+type PublicInternalTransactionAPI struct {
+	b Backend
+}
+
+// NewPublicInternalTransactionAPI creates a new internal transaction API.
+func NewPublicInternalTransactionAPI(b Backend) *PublicInternalTransactionAPI {
+	return &PublicInternalTransactionAPI{b}
+}
+
+// RPCInternalTransaction represents an internal transaction that will serialize
+// to the RPC representation of an internal transaction.
+type RPCInternalTransaction struct {
+	Hash            common.Hash    `json:"hash"`
+	Order           hexutil.Uint64 `json:"order"`
+	Opcode          string         `json:"opcode"`
+	Type            string         `json:"type"`
+	Success         bool           `json:"success"`
+	Error           string         `json:"error,omitempty"`
+	TransactionHash common.Hash    `json:"transactionHash"`
+	From            common.Address `json:"from"`
+	To              common.Address `json:"to"`
+	Value           *hexutil.Big   `json:"value"`
+	Input           hexutil.Bytes  `json:"input"`
+	Output          hexutil.Bytes  `json:"output"`
+	BlockHash       common.Hash    `json:"blockHash"`
+	BlockNumber     hexutil.Uint64 `json:"blockNumber"`
+	BlockTime       hexutil.Uint64 `json:"blockTime"`
+}
+
+// RPCInternalTransactionPage is a page of internal transactions belonging to a
+// block or a transaction.
+type RPCInternalTransactionPage struct {
+	BlockHash    common.Hash               `json:"blockHash"`
+	BlockNumber  hexutil.Uint64            `json:"blockNumber"`
+	Total        hexutil.Uint              `json:"total"`
+	Offset       hexutil.Uint              `json:"offset"`
+	Transactions []*RPCInternalTransaction `json:"transactions"`
+}
+
+// newRPCInternalTransaction returns an internal transaction that will serialize
+// to the RPC representation.
+func newRPCInternalTransaction(itx *types.InternalTransaction) *RPCInternalTransaction {
+	result := &RPCInternalTransaction{
+		Hash:    itx.Hash(),
+		Opcode:  itx.Opcode,
+		Type:    itx.Type,
+		Success: itx.Success,
+		Error:   itx.Error,
+		Output:  itx.Output,
+	}
+	if body := itx.InternalTransactionBody; body != nil {
+		result.Order = hexutil.Uint64(body.Order)
+		result.TransactionHash = body.TransactionHash
+		result.From = body.From
+		result.To = body.To
+		result.Value = (*hexutil.Big)(body.Value)
+		result.Input = body.Input
+		result.BlockHash = body.BlockHash
+		result.BlockNumber = hexutil.Uint64(body.Height)
+		result.BlockTime = hexutil.Uint64(body.BlockTime)
+	}
+	return result
+}
+
+// GetInternalTransactionsByBlockNumber returns the internal transactions of the
+// canonical block with the given number. The offset and limit arguments can be
+// used to paginate through blocks with many internal transactions, the next
+// pages should be requested by the returned block hash so that a reorg of the
+// block is reported instead of mixing the internal transactions of two blocks.
+func (s *PublicInternalTransactionAPI) GetInternalTransactionsByBlockNumber(ctx context.Context, number rpc.BlockNumber, offset *hexutil.Uint, limit *hexutil.Uint) (*RPCInternalTransactionPage, error) {
+	if number == rpc.PendingBlockNumber {
+		return nil, errors.New("internal transactions of the pending block are not available")
+	}
+	header, err := s.b.HeaderByNumber(ctx, number)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	return s.page(header, nil, offset, limit)
+}
+
+// GetInternalTransactionsByBlockHash returns the internal transactions of the
+// block with the given hash. An error is returned if the block is no longer
+// part of the canonical chain.
+func (s *PublicInternalTransactionAPI) GetInternalTransactionsByBlockHash(ctx context.Context, hash common.Hash, offset *hexutil.Uint, limit *hexutil.Uint) (*RPCInternalTransactionPage, error) {
+	header, err := s.b.HeaderByHash(ctx, hash)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	return s.page(header, nil, offset, limit)
+}
+
+// GetInternalTransactionsByTxHash returns the internal transactions produced by
+// the canonical transaction with the given hash.
+func (s *PublicInternalTransactionAPI) GetInternalTransactionsByTxHash(ctx context.Context, hash common.Hash, offset *hexutil.Uint, limit *hexutil.Uint) (*RPCInternalTransactionPage, error) {
+	tx, blockHash, _, _, err := s.b.GetTransaction(ctx, hash)
+	if tx == nil || err != nil {
+		return nil, err
+	}
+	header, err := s.b.HeaderByHash(ctx, blockHash)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	return s.page(header, &hash, offset, limit)
+}
+
+// page reads the internal transactions of the given block, optionally filtered
+// by the parent transaction hash, and returns the requested window of them.
+func (s *PublicInternalTransactionAPI) page(header *types.Header, txHash *common.Hash, offset *hexutil.Uint, limit *hexutil.Uint) (*RPCInternalTransactionPage, error) {
+	var (
+		db     = s.b.ChainDb()
+		hash   = header.Hash()
+		number = header.Number.Uint64()
+	)
+	if !rawdb.ReadStoreInternalTransactionsEnabled(db) {
+		return nil, errInternalTxsDisabled
+	}
+	if rawdb.ReadCanonicalHash(db, number) != hash {
+		return nil, errNonCanonicalBlock
+	}
+	// The internal transactions are stored by block hash, they belong to the
+	// returned block even if it is reorged out meanwhile.
+	internalTxs := rawdb.ReadInternalTransactions(db, hash)
+	if txHash != nil {
+		filtered := make([]*types.InternalTransaction, 0)
+		for _, itx := range internalTxs {
+			if itx.InternalTransactionBody != nil && itx.TransactionHash == *txHash {
+				filtered = append(filtered, itx)
+			}
+		}
+		internalTxs = filtered
+	}
+	start, size := uint(0), uint(maxInternalTxsPageSize)
+	if offset != nil {
+		start = uint(*offset)
+	}
+	if limit != nil {
+		if uint(*limit) == 0 || uint(*limit) > maxInternalTxsPageSize {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxInternalTxsPageSize)
+		}
+		size = uint(*limit)
+	}
+	result := &RPCInternalTransactionPage{
+		BlockHash:    hash,
+		BlockNumber:  hexutil.Uint64(number),
+		Total:        hexutil.Uint(len(internalTxs)),
+		Offset:       hexutil.Uint(start),
+		Transactions: make([]*RPCInternalTransaction, 0),
+	}
+	for i := start; i < uint(len(internalTxs)) && i < start+size; i++ {
+		result.Transactions = append(result.Transactions, newRPCInternalTransaction(internalTxs[i]))
+	}
+	return result, nil
+}
+
 // checkTxFee is an internal function used to check whether the fee of
 // the given transaction is _reasonable_(under the cap).
 func checkTxFee(gasPrice *big.Int, gas uint64, cap float64) error {
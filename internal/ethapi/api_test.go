@@ -577,6 +577,9 @@ func (b testBackend) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) even
 func (b testBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
 	panic("implement me")
 }
+func (b testBackend) SendTxConditional(ctx context.Context, signedTx *types.Transaction, cond *types.TransactionConditional) error {
+	panic("implement me")
+}
 func (b testBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error) {
 	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(b.db, txHash)
 	return tx, blockHash, blockNumber, index, nil
@@ -593,6 +596,12 @@ func (b testBackend) TxPoolContent() (map[common.Address][]*types.Transaction, m
 func (b testBackend) TxPoolContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction) {
 	panic("implement me")
 }
+func (b testBackend) TxPoolContentFromPayer(payer common.Address) (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
+	panic("implement me")
+}
+func (b testBackend) TxPoolPayerCosts() map[common.Address]*big.Int {
+	panic("implement me")
+}
 func (b testBackend) SubscribeNewTxsEvent(events chan<- core.NewTxsEvent) event.Subscription {
 	panic("implement me")
 }
@@ -714,6 +723,41 @@ func TestEstimateGas(t *testing.T) {
 			},
 			expectErr: core.ErrInsufficientFunds,
 		},
+		// sponsored transfer, the gas fee is paid by the payer
+		{
+			blockNumber: rpc.LatestBlockNumber,
+			call: TransactionArgs{
+				From:                 &randomAccounts[0].addr,
+				To:                   &accounts[1].addr,
+				Payer:                &accounts[0].addr,
+				MaxFeePerGas:         (*hexutil.Big)(big.NewInt(10 * params.GWei)),
+				MaxPriorityFeePerGas: (*hexutil.Big)(big.NewInt(10 * params.GWei)),
+			},
+			want: 21000,
+		},
+		// sponsored transfer with insufficient sender funds for value
+		{
+			blockNumber: rpc.LatestBlockNumber,
+			call: TransactionArgs{
+				From:                 &randomAccounts[0].addr,
+				To:                   &accounts[1].addr,
+				Payer:                &accounts[0].addr,
+				Value:                (*hexutil.Big)(big.NewInt(1000)),
+				MaxFeePerGas:         (*hexutil.Big)(big.NewInt(10 * params.GWei)),
+				MaxPriorityFeePerGas: (*hexutil.Big)(big.NewInt(10 * params.GWei)),
+			},
+			expectErr: core.ErrInsufficientSenderFunds,
+		},
+		// sponsored transaction with the same payer and sender
+		{
+			blockNumber: rpc.LatestBlockNumber,
+			call: TransactionArgs{
+				From:  &accounts[0].addr,
+				To:    &accounts[1].addr,
+				Payer: &accounts[0].addr,
+			},
+			expectErr: types.ErrSamePayerSenderSponsoredTx,
+		},
 		// Blobs should have no effect on gas estimate
 		{
 			blockNumber: rpc.LatestBlockNumber,
@@ -946,6 +990,101 @@ func TestCall(t *testing.T) {
 	}
 }
 
+func TestSimulate(t *testing.T) {
+	t.Parallel()
+	var (
+		accounts = newAccounts(3)
+		genesis  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			},
+		}
+		reverter = common.Address{0xaa}
+		numberer = common.Address{0xbb}
+		latest   = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	)
+	api := NewPublicBlockChainAPI(newTestBackend(t, 1, genesis, ethash.NewFaker(), func(i int, b *core.BlockGen) {}))
+
+	results, err := api.Simulate(context.Background(), SimulateOpts{
+		TraceTransfers: true,
+		BlockStateCalls: []SimulateBlock{
+			{
+				StateOverrides: &StateOverride{
+					// revert with the 32 bytes word 0x2a
+					reverter: {Code: newRPCBytes([]byte{byte(vm.PUSH1), 0x2a, byte(vm.PUSH1), 0, byte(vm.MSTORE), byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0, byte(vm.REVERT)})},
+					// return the block number
+					numberer: {Code: newRPCBytes([]byte{byte(vm.NUMBER), byte(vm.PUSH1), 0, byte(vm.MSTORE), byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0, byte(vm.RETURN)})},
+				},
+				Calls: []TransactionArgs{
+					{From: &accounts[0].addr, To: &accounts[1].addr, Value: (*hexutil.Big)(big.NewInt(1000))},
+					{From: &accounts[0].addr, To: &reverter, Value: (*hexutil.Big)(big.NewInt(1))},
+				},
+			},
+			{
+				BlockOverrides: &BlockOverrides{Number: (*hexutil.Big)(big.NewInt(5))},
+				Calls: []TransactionArgs{
+					// only funded by the transfer of the previous block
+					{From: &accounts[1].addr, To: &accounts[2].addr, Value: (*hexutil.Big)(big.NewInt(600))},
+					{From: &accounts[1].addr, To: &numberer},
+				},
+			},
+		},
+	}, &latest)
+	if err != nil {
+		t.Fatalf("failed to simulate: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("block count mismatch: have %d, want 2", len(results))
+	}
+	if results[0].Number != 2 || results[1].Number != 5 || results[1].ParentHash != results[0].Hash {
+		t.Errorf("unexpected blocks: %d, %d (parent %x, want %x)", results[0].Number, results[1].Number, results[1].ParentHash, results[0].Hash)
+	}
+	transfer := results[0].Calls[0]
+	if transfer.Status != 1 || transfer.GasUsed != hexutil.Uint64(params.TxGas) || len(transfer.Logs) != 1 {
+		t.Fatalf("unexpected transfer result: %+v", transfer)
+	}
+	if log := transfer.Logs[0]; log.Address != transferAddress || log.Topics[1] != common.BytesToHash(accounts[0].addr.Bytes()) || new(big.Int).SetBytes(log.Data).Int64() != 1000 {
+		t.Errorf("unexpected transfer log: %+v", log)
+	}
+	reverted := results[0].Calls[1]
+	if reverted.Status != 0 || reverted.Error == nil || reverted.Error.Code != 3 || len(reverted.Logs) != 0 {
+		t.Errorf("unexpected reverted result: %+v", reverted)
+	}
+	if new(big.Int).SetBytes(reverted.ReturnValue).Int64() != 0x2a {
+		t.Errorf("revert data mismatch: have %x", reverted.ReturnValue)
+	}
+	if results[1].Calls[0].Status != 1 {
+		t.Errorf("transfer funded in the previous block failed: %+v", results[1].Calls[0].Error)
+	}
+	if number := new(big.Int).SetBytes(results[1].Calls[1].ReturnValue); number.Int64() != 5 {
+		t.Errorf("block number mismatch: have %d, want 5", number)
+	}
+	if results[1].GasUsed != results[1].Calls[0].GasUsed+results[1].Calls[1].GasUsed {
+		t.Errorf("block gas used mismatch: have %d", results[1].GasUsed)
+	}
+
+	// The nonces are checked in validation mode
+	nonce := hexutil.Uint64(1)
+	_, err = api.Simulate(context.Background(), SimulateOpts{
+		Validation: true,
+		BlockStateCalls: []SimulateBlock{{
+			Calls: []TransactionArgs{{From: &accounts[0].addr, To: &accounts[1].addr, Nonce: &nonce}},
+		}},
+	}, &latest)
+	if !errors.Is(err, core.ErrNonceTooHigh) {
+		t.Errorf("error mismatch: have %v, want %v", err, core.ErrNonceTooHigh)
+	}
+	// The timestamps must be increasing
+	past := hexutil.Uint64(0)
+	_, err = api.Simulate(context.Background(), SimulateOpts{
+		BlockStateCalls: []SimulateBlock{{BlockOverrides: &BlockOverrides{Time: &past}}},
+	}, &latest)
+	if err == nil {
+		t.Error("expected error for the timestamp before the parent")
+	}
+}
+
 type Account struct {
 	key  *ecdsa.PrivateKey
 	addr common.Address
@@ -1317,3 +1456,86 @@ func newRPCBytes(bytes []byte) *hexutil.Bytes {
 	rpcBytes := hexutil.Bytes(bytes)
 	return &rpcBytes
 }
+
+func TestInternalTransactionApi(t *testing.T) {
+	t.Parallel()
+	var (
+		accounts = newAccounts(2)
+		genesis  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: core.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			},
+		}
+		signer = types.HomesteadSigner{}
+	)
+	b := newTestBackend(t, 2, genesis, ethash.NewFaker(), func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{Nonce: uint64(i), To: &accounts[1].addr, Value: big.NewInt(1000), Gas: params.TxGas, GasPrice: b.BaseFee()}), signer, accounts[0].key)
+		b.AddTx(tx)
+	})
+	block := b.chain.GetBlockByNumber(1)
+	txHash := block.Transactions()[0].Hash()
+	internalTxs := make([]*types.InternalTransaction, 0)
+	for i := 0; i < 5; i++ {
+		parent := txHash
+		if i%2 == 1 {
+			parent = common.Hash{0x01}
+		}
+		internalTxs = append(internalTxs, &types.InternalTransaction{
+			Opcode:  vm.CALL.String(),
+			Type:    types.InternalTransactionContractCall,
+			Success: true,
+			InternalTransactionBody: &types.InternalTransactionBody{
+				Order:           uint64(i),
+				TransactionHash: parent,
+				Value:           big.NewInt(int64(i)),
+				From:            accounts[0].addr,
+				To:              accounts[1].addr,
+				Height:          block.NumberU64(),
+				BlockHash:       block.Hash(),
+				BlockTime:       block.Time(),
+			},
+		})
+	}
+	rawdb.WriteInternalTransactions(b.db, block.Hash(), internalTxs)
+
+	api := NewPublicInternalTransactionAPI(b)
+	ctx := context.Background()
+
+	// Nothing is served unless the internal transactions are stored
+	_, err := api.GetInternalTransactionsByBlockNumber(ctx, 1, nil, nil)
+	require.ErrorIs(t, err, errInternalTxsDisabled)
+	rawdb.WriteStoreInternalTransactionsEnabled(b.db, true)
+
+	page, err := api.GetInternalTransactionsByBlockNumber(ctx, 1, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, hexutil.Uint(5), page.Total)
+	require.Len(t, page.Transactions, 5)
+	require.Equal(t, internalTxs[3].Hash(), page.Transactions[3].Hash)
+
+	offset, limit := hexutil.Uint(3), hexutil.Uint(3)
+	page, err = api.GetInternalTransactionsByBlockHash(ctx, block.Hash(), &offset, &limit)
+	require.NoError(t, err)
+	require.Equal(t, hexutil.Uint(5), page.Total)
+	require.Len(t, page.Transactions, 2)
+	require.Equal(t, hexutil.Uint64(3), page.Transactions[0].Order)
+
+	limit = 0
+	_, err = api.GetInternalTransactionsByBlockHash(ctx, block.Hash(), nil, &limit)
+	require.Error(t, err)
+
+	page, err = api.GetInternalTransactionsByTxHash(ctx, txHash, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, hexutil.Uint(3), page.Total)
+	for _, itx := range page.Transactions {
+		require.Equal(t, txHash, itx.TransactionHash)
+	}
+
+	// Blocks which are not part of the canonical chain must be rejected
+	sideHeader := types.CopyHeader(block.Header())
+	sideHeader.Extra = []byte("side")
+	rawdb.WriteHeader(b.db, sideHeader)
+	rawdb.WriteInternalTransactions(b.db, sideHeader.Hash(), internalTxs)
+	_, err = api.GetInternalTransactionsByBlockHash(ctx, sideHeader.Hash(), nil, nil)
+	require.ErrorIs(t, err, errNonCanonicalBlock)
+}
@@ -0,0 +1,400 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// maxSimulateBlocks is the maximum number of blocks simulated in a request.
+	maxSimulateBlocks = 256
+
+	// simulateTimestampIncrement is the default time between the simulated
+	// blocks if the chain has no block period.
+	simulateTimestampIncrement = 12
+)
+
+var (
+	// transferAddress is the emitter of the synthetic logs of the ether transfers,
+	// following the ERC-7528 convention for the native asset.
+	transferAddress = common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+
+	// transferTopic is the topic of the synthetic logs of the ether transfers,
+	// the same as the ERC-20 Transfer event.
+	transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+	errSimulateGasBudget = errors.New("simulation gas budget exhausted")
+)
+
+// SimulateBlock is a batch of calls executed sequentially in a simulated block,
+// on top of the state overrides.
+type SimulateBlock struct {
+	BlockOverrides *BlockOverrides   `json:"blockOverrides"`
+	StateOverrides *StateOverride    `json:"stateOverrides"`
+	Calls          []TransactionArgs `json:"calls"`
+}
+
+// SimulateOpts is the input of eth_simulate.
+type SimulateOpts struct {
+	BlockStateCalls []SimulateBlock `json:"blockStateCalls"`
+	TraceTransfers  bool            `json:"traceTransfers"` // emit a synthetic log for every ether transfer
+	Validation      bool            `json:"validation"`     // check the nonces, balances and base fee as for transactions
+}
+
+// SimulateCallError is the error of a failed simulated call.
+type SimulateCallError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+// SimulateCallResult is the result of a simulated call.
+type SimulateCallResult struct {
+	ReturnValue hexutil.Bytes      `json:"returnData"`
+	Logs        []*types.Log       `json:"logs"`
+	GasUsed     hexutil.Uint64     `json:"gasUsed"`
+	Status      hexutil.Uint64     `json:"status"`
+	Error       *SimulateCallError `json:"error,omitempty"`
+}
+
+// SimulateBlockResult is the result of a simulated block.
+type SimulateBlockResult struct {
+	Number       hexutil.Uint64       `json:"number"`
+	Hash         common.Hash          `json:"hash"`
+	ParentHash   common.Hash          `json:"parentHash"`
+	Timestamp    hexutil.Uint64       `json:"timestamp"`
+	GasLimit     hexutil.Uint64       `json:"gasLimit"`
+	GasUsed      hexutil.Uint64       `json:"gasUsed"`
+	FeeRecipient common.Address       `json:"feeRecipient"`
+	BaseFee      *hexutil.Big         `json:"baseFeePerGas,omitempty"`
+	StateRoot    common.Hash          `json:"stateRoot"`
+	Calls        []SimulateCallResult `json:"calls"`
+}
+
+// Simulate executes the calls of a series of simulated blocks on top of the state
+// of the given block, each block and each call building on the results of the
+// previous ones.
+//
+// Note, this function doesn't make any changes in the state/blockchain.
+func (s *PublicBlockChainAPI) Simulate(ctx context.Context, opts SimulateOpts, blockNrOrHash *rpc.BlockNumberOrHash) ([]*SimulateBlockResult, error) {
+	if len(opts.BlockStateCalls) == 0 {
+		return nil, errors.New("empty input")
+	}
+	if len(opts.BlockStateCalls) > maxSimulateBlocks {
+		return nil, fmt.Errorf("too many blocks: %d > %d", len(opts.BlockStateCalls), maxSimulateBlocks)
+	}
+	if blockNrOrHash == nil {
+		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &latest
+	}
+	state, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, *blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	// Setup context so it may be cancelled when the simulation has completed or
+	// timed out.
+	var cancel context.CancelFunc
+	if timeout := s.b.RPCEVMTimeout(); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	sim := &simulator{
+		b:       s.b,
+		state:   state,
+		opts:    opts,
+		gasCap:  s.b.RPCGasCap(),
+		budget:  s.b.RPCGasCap(),
+		headers: make(map[common.Hash]*types.Header),
+	}
+	results := make([]*SimulateBlockResult, 0, len(opts.BlockStateCalls))
+	for i, block := range opts.BlockStateCalls {
+		if header, err = sim.makeHeader(header, block.BlockOverrides); err != nil {
+			return nil, fmt.Errorf("block %d: %w", i, err)
+		}
+		if err := block.StateOverrides.Apply(state); err != nil {
+			return nil, fmt.Errorf("block %d: %w", i, err)
+		}
+		result, err := sim.processBlock(ctx, header, block.Calls)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", i, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// simulator holds the state of a simulation across the simulated blocks.
+type simulator struct {
+	b       Backend
+	state   *state.StateDB
+	opts    SimulateOpts
+	gasCap  uint64                        // gas cap of the whole simulation, 0 if unlimited
+	budget  uint64                        // gas left in the cap
+	headers map[common.Hash]*types.Header // simulated headers for the BLOCKHASH opcode
+}
+
+// makeHeader creates the header of the next simulated block on top of the
+// parent one.
+func (sim *simulator) makeHeader(parent *types.Header, overrides *BlockOverrides) (*types.Header, error) {
+	config := sim.b.ChainConfig()
+	period := uint64(simulateTimestampIncrement)
+	if config.Consortium != nil && config.Consortium.Period > 0 {
+		period = config.Consortium.Period
+	}
+	header := overrides.MakeHeader(&types.Header{
+		ParentHash: parent.Hash(),
+		Coinbase:   parent.Coinbase,
+		Difficulty: new(big.Int).Set(parent.Difficulty),
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + period,
+	})
+	if header.Number.Cmp(parent.Number) <= 0 {
+		return nil, fmt.Errorf("block number %d is not above the parent %d", header.Number, parent.Number)
+	}
+	if header.Time <= parent.Time {
+		return nil, fmt.Errorf("block timestamp %d is not above the parent %d", header.Time, parent.Time)
+	}
+	if header.BaseFee == nil && config.IsLondon(header.Number) {
+		// The base fee is only enforced in validation mode, the calls are free
+		// otherwise as in eth_call.
+		if sim.opts.Validation {
+			header.BaseFee = eip1559.CalcBaseFee(config, parent)
+		} else {
+			header.BaseFee = new(big.Int)
+		}
+	}
+	return header, nil
+}
+
+// processBlock executes the calls of the simulated block and finalizes its
+// header.
+func (sim *simulator) processBlock(ctx context.Context, header *types.Header, calls []TransactionArgs) (*SimulateBlockResult, error) {
+	var (
+		config   = sim.b.ChainConfig()
+		chain    = &simulatedChainContext{ChainContext: NewChainContext(ctx, sim.b), headers: sim.headers}
+		blockCtx = core.NewEVMBlockContext(header, chain, &header.Coinbase)
+		vmConfig = &vm.Config{NoBaseFee: !sim.opts.Validation}
+		gp       = new(core.GasPool).AddGas(header.GasLimit)
+		results  = make([]SimulateCallResult, 0, len(calls))
+		txHashes = make([]common.Hash, 0, len(calls))
+	)
+	if sim.opts.TraceTransfers {
+		vmConfig.Tracer = new(transferTracer)
+	}
+	for i, args := range calls {
+		if sim.gasCap != 0 && sim.budget == 0 {
+			return nil, errSimulateGasBudget
+		}
+		msg, err := sim.toMessage(&args, header, header.GasLimit-header.GasUsed)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		txHash := simulatedTxHash(header.Number, i)
+		sim.state.SetTxContext(txHash, i)
+
+		evm, vmError, err := sim.b.GetEVM(ctx, msg, sim.state, header, vmConfig, &blockCtx)
+		if err != nil {
+			return nil, err
+		}
+		// Cancel the evm once the simulation is aborted
+		done := make(chan struct{})
+		go func() {
+			select {
+			case <-ctx.Done():
+				evm.Cancel()
+			case <-done:
+			}
+		}()
+		result, err := core.ApplyMessage(evm, msg, gp)
+		close(done)
+		if err := vmError(); err != nil {
+			return nil, err
+		}
+		if evm.Cancelled() {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", sim.b.RPCEVMTimeout())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		sim.state.Finalise(config.IsEIP158(header.Number))
+		header.GasUsed += result.UsedGas
+		if sim.gasCap != 0 {
+			sim.budget -= result.UsedGas
+		}
+
+		call := SimulateCallResult{
+			ReturnValue: result.Return(),
+			GasUsed:     hexutil.Uint64(result.UsedGas),
+			Status:      hexutil.Uint64(types.ReceiptStatusSuccessful),
+		}
+		if result.Failed() {
+			call.Status = hexutil.Uint64(types.ReceiptStatusFailed)
+			if revert := result.Revert(); len(revert) > 0 {
+				err := newRevertError(revert)
+				call.ReturnValue = revert
+				call.Error = &SimulateCallError{Code: err.ErrorCode(), Message: err.Error(), Data: err.reason}
+			} else {
+				call.Error = &SimulateCallError{Code: -32015, Message: result.Err.Error()}
+			}
+		}
+		results = append(results, call)
+		txHashes = append(txHashes, txHash)
+	}
+	header.Root = sim.state.IntermediateRoot(config.IsEIP158(header.Number))
+	hash := header.Hash()
+	sim.headers[hash] = header
+
+	for i := range results {
+		results[i].Logs = sim.state.GetLogs(txHashes[i], hash)
+		for _, log := range results[i].Logs {
+			log.BlockNumber = header.Number.Uint64()
+		}
+		if results[i].Logs == nil {
+			results[i].Logs = []*types.Log{}
+		}
+	}
+	result := &SimulateBlockResult{
+		Number:       hexutil.Uint64(header.Number.Uint64()),
+		Hash:         hash,
+		ParentHash:   header.ParentHash,
+		Timestamp:    hexutil.Uint64(header.Time),
+		GasLimit:     hexutil.Uint64(header.GasLimit),
+		GasUsed:      hexutil.Uint64(header.GasUsed),
+		FeeRecipient: header.Coinbase,
+		StateRoot:    header.Root,
+		Calls:        results,
+	}
+	if header.BaseFee != nil {
+		result.BaseFee = (*hexutil.Big)(header.BaseFee)
+	}
+	return result, nil
+}
+
+// toMessage converts the call arguments to a message, the gas defaults to what is
+// left in the block and is capped by the gas budget. In validation mode the nonce
+// defaults to the one of the sender, and the message is checked as a transaction.
+func (sim *simulator) toMessage(args *TransactionArgs, header *types.Header, gasLeft uint64) (types.Message, error) {
+	if args.Gas == nil {
+		gas := hexutil.Uint64(gasLeft)
+		args.Gas = &gas
+	}
+	if sim.gasCap != 0 && uint64(*args.Gas) > sim.budget {
+		gas := hexutil.Uint64(sim.budget)
+		args.Gas = &gas
+	}
+	msg, err := args.ToMessage(sim.budget, header.BaseFee)
+	if err != nil || !sim.opts.Validation {
+		return msg, err
+	}
+	nonce := sim.state.GetNonce(msg.From())
+	if args.Nonce != nil {
+		nonce = uint64(*args.Nonce)
+	}
+	validated := types.NewMessage(msg.From(), msg.To(), nonce, msg.Value(), msg.Gas(), msg.GasPrice(), msg.GasFeeCap(), msg.GasTipCap(), msg.Data(), msg.AccessList(), false, msg.BlobGasFeeCap(), msg.BlobHashes())
+	if args.IsSponsored() {
+		validated = validated.WithPayer(msg.Payer(), msg.ExpiredTime())
+	}
+	return validated, nil
+}
+
+// simulatedTxHash returns the hash identifying the simulated call in the logs,
+// derived from the block number and the call index.
+func simulatedTxHash(number *big.Int, index int) common.Hash {
+	var enc [16]byte
+	binary.BigEndian.PutUint64(enc[:8], number.Uint64())
+	binary.BigEndian.PutUint64(enc[8:], uint64(index))
+	return crypto.Keccak256Hash(enc[:])
+}
+
+// simulatedChainContext resolves the simulated headers as well as the canonical
+// ones for the BLOCKHASH opcode.
+type simulatedChainContext struct {
+	*ChainContext
+	headers map[common.Hash]*types.Header
+}
+
+func (context *simulatedChainContext) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header, ok := context.headers[hash]; ok {
+		return header
+	}
+	return context.ChainContext.GetHeader(hash, number)
+}
+
+// transferTracer emits a synthetic log for every ether transfer, so that the
+// native transfers are tracked along with the token ones. The logs are added to
+// the state, hence reverted with the call frame transferring the ether.
+type transferTracer struct {
+	statedb vm.StateDB
+}
+
+func (t *transferTracer) CaptureTxStart(gasLimit uint64, payer *common.Address) {}
+
+func (t *transferTracer) CaptureTxEnd(restGas uint64) {}
+
+func (t *transferTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.statedb = env.StateDB
+	t.addTransfer(from, to, value)
+}
+
+func (t *transferTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {}
+
+func (t *transferTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// The value of the delegate calls is the one of the parent frame, and the
+	// call codes transfer the value to the caller itself
+	if typ == vm.DELEGATECALL || typ == vm.CALLCODE {
+		return
+	}
+	t.addTransfer(from, to, value)
+}
+
+func (t *transferTracer) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+func (t *transferTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (t *transferTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+func (t *transferTracer) addTransfer(from, to common.Address, value *big.Int) {
+	if value == nil || value.Sign() == 0 {
+		return
+	}
+	t.statedb.AddLog(&types.Log{
+		Address: transferAddress,
+		Topics:  []common.Hash{transferTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:    common.BigToHash(value).Bytes(),
+	})
+}
@@ -28,7 +28,9 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
@@ -66,6 +68,11 @@ type TransactionArgs struct {
 	Commitments []kzg4844.Commitment `json:"commitments"`
 	Proofs      []kzg4844.Proof      `json:"proofs"`
 
+	// Introduced by SponsoredTxType transaction, the gas fee is paid by the payer
+	// and the payer signature is valid until the expired time.
+	Payer       *common.Address `json:"payer,omitempty"`
+	ExpiredTime *hexutil.Uint64 `json:"expiredTime,omitempty"`
+
 	// This configures whether blobs are allowed to be passed.
 	blobSidecarAllowed bool
 }
@@ -78,6 +85,34 @@ func (arg *TransactionArgs) from() common.Address {
 	return *arg.From
 }
 
+// expiredTime retrieves the expired time of the payer signature in sponsored
+// transaction.
+func (arg *TransactionArgs) expiredTime() uint64 {
+	if arg.ExpiredTime == nil {
+		return 0
+	}
+	return uint64(*arg.ExpiredTime)
+}
+
+// IsSponsored returns an indicator if the args contains a payer.
+func (args *TransactionArgs) IsSponsored() bool {
+	return args.Payer != nil
+}
+
+// checkSponsored checks the fields not supported in sponsored transaction.
+func (args *TransactionArgs) checkSponsored() error {
+	if *args.Payer == args.from() {
+		return types.ErrSamePayerSenderSponsoredTx
+	}
+	if args.IsEIP4844() {
+		return errors.New("blobs are not supported in sponsored transaction")
+	}
+	if args.AccessList != nil {
+		return errors.New("access list is not supported in sponsored transaction")
+	}
+	return nil
+}
+
 // data retrieves the transaction calldata. Input field is preferred.
 func (arg *TransactionArgs) data() []byte {
 	if arg.Input != nil {
@@ -100,6 +135,43 @@ func (args *TransactionArgs) setDefaults(ctx context.Context, b Backend) error {
 
 	head := b.CurrentHeader()
 
+	if args.IsSponsored() {
+		if err := args.checkSponsored(); err != nil {
+			return err
+		}
+		if expiredTime := args.expiredTime(); expiredTime != 0 && expiredTime <= head.Time {
+			return fmt.Errorf("%w: expiredTime: %d, blockTime: %d", core.ErrExpiredSponsoredTx, expiredTime, head.Time)
+		}
+		// Sponsored transaction only has the 1559 fee fields, before Venoki they
+		// must be the same so the legacy gas price suggestion is used for both.
+		if args.GasPrice != nil {
+			if args.MaxFeePerGas != nil || args.MaxPriorityFeePerGas != nil {
+				return errors.New("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
+			}
+			args.MaxFeePerGas, args.MaxPriorityFeePerGas, args.GasPrice = args.GasPrice, args.GasPrice, nil
+		}
+		if !b.ChainConfig().IsVenoki(head.Number) {
+			switch {
+			case args.MaxFeePerGas == nil && args.MaxPriorityFeePerGas == nil:
+				price, err := b.SuggestGasTipCap(ctx)
+				if err != nil {
+					return err
+				}
+				if head.BaseFee != nil {
+					price.Add(price, head.BaseFee)
+				}
+				args.MaxFeePerGas, args.MaxPriorityFeePerGas = (*hexutil.Big)(price), (*hexutil.Big)(price)
+			case args.MaxFeePerGas == nil:
+				args.MaxFeePerGas = args.MaxPriorityFeePerGas
+			case args.MaxPriorityFeePerGas == nil:
+				args.MaxPriorityFeePerGas = args.MaxFeePerGas
+			}
+			if args.MaxFeePerGas.ToInt().Cmp(args.MaxPriorityFeePerGas.ToInt()) != 0 {
+				return core.ErrDifferentFeeCapTipCap
+			}
+		}
+	}
+
 	// Sanity check the EIP-4844 fee parameters.
 	if args.BlobFeeCap != nil && args.BlobFeeCap.ToInt().Sign() == 0 {
 		return errors.New("maxFeePerBlobGas, if specified, must be non-zero")
@@ -211,6 +283,8 @@ func (args *TransactionArgs) setDefaults(ctx context.Context, b Backend) error {
 			Value:                args.Value,
 			Data:                 (*hexutil.Bytes)(&data),
 			AccessList:           args.AccessList,
+			Payer:                args.Payer,
+			ExpiredTime:          args.ExpiredTime,
 		}
 		pendingBlockNr := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
 		estimated, err := DoEstimateGas(ctx, b, callArgs, pendingBlockNr, nil, b.RPCGasCap())
@@ -237,6 +311,11 @@ func (args *TransactionArgs) ToMessage(globalGasCap uint64, baseFee *big.Int) (t
 	}
 	// Set sender address or use zero address if none specified.
 	addr := args.from()
+	if args.IsSponsored() {
+		if err := args.checkSponsored(); err != nil {
+			return types.Message{}, err
+		}
+	}
 
 	// Set default gas & gas price if none were set
 	gas := globalGasCap
@@ -298,6 +377,9 @@ func (args *TransactionArgs) ToMessage(globalGasCap uint64, baseFee *big.Int) (t
 		args.BlobFeeCap = new(hexutil.Big)
 	}
 	msg := types.NewMessage(addr, args.To, 0, value, gas, gasPrice, gasFeeCap, gasTipCap, data, accessList, true, (*big.Int)(args.BlobFeeCap), args.BlobHashes)
+	if args.IsSponsored() {
+		msg = msg.WithPayer(*args.Payer, args.expiredTime())
+	}
 	return msg, nil
 }
 
@@ -306,6 +388,8 @@ func (args *TransactionArgs) ToMessage(globalGasCap uint64, baseFee *big.Int) (t
 func (args *TransactionArgs) toTransaction() *types.Transaction {
 	var data types.TxData
 	switch {
+	case args.IsSponsored():
+		data = args.toSponsoredTx()
 	case len(args.BlobHashes) != 0:
 		al := types.AccessList{}
 		if args.AccessList != nil {
@@ -377,6 +461,42 @@ func (args *TransactionArgs) ToTransaction() *types.Transaction {
 	return args.toTransaction()
 }
 
+// toSponsoredTx converts the arguments to a sponsored transaction without the
+// payer signature. This assumes that setDefaults has been called.
+func (args *TransactionArgs) toSponsoredTx() *types.SponsoredTx {
+	return &types.SponsoredTx{
+		ChainID:     (*big.Int)(args.ChainID),
+		Nonce:       uint64(*args.Nonce),
+		GasTipCap:   (*big.Int)(args.MaxPriorityFeePerGas),
+		GasFeeCap:   (*big.Int)(args.MaxFeePerGas),
+		Gas:         uint64(*args.Gas),
+		To:          args.To,
+		Value:       (*big.Int)(args.Value),
+		Data:        args.data(),
+		ExpiredTime: args.expiredTime(),
+	}
+}
+
+// toPayerSignedTransaction converts the arguments to a sponsored transaction
+// signed by the payer, the payer hash is signed by signPayer. The transaction
+// still needs to be signed by the sender.
+// This assumes that setDefaults has been called.
+func (args *TransactionArgs) toPayerSignedTransaction(signer types.Signer, signPayer func(hash common.Hash) ([]byte, error)) (*types.Transaction, error) {
+	data := args.toSponsoredTx()
+	sig, err := signPayer(types.PayerHash(signer, args.from(), data))
+	if err != nil {
+		return nil, err
+	}
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("wrong size for payer signature: got %d, want %d", len(sig), crypto.SignatureLength)
+	}
+	// V in payer signature is {0, 1}
+	data.PayerR = new(big.Int).SetBytes(sig[:32])
+	data.PayerS = new(big.Int).SetBytes(sig[32:64])
+	data.PayerV = new(big.Int).SetUint64(uint64(sig[64]))
+	return types.NewTx(data), nil
+}
+
 // setBlobTxSidecar can generate commitments and proofs from blobs
 // before set these value to transaction args.
 func (args *TransactionArgs) setBlobTxSidecar(ctx context.Context) error {
@@ -189,6 +189,14 @@ web3._extend({
 			name: 'stopWS',
 			call: 'admin_stopWS'
 		}),
+		new web3._extend.Method({
+			name: 'reloadBLSKeys',
+			call: 'admin_reloadBLSKeys'
+		}),
+		new web3._extend.Method({
+			name: 'forkSchedule',
+			call: 'admin_forkSchedule'
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -592,6 +600,21 @@ web3._extend({
 			call: 'eth_getBlockReceipts',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'sendRawTransactionConditional',
+			call: 'eth_sendRawTransactionConditional',
+			params: 2,
+		}),
+		new web3._extend.Method({
+			name: 'callBundle',
+			call: 'eth_callBundle',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'sendBundle',
+			call: 'eth_sendBundle',
+			params: 1,
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -776,6 +799,11 @@ web3._extend({
 			call: 'txpool_contentFrom',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'contentFromPayer',
+			call: 'txpool_contentFromPayer',
+			params: 1,
+		}),
 	]
 });
 `
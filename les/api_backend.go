@@ -213,6 +213,10 @@ func (b *LesApiBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	return b.eth.txPool.Add(ctx, signedTx)
 }
 
+func (b *LesApiBackend) SendTxConditional(ctx context.Context, signedTx *types.Transaction, cond *types.TransactionConditional) error {
+	return errors.New("conditional transactions are not supported by light clients")
+}
+
 func (b *LesApiBackend) RemoveTx(txHash common.Hash) {
 	b.eth.txPool.RemoveTx(txHash)
 }
@@ -245,6 +249,16 @@ func (b *LesApiBackend) TxPoolContentFrom(addr common.Address) ([]*types.Transac
 	return b.eth.txPool.ContentFrom(addr)
 }
 
+func (b *LesApiBackend) TxPoolContentFromPayer(payer common.Address) (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
+	return b.eth.txPool.ContentFromPayer(payer)
+}
+
+// TxPoolPayerCosts returns nothing, the light pool does not track the costs
+// charged to the payers.
+func (b *LesApiBackend) TxPoolPayerCosts() map[common.Address]*big.Int {
+	return map[common.Address]*big.Int{}
+}
+
 func (b *LesApiBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
 	return b.eth.txPool.SubscribeNewTxsEvent(ch)
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/mclock"
 	"github.com/ethereum/go-ethereum/consensus"
+	v2 "github.com/ethereum/go-ethereum/consensus/consortium/v2"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -77,6 +78,8 @@ type LightEthereum struct {
 	p2pServer  *p2p.Server
 	p2pConfig  *p2p.Config
 	udpEnabled bool
+
+	consortium *v2.LightVerifier // Consortium v2 header verifier, nil unless synced from a trusted epoch checkpoint
 }
 
 // New creates an instance of the light client.
@@ -137,6 +140,13 @@ func New(stack *node.Node, config *ethconfig.Config) (*LightEthereum, error) {
 	if checkpoint == nil {
 		checkpoint = params.TrustedCheckpoints[genesisHash]
 	}
+	// Verify the consortium v2 headers from the trusted epoch checkpoint on,
+	// without the snapshots which require the ancestors and the state
+	if config.ConsortiumCheckpoint != nil {
+		if err := leth.setupConsortiumLight(); err != nil {
+			return nil, err
+		}
+	}
 	// Note: NewLightChain adds the trusted checkpoint so it needs an ODR with
 	// indexers already set but not started yet
 	if leth.blockchain, err = light.NewLightChain(leth.odr, leth.chainConfig, leth.engine, checkpoint); err != nil {
@@ -433,37 +433,46 @@ func (pc *peerConnection) RequestHeadersByNumber(origin uint64, amount int, skip
 // RetrieveSingleHeaderByNumber requests a single header by the specified block
 // number. This function will wait the response until it's timeout or delivered.
 func (pc *peerConnection) RetrieveSingleHeaderByNumber(context context.Context, number uint64) (*types.Header, error) {
+	headers, err := pc.RetrieveHeadersByNumber(context, number, 1)
+	if err != nil {
+		return nil, err
+	}
+	return headers[0], nil
+}
+
+// RetrieveHeadersByNumber requests a batch of contiguous headers from the specified
+// block number. This function will wait the response until it's timeout or delivered.
+func (pc *peerConnection) RetrieveHeadersByNumber(context context.Context, origin uint64, amount int) ([]*types.Header, error) {
 	reqID := rand.Uint64()
 	rq := &distReq{
 		getCost: func(dp distPeer) uint64 {
 			peer := dp.(*serverPeer)
-			return peer.getRequestCost(GetBlockHeadersMsg, 1)
+			return peer.getRequestCost(GetBlockHeadersMsg, amount)
 		},
 		canSend: func(dp distPeer) bool {
 			return dp.(*serverPeer) == pc.peer
 		},
 		request: func(dp distPeer) func() {
 			peer := dp.(*serverPeer)
-			cost := peer.getRequestCost(GetBlockHeadersMsg, 1)
+			cost := peer.getRequestCost(GetBlockHeadersMsg, amount)
 			peer.fcServer.QueuedRequest(reqID, cost)
-			return func() { peer.requestHeadersByNumber(reqID, number, 1, 0, false) }
+			return func() { peer.requestHeadersByNumber(reqID, origin, amount, 0, false) }
 		},
 	}
-	var header *types.Header
+	var headers []*types.Header
 	if err := pc.handler.backend.retriever.retrieve(context, reqID, rq, func(peer distPeer, msg *Msg) error {
 		if msg.MsgType != MsgBlockHeaders {
 			return errInvalidMessageType
 		}
-		headers := msg.Obj.([]*types.Header)
-		if len(headers) != 1 {
+		headers = msg.Obj.([]*types.Header)
+		if len(headers) != amount {
 			return errInvalidEntryCount
 		}
-		header = headers[0]
 		return nil
 	}, nil); err != nil {
 		return nil, err
 	}
-	return header, nil
+	return headers, nil
 }
 
 // downloaderPeerNotify implements peerSetNotify
@@ -0,0 +1,225 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	v2 "github.com/ethereum/go-ethereum/consensus/consortium/v2"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/les/downloader"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	// consortiumCheckpointKey is the key of the latest finalized consortium
+	// epoch checkpoint in the les client database
+	consortiumCheckpointKey = []byte("consortium:checkpoint")
+
+	errInvalidConsortiumCheckpoint = errors.New("trusted consortium checkpoint hash mismatch")
+	errInvalidConsortiumHead       = errors.New("invalid consortium head td")
+)
+
+// consortiumRequestTimeout is the timeout of the header requests while syncing
+// the consortium epoch checkpoints
+const consortiumRequestTimeout = 10 * time.Second
+
+// consortiumLightEngine wraps the consortium engine to verify the headers with
+// the light verifier, which needs neither the snapshots nor the state.
+type consortiumLightEngine struct {
+	consensus.Engine
+	verifier *v2.LightVerifier
+}
+
+// VerifyHeader implements consensus.Engine, verifying the header against the
+// validator set of the latest finalized epoch checkpoint.
+func (e *consortiumLightEngine) VerifyHeader(chain consensus.ChainHeaderReader, header *types.Header, seal bool) error {
+	return e.verifier.VerifyHeader(chain, header, nil)
+}
+
+// VerifyHeaders implements consensus.Engine, verifying a batch of headers
+// concurrently.
+func (e *consortiumLightEngine) VerifyHeaders(chain consensus.ChainHeaderReader, headers []*types.Header, seals []bool) (chan<- struct{}, <-chan error) {
+	abort := make(chan struct{})
+	results := make(chan error, len(headers))
+
+	go func() {
+		for i, header := range headers {
+			err := e.verifier.VerifyHeader(chain, header, headers[:i])
+			select {
+			case <-abort:
+				return
+			case results <- err:
+			}
+		}
+	}()
+	return abort, results
+}
+
+// readConsortiumCheckpoint retrieves the latest finalized consortium epoch
+// checkpoint from the database.
+func readConsortiumCheckpoint(db ethdb.KeyValueReader) *v2.LightCheckpoint {
+	data, _ := db.Get(consortiumCheckpointKey)
+	if len(data) == 0 {
+		return nil
+	}
+	checkpoint := new(v2.LightCheckpoint)
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		log.Error("Invalid consortium checkpoint", "err", err)
+		return nil
+	}
+	return checkpoint
+}
+
+// writeConsortiumCheckpoint stores the latest finalized consortium epoch
+// checkpoint into the database.
+func writeConsortiumCheckpoint(db ethdb.KeyValueWriter, checkpoint *v2.LightCheckpoint) {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		log.Crit("Failed to encode consortium checkpoint", "err", err)
+	}
+	if err := db.Put(consortiumCheckpointKey, data); err != nil {
+		log.Crit("Failed to store consortium checkpoint", "err", err)
+	}
+}
+
+// setupConsortiumLight wraps the consensus engine to verify the headers from
+// the configured trusted checkpoint on, resuming from the latest finalized
+// checkpoint if any.
+func (s *LightEthereum) setupConsortiumLight() error {
+	trusted := s.config.ConsortiumCheckpoint
+	checkpoint := readConsortiumCheckpoint(s.lesDb)
+	if checkpoint != nil && checkpoint.Number < trusted.Number {
+		log.Info("Discarding consortium checkpoint before the trusted one", "number", checkpoint.Number, "trusted", trusted.Number)
+		checkpoint = nil
+	}
+	verifier, err := v2.NewLightVerifier(s.chainConfig, checkpoint)
+	if err != nil {
+		return err
+	}
+	s.consortium = verifier
+	s.engine = &consortiumLightEngine{Engine: s.engine, verifier: verifier}
+	log.Info("Consortium light sync enabled", "trusted", trusted.Number, "hash", trusted.Hash)
+	return nil
+}
+
+// consortiumSync walks the consortium epoch checkpoints from the trusted one up
+// to the head announced by the peer.
+func (h *clientHandler) consortiumSync(peer *serverPeer) error {
+	conn := &peerConnection{handler: h, peer: peer}
+	retrieve := func(origin uint64, amount int) ([]*types.Header, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), consortiumRequestTimeout)
+		defer cancel()
+		return conn.RetrieveHeadersByNumber(ctx, origin, amount)
+	}
+	peer.lock.RLock()
+	head := peer.headInfo
+	peer.lock.RUnlock()
+
+	return syncConsortium(h.backend.chainConfig, h.backend.consortium, h.backend.blockchain, h.backend.lesDb, h.backend.config.ConsortiumCheckpoint, head, retrieve)
+}
+
+// syncConsortium walks the consortium epoch checkpoints from the trusted one up
+// to the head, each of them is finalized by the validator set of the previous
+// one. If the local chain is behind the latest finalized checkpoint, its head is
+// set to the checkpoint and the headers after it are imported, so that the state
+// and the blocks are served from the checkpoint on.
+//
+// The total difficulty announced by the peer is only checked to be in range, the
+// one of the checkpoint is the lowest the chain may have at its number, so that
+// a peer lying about it can not get the local chain ahead of the honest peers.
+func syncConsortium(chainConfig *params.ChainConfig, verifier *v2.LightVerifier, chain *light.LightChain, db ethdb.KeyValueStore, trusted *ethconfig.ConsortiumCheckpoint, head blockInfo, retrieve func(origin uint64, amount int) ([]*types.Header, error)) error {
+	genesis := chain.Genesis()
+	genesisTd := chain.GetTd(genesis.Hash(), 0)
+	if err := v2.VerifyLightTotalDifficulty(genesisTd, head.Number, head.Td); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidConsortiumHead, err)
+	}
+	if verifier.Checkpoint() == nil {
+		headers, err := retrieve(trusted.Number-1, 2)
+		if err != nil {
+			return err
+		}
+		if len(headers) != 2 || headers[1].Hash() != trusted.Hash {
+			return errInvalidConsortiumCheckpoint
+		}
+		checkpoint, err := v2.NewLightCheckpoint(chainConfig, headers[1], headers[0], nil)
+		if err != nil {
+			return err
+		}
+		verifier.SetCheckpoint(checkpoint)
+		writeConsortiumCheckpoint(db, checkpoint)
+	}
+	for {
+		origin, amount, err := verifier.NextCheckpoint()
+		if err != nil {
+			return err
+		}
+		if origin+uint64(amount) > head.Number+1 {
+			break
+		}
+		headers, err := retrieve(origin, amount)
+		if err != nil {
+			return err
+		}
+		checkpoint, err := verifier.VerifyCheckpoint(headers)
+		if err != nil {
+			return err
+		}
+		writeConsortiumCheckpoint(db, checkpoint)
+		log.Debug("Verified consortium checkpoint", "number", checkpoint.Number, "hash", checkpoint.Hash, "validators", len(checkpoint.Validators))
+	}
+
+	// The headers are verified from the first checkpoint walked from the trusted
+	// one, the validator set before the trusted one is unknown
+	checkpoint := verifier.Checkpoint()
+	if checkpoint.Bootstrap() || chain.CurrentHeader().Number.Uint64() >= checkpoint.Number || head.Number < checkpoint.Number {
+		return nil
+	}
+	var headers []*types.Header
+	for origin := checkpoint.Number; origin <= head.Number; {
+		amount := uint64(downloader.MaxHeaderFetch)
+		if remaining := head.Number - origin + 1; remaining < amount {
+			amount = remaining
+		}
+		batch, err := retrieve(origin, int(amount))
+		if err != nil {
+			return err
+		}
+		headers = append(headers, batch...)
+		origin += amount
+	}
+	if headers[0].Hash() != checkpoint.Hash {
+		return errInvalidConsortiumCheckpoint
+	}
+	if chain.SetTrustedHead(headers[0], v2.LightTotalDifficulty(genesisTd, checkpoint.Number)) {
+		if _, err := chain.InsertHeaderChain(headers[1:], 0); err != nil {
+			return err
+		}
+	}
+	writeConsortiumCheckpoint(db, verifier.Checkpoint())
+	log.Info("Synced consortium checkpoint", "number", checkpoint.Number, "hash", checkpoint.Hash, "head", head.Number)
+	return nil
+}
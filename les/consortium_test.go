@@ -0,0 +1,286 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	consortiumCommon "github.com/ethereum/go-ethereum/consensus/consortium/common"
+	v2 "github.com/ethereum/go-ethereum/consensus/consortium/v2"
+	"github.com/ethereum/go-ethereum/consensus/consortium/v2/finality"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/bls/blst"
+	blsCommon "github.com/ethereum/go-ethereum/crypto/bls/common"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"golang.org/x/crypto/sha3"
+)
+
+const consortiumTestDay = uint64(86400)
+
+type consortiumTestValidator struct {
+	ecdsaKey *ecdsa.PrivateKey
+	blsKey   blsCommon.SecretKey
+	info     finality.ValidatorWithBlsPub
+}
+
+func newConsortiumTestValidators(t *testing.T, n int) []consortiumTestValidator {
+	validators := make([]consortiumTestValidator, n)
+	for i := range validators {
+		ecdsaKey, _ := crypto.GenerateKey()
+		blsKey, err := blst.RandKey()
+		if err != nil {
+			t.Fatalf("Failed to generate bls key, err %s", err)
+		}
+		validators[i] = consortiumTestValidator{
+			ecdsaKey: ecdsaKey,
+			blsKey:   blsKey,
+			info: finality.ValidatorWithBlsPub{
+				Address:      crypto.PubkeyToAddress(ecdsaKey.PublicKey),
+				BlsPublicKey: blsKey.PublicKey(),
+				Weight:       consortiumCommon.MaxFinalityVotePercentage / uint16(n),
+			},
+		}
+	}
+	return validators
+}
+
+// sealConsortiumTestHeader signs the header as the consortium v2 engine does.
+func sealConsortiumTestHeader(t *testing.T, chainConfig *params.ChainConfig, header *types.Header, key *ecdsa.PrivateKey) {
+	hasher := sha3.NewLegacyKeccak256()
+	rlp.Encode(hasher, []interface{}{
+		chainConfig.ChainID,
+		header.ParentHash,
+		header.UncleHash,
+		header.Coinbase,
+		header.Root,
+		header.TxHash,
+		header.ReceiptHash,
+		header.Bloom,
+		header.Difficulty,
+		header.Number,
+		header.GasLimit,
+		header.GasUsed,
+		header.Time,
+		header.Extra[:len(header.Extra)-consortiumCommon.ExtraSeal],
+		header.MixDigest,
+		header.Nonce,
+	})
+	var hash common.Hash
+	hasher.Sum(hash[:0])
+	sig, err := crypto.Sign(hash[:], key)
+	if err != nil {
+		t.Fatalf("Failed to sign header, err %s", err)
+	}
+	copy(header.Extra[len(header.Extra)-consortiumCommon.ExtraSeal:], sig)
+}
+
+// newConsortiumTestChain generates the headers from 9 to 45 of a chain whose
+// epoch checkpoints 10 and 20 are in one period, and 30 and 40 in the next one.
+// The first set of validators is carried by the checkpoint 10, the second one by
+// the period block 30, and each checkpoint is finalized before its validator set
+// takes effect.
+func newConsortiumTestChain(t *testing.T, chainConfig *params.ChainConfig, first, second []consortiumTestValidator) []*types.Header {
+	var (
+		headers []*types.Header
+		parent  common.Hash
+	)
+	dayStart := (uint64(time.Now().Unix())/consortiumTestDay - 1) * consortiumTestDay
+	voted := map[uint64][]consortiumTestValidator{21: first[:3], 31: first[1:], 41: second[:3]}
+	for number := uint64(9); number <= 45; number++ {
+		// The validator set at the parent block, the second one takes effect at 32
+		set := first
+		if number-1 >= 32 {
+			set = second
+		}
+		extraData := &finality.HeaderExtraData{}
+		if number%10 == 0 {
+			next := first
+			if number >= 30 {
+				next = second
+			}
+			if number == 10 || number == 30 {
+				for _, validator := range next {
+					extraData.CheckpointValidators = append(extraData.CheckpointValidators, validator.info)
+				}
+			}
+			for _, validator := range next {
+				extraData.BlockProducers = append(extraData.BlockProducers, validator.info.Address)
+			}
+		}
+		if voters, ok := voted[number]; ok {
+			voteData := types.VoteData{TargetNumber: number - 1, TargetHash: parent}
+			digest := voteData.Hash()
+			var signatures []blsCommon.Signature
+			for _, voter := range voters {
+				for i, validator := range set {
+					if validator.info.Address == voter.info.Address {
+						extraData.FinalityVotedValidators.SetBit(i)
+					}
+				}
+				signatures = append(signatures, voter.blsKey.Sign(digest[:]))
+			}
+			extraData.HasFinalityVote = 1
+			extraData.AggregatedFinalityVotes = blst.AggregateSignatures(signatures)
+		}
+		producer := set[number%uint64(len(set))]
+		header := &types.Header{
+			ParentHash: parent,
+			Number:     new(big.Int).SetUint64(number),
+			Time:       dayStart + 5 + 3*number - 90,
+			Coinbase:   producer.info.Address,
+			Difficulty: big.NewInt(7),
+		}
+		extra, err := extraData.EncodeV2(chainConfig, header.Number)
+		if err != nil {
+			t.Fatalf("Failed to encode extra data, err %s", err)
+		}
+		header.Extra = extra
+		sealConsortiumTestHeader(t, chainConfig, header, producer.ecdsaKey)
+
+		headers = append(headers, header)
+		parent = header.Hash()
+	}
+	return headers
+}
+
+type consortiumTestOdr struct {
+	light.OdrBackend
+	db ethdb.Database
+}
+
+func (odr *consortiumTestOdr) Database() ethdb.Database {
+	return odr.db
+}
+
+func (odr *consortiumTestOdr) Retrieve(ctx context.Context, req light.OdrRequest) error {
+	return errors.New("not supported")
+}
+
+func (odr *consortiumTestOdr) IndexerConfig() *light.IndexerConfig {
+	return light.TestClientIndexerConfig
+}
+
+func TestConsortiumSync(t *testing.T) {
+	dayStart := (uint64(time.Now().Unix())/consortiumTestDay - 1) * consortiumTestDay
+	chainConfig := &params.ChainConfig{
+		ChainID:      big.NewInt(2021),
+		ShillinBlock: big.NewInt(0),
+		BubaBlock:    big.NewInt(0),
+		TrippBlock:   big.NewInt(0),
+		TrippPeriod:  new(big.Int).SetUint64(dayStart/consortiumTestDay - 2),
+		Consortium:   &params.ConsortiumConfig{EpochV2: 10, Period: 3},
+	}
+	first := newConsortiumTestValidators(t, 4)
+	second := newConsortiumTestValidators(t, 4)
+	headers := newConsortiumTestChain(t, chainConfig, first, second)
+	retrieve := func(origin uint64, amount int) ([]*types.Header, error) {
+		if origin < 9 || origin-9+uint64(amount) > uint64(len(headers)) {
+			return nil, errors.New("unknown headers")
+		}
+		return headers[origin-9 : origin-9+uint64(amount)], nil
+	}
+	trusted := &ethconfig.ConsortiumCheckpoint{Number: 10, Hash: headers[1].Hash()}
+	head := headers[len(headers)-1]
+
+	newClient := func() (*v2.LightVerifier, *light.LightChain, ethdb.Database) {
+		db := rawdb.NewMemoryDatabase()
+		gspec := &core.Genesis{Config: chainConfig, Difficulty: big.NewInt(1)}
+		gspec.MustCommit(db, trie.NewDatabase(db, nil))
+		verifier, err := v2.NewLightVerifier(chainConfig, nil)
+		if err != nil {
+			t.Fatalf("Failed to create light verifier, err %s", err)
+		}
+		engine := &consortiumLightEngine{Engine: ethash.NewFaker(), verifier: verifier}
+		chain, err := light.NewLightChain(&consortiumTestOdr{db: db}, chainConfig, engine, nil)
+		if err != nil {
+			t.Fatalf("Failed to create light chain, err %s", err)
+		}
+		return verifier, chain, db
+	}
+
+	// The checkpoints are walked across the period block switching the
+	// validator set, and the headers imported from the latest one
+	verifier, chain, db := newClient()
+	td := new(big.Int).SetUint64(1 + 7*head.Number.Uint64())
+	info := blockInfo{Hash: head.Hash(), Number: head.Number.Uint64(), Td: td}
+	if err := syncConsortium(chainConfig, verifier, chain, db, trusted, info, retrieve); err != nil {
+		t.Fatalf("Failed to sync consortium checkpoints, err %s", err)
+	}
+	checkpoint := verifier.Checkpoint()
+	if checkpoint.Number != 40 || checkpoint.Validators[0].Address != second[0].info.Address || checkpoint.PreviousValidators[0].Address != second[0].info.Address {
+		t.Fatalf("Unexpected checkpoint %d", checkpoint.Number)
+	}
+	if stored := readConsortiumCheckpoint(db); stored == nil || stored.Hash != checkpoint.Hash {
+		t.Fatal("Expect the latest checkpoint stored")
+	}
+	if current := chain.CurrentHeader(); current.Hash() != head.Hash() {
+		t.Fatalf("Expect head %d, got %d", head.Number, current.Number)
+	}
+	// The total difficulty is not taken from the peer
+	want := v2.LightTotalDifficulty(big.NewInt(1), 40)
+	if have := chain.GetTd(checkpoint.Hash, 40); have.Cmp(want) != 0 {
+		t.Fatalf("Expect checkpoint td %v, got %v", want, have)
+	}
+	want.Add(want, big.NewInt(7*5))
+	if have := chain.GetTd(head.Hash(), head.Number.Uint64()); have.Cmp(want) != 0 {
+		t.Fatalf("Expect head td %v, got %v", want, have)
+	}
+
+	// A peer announcing a total difficulty the chain can not have is rejected
+	for _, td := range []*big.Int{new(big.Int).Add(td, big.NewInt(1)), big.NewInt(100), nil} {
+		verifier, chain, db := newClient()
+		info := blockInfo{Hash: head.Hash(), Number: head.Number.Uint64(), Td: td}
+		if err := syncConsortium(chainConfig, verifier, chain, db, trusted, info, retrieve); !errors.Is(err, errInvalidConsortiumHead) {
+			t.Fatalf("Expect error %v for td %v, got %v", errInvalidConsortiumHead, td, err)
+		}
+		if verifier.Checkpoint() != nil || chain.CurrentHeader().Number.Uint64() != 0 {
+			t.Fatalf("Expect nothing synced for td %v", td)
+		}
+	}
+
+	// The trusted checkpoint is checked against the configured hash
+	verifier, chain, db = newClient()
+	forged := &ethconfig.ConsortiumCheckpoint{Number: 10, Hash: headers[0].Hash()}
+	if err := syncConsortium(chainConfig, verifier, chain, db, forged, info, retrieve); !errors.Is(err, errInvalidConsortiumCheckpoint) {
+		t.Fatalf("Expect error %v, got %v", errInvalidConsortiumCheckpoint, err)
+	}
+
+	// Nothing is imported until a checkpoint is walked from the trusted one
+	verifier, chain, db = newClient()
+	info = blockInfo{Hash: headers[12].Hash(), Number: 21, Td: big.NewInt(1 + 7*21)}
+	if err := syncConsortium(chainConfig, verifier, chain, db, trusted, info, retrieve); err != nil {
+		t.Fatalf("Failed to sync consortium checkpoints, err %s", err)
+	}
+	if verifier.Checkpoint().Number != 10 || chain.CurrentHeader().Number.Uint64() != 0 {
+		t.Fatalf("Expect only the trusted checkpoint, got %d with head %d", verifier.Checkpoint().Number, chain.CurrentHeader().Number)
+	}
+}
@@ -192,6 +192,15 @@ func (h *clientHandler) synchronise(peer *serverPeer) {
 		}
 	}
 
+	// Walk the consortium epoch checkpoints up to the peer head, skipping the
+	// headers between them
+	if h.backend.consortium != nil {
+		if err := h.consortiumSync(peer); err != nil {
+			log.Debug("Consortium checkpoint syncing failed", "reason", err)
+			h.removePeer(peer.id)
+			return
+		}
+	}
 	if h.syncStart != nil {
 		h.syncStart(h.backend.blockchain.CurrentHeader())
 	}
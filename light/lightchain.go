@@ -533,6 +533,28 @@ func (lc *LightChain) SyncCheckpoint(ctx context.Context, checkpoint *params.Tru
 	return false
 }
 
+// SetTrustedHead sets the head of the chain to a header trusted out of band,
+// such as a finalized consortium epoch checkpoint, along with its total
+// difficulty. The ancestors of the header are not required.
+func (lc *LightChain) SetTrustedHead(header *types.Header, td *big.Int) bool {
+	lc.chainmu.Lock()
+	defer lc.chainmu.Unlock()
+
+	// Ensure the chain didn't move past the trusted header
+	if lc.hc.CurrentHeader().Number.Uint64() >= header.Number.Uint64() {
+		return false
+	}
+	hash, number := header.Hash(), header.Number.Uint64()
+	rawdb.WriteHeader(lc.chainDb, header)
+	rawdb.WriteTd(lc.chainDb, hash, number, td)
+	rawdb.WriteCanonicalHash(lc.chainDb, hash, number)
+	rawdb.WriteHeadHeaderHash(lc.chainDb, hash)
+	lc.hc.SetCurrentHeader(header)
+
+	log.Info("Updated latest header to trusted header", "number", number, "hash", hash, "age", common.PrettyAge(time.Unix(int64(header.Time), 0)))
+	return true
+}
+
 // LockChain locks the chain mutex for reading so that multiple canonical hashes can be
 // retrieved while it is guaranteed that they belong to the same version of the chain
 func (lc *LightChain) LockChain() {
@@ -356,3 +356,46 @@ func TestReorgBadHeaderHashes(t *testing.T) {
 		t.Errorf("last header hash mismatch: have: %x, want %x", ncm.CurrentHeader().Hash(), headers[2].Hash())
 	}
 }
+
+// Tests that the head can be set to a trusted header without its ancestors,
+// and the chain extended from it.
+func TestSetTrustedHead(t *testing.T) {
+	bc := newTestLightChain()
+	headers := makeHeaderChainWithDiff(bc.genesisBlock, []int{1, 2, 3, 4, 5, 6}, 10)
+
+	if !bc.SetTrustedHead(headers[2], big.NewInt(100)) {
+		t.Fatal("trusted head not set")
+	}
+	if head := bc.CurrentHeader(); head.Hash() != headers[2].Hash() {
+		t.Fatalf("head mismatch: have %d, want %d", head.Number, headers[2].Number)
+	}
+	if td := bc.GetTd(headers[2].Hash(), 3); td == nil || td.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("total difficulty mismatch: have %v, want %v", td, 100)
+	}
+	if header := bc.GetHeaderByNumber(3); header == nil || header.Hash() != headers[2].Hash() {
+		t.Fatal("trusted head is not canonical")
+	}
+	if header := bc.GetHeaderByNumber(2); header != nil {
+		t.Fatalf("unexpected ancestor %d of the trusted head", header.Number)
+	}
+
+	// The headers after the trusted head are imported on top of it
+	if _, err := bc.InsertHeaderChain(headers[3:], 1); err != nil {
+		t.Fatalf("failed to insert headers after the trusted head: %v", err)
+	}
+	head := bc.CurrentHeader()
+	if head.Hash() != headers[5].Hash() {
+		t.Fatalf("head mismatch: have %d, want %d", head.Number, headers[5].Number)
+	}
+	if td := bc.GetTd(head.Hash(), head.Number.Uint64()); td.Cmp(big.NewInt(115)) != 0 {
+		t.Fatalf("total difficulty mismatch: have %v, want %v", td, 115)
+	}
+
+	// The head is not moved back to a trusted header behind it
+	if bc.SetTrustedHead(headers[4], big.NewInt(200)) {
+		t.Fatal("trusted head set behind the current head")
+	}
+	if bc.CurrentHeader().Hash() != headers[5].Hash() {
+		t.Fatal("head moved by a trusted header behind it")
+	}
+}
@@ -530,6 +530,27 @@ func (pool *TxPool) ContentFrom(addr common.Address) ([]*types.Transaction, []*t
 	return pending, make([]*types.Transaction, 0)
 }
 
+// ContentFromPayer retrieves the sponsored transactions paid by this address,
+// returning the pending ones grouped by sender.
+func (pool *TxPool) ContentFromPayer(payer common.Address) (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	pending := make(map[common.Address][]*types.Transaction)
+	for _, tx := range pool.pending {
+		if tx.Type() != types.SponsoredTxType {
+			continue
+		}
+		if txPayer, err := types.Payer(pool.signer, tx); err != nil || txPayer != payer {
+			continue
+		}
+		account, _ := types.Sender(pool.signer, tx)
+		pending[account] = append(pending[account], tx)
+	}
+	// There are no queued transactions in a light pool, just return an empty map
+	return pending, make(map[common.Address][]*types.Transaction)
+}
+
 // RemoveTransactions removes all given transactions from the pool.
 func (pool *TxPool) RemoveTransactions(txs types.Transactions) {
 	pool.mu.Lock()
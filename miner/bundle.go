@@ -0,0 +1,291 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// maxBundles is the maximum number of bundles waiting for inclusion.
+	maxBundles = 256
+
+	// maxBundleTxs is the maximum number of transactions in a bundle.
+	maxBundleTxs = 16
+
+	// maxBundlePoolTxs is the maximum number of transactions in all the bundles
+	// waiting for inclusion. The worker executes all of them on every recommit.
+	maxBundlePoolTxs = 1024
+
+	// maxBundlesPerSender is the maximum number of bundles waiting for inclusion
+	// which contain a transaction from the same sender.
+	maxBundlesPerSender = 16
+
+	// MaxBundleBlockRange is the maximum distance from the current head to the
+	// last block a bundle targets.
+	MaxBundleBlockRange = 256
+)
+
+var (
+	ErrBundleEmpty    = errors.New("bundle has no transactions")
+	ErrBundleTooLarge = fmt.Errorf("bundle has more than %d transactions", maxBundleTxs)
+	ErrBundleBlobTx   = errors.New("blob transactions are not allowed in bundles")
+	ErrBundleRange    = errors.New("invalid bundle block range")
+	ErrBundlePoolFull = errors.New("too many pending bundles")
+	ErrBundleSender   = fmt.Errorf("sender has more than %d pending bundles", maxBundlesPerSender)
+	errBundleReverted = errors.New("bundle transaction reverted")
+)
+
+var (
+	bundleReceivedMeter  = metrics.NewRegisteredMeter("miner/bundle/received", nil)
+	bundleCommittedMeter = metrics.NewRegisteredMeter("miner/bundle/committed", nil)
+	bundleRevertedMeter  = metrics.NewRegisteredMeter("miner/bundle/reverted", nil) // Dropped as one of the transactions reverted
+	bundleFailedMeter    = metrics.NewRegisteredMeter("miner/bundle/failed", nil)   // Dropped as one of the transactions could not be applied
+	bundleExpiredMeter   = metrics.NewRegisteredMeter("miner/bundle/expired", nil)
+	bundleGasMeter       = metrics.NewRegisteredMeter("miner/bundle/gas", nil)
+)
+
+// Bundle is an ordered list of transactions which are included all together at
+// the top of a block within the target range, or not at all.
+type Bundle struct {
+	Txs      types.Transactions
+	MinBlock uint64 // First block the bundle can be included in
+	MaxBlock uint64 // Last block the bundle can be included in
+}
+
+// Hash returns the hash identifying the bundle, the keccak256 hash of the
+// concatenated hashes of its transactions.
+func (b *Bundle) Hash() common.Hash {
+	hashes := make([]byte, 0, len(b.Txs)*common.HashLength)
+	for _, tx := range b.Txs {
+		hashes = append(hashes, tx.Hash().Bytes()...)
+	}
+	return crypto.Keccak256Hash(hashes)
+}
+
+// validate checks the bundle against the current head of the chain.
+func (b *Bundle) validate(head uint64) error {
+	if len(b.Txs) == 0 {
+		return ErrBundleEmpty
+	}
+	if len(b.Txs) > maxBundleTxs {
+		return ErrBundleTooLarge
+	}
+	for _, tx := range b.Txs {
+		if tx.Type() == types.BlobTxType {
+			return ErrBundleBlobTx
+		}
+	}
+	switch {
+	case b.MinBlock > b.MaxBlock:
+		return fmt.Errorf("%w: min block %d above max block %d", ErrBundleRange, b.MinBlock, b.MaxBlock)
+	case b.MaxBlock <= head:
+		return fmt.Errorf("%w: max block %d not above head %d", ErrBundleRange, b.MaxBlock, head)
+	case b.MaxBlock > head+MaxBundleBlockRange:
+		return fmt.Errorf("%w: max block %d more than %d blocks ahead of head %d", ErrBundleRange, b.MaxBlock, MaxBundleBlockRange, head)
+	}
+	return nil
+}
+
+// bundleEntry is a bundle waiting for inclusion.
+type bundleEntry struct {
+	bundle    *Bundle
+	hash      common.Hash
+	senders   []common.Address // Distinct senders of the bundle transactions
+	attempted uint64           // Last block number the bundle was attempted at
+}
+
+// contains reports whether any of the bundle transactions is in the given set.
+func (e *bundleEntry) contains(txs map[common.Hash]struct{}) bool {
+	for _, tx := range e.bundle.Txs {
+		if _, ok := txs[tx.Hash()]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// bundlePool keeps the bundles submitted to the miner in arrival order, which is
+// the order they are attempted in.
+type bundlePool struct {
+	mu      sync.Mutex
+	entries []*bundleEntry
+	txs     int                    // Number of transactions in all the bundles
+	senders map[common.Address]int // Number of bundles containing a transaction per sender
+}
+
+// add inserts a bundle into the pool, returning its hash. Resubmitting a known
+// bundle updates its target range.
+func (p *bundlePool) add(bundle *Bundle, head uint64, signer types.Signer) (common.Hash, error) {
+	if err := bundle.validate(head); err != nil {
+		return common.Hash{}, err
+	}
+	var (
+		hash    = bundle.Hash()
+		senders []common.Address
+		seen    = make(map[common.Address]struct{})
+	)
+	for i, tx := range bundle.Txs {
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("invalid transaction %d [%v]: %w", i, tx.Hash(), err)
+		}
+		if _, ok := seen[from]; !ok {
+			seen[from] = struct{}{}
+			senders = append(senders, from)
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, entry := range p.entries {
+		if entry.hash == hash {
+			p.entries[i] = &bundleEntry{bundle: bundle, hash: hash, senders: entry.senders}
+			return hash, nil
+		}
+	}
+	if len(p.entries) >= maxBundles || p.txs+len(bundle.Txs) > maxBundlePoolTxs {
+		return common.Hash{}, ErrBundlePoolFull
+	}
+	for _, from := range senders {
+		if p.senders[from] >= maxBundlesPerSender {
+			return common.Hash{}, fmt.Errorf("%w: %s", ErrBundleSender, from)
+		}
+	}
+	if p.senders == nil {
+		p.senders = make(map[common.Address]int)
+	}
+	for _, from := range senders {
+		p.senders[from]++
+	}
+	p.txs += len(bundle.Txs)
+	p.entries = append(p.entries, &bundleEntry{bundle: bundle, hash: hash, senders: senders})
+	bundleReceivedMeter.Mark(1)
+	return hash, nil
+}
+
+// release forgets the transactions and senders of a bundle leaving the pool.
+//
+// Note, this method assumes the pool lock is held!
+func (p *bundlePool) release(entry *bundleEntry) {
+	p.txs -= len(entry.bundle.Txs)
+	for _, from := range entry.senders {
+		if p.senders[from]--; p.senders[from] == 0 {
+			delete(p.senders, from)
+		}
+	}
+}
+
+// pending drops the bundles expired before the given block and returns the ones
+// targeting it.
+func (p *bundlePool) pending(number uint64) []*bundleEntry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var (
+		kept    = p.entries[:0]
+		pending []*bundleEntry
+	)
+	for _, entry := range p.entries {
+		if entry.bundle.MaxBlock < number {
+			p.release(entry)
+			bundleExpiredMeter.Mark(1)
+			continue
+		}
+		kept = append(kept, entry)
+		if entry.bundle.MinBlock <= number {
+			pending = append(pending, entry)
+		}
+	}
+	for i := len(kept); i < len(p.entries); i++ {
+		p.entries[i] = nil
+	}
+	p.entries = kept
+	return pending
+}
+
+// remove drops a bundle which failed or can never be included anymore.
+func (p *bundlePool) remove(hash common.Hash) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, entry := range p.entries {
+		if entry.hash == hash {
+			p.release(entry)
+			p.entries = append(p.entries[:i], p.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// included drops the bundles with a transaction in the given block. The nonces
+// of their senders have been consumed, so they could only fail from now on.
+func (p *bundlePool) included(block *types.Block) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.entries) == 0 || len(block.Transactions()) == 0 {
+		return
+	}
+	txs := make(map[common.Hash]struct{}, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		txs[tx.Hash()] = struct{}{}
+	}
+	kept := p.entries[:0]
+	for _, entry := range p.entries {
+		if !entry.contains(txs) {
+			kept = append(kept, entry)
+			continue
+		}
+		log.Debug("Bundle included, discarded", "hash", entry.hash, "number", block.NumberU64())
+		p.release(entry)
+	}
+	for i := len(kept); i < len(p.entries); i++ {
+		p.entries[i] = nil
+	}
+	p.entries = kept
+}
+
+// attempt marks the bundle as attempted at the given block, returning whether it
+// is the first attempt. As the worker rebuilds the block at the same height on
+// every recommit, the outcome metrics are only updated by the first attempt.
+func (p *bundlePool) attempt(entry *bundleEntry, number uint64) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if entry.attempted == number {
+		return false
+	}
+	entry.attempted = number
+	return true
+}
+
+// len returns the number of bundles waiting for inclusion.
+func (p *bundlePool) len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.entries)
+}
@@ -0,0 +1,323 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestBundlePool(t *testing.T) {
+	signer := types.LatestSigner(params.TestChainConfig)
+	transfer := func(nonce uint64) *types.Transaction {
+		return types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &testUserAddress,
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(params.InitialBaseFee),
+		})
+	}
+	var pool bundlePool
+
+	invalid := []struct {
+		bundle *Bundle
+		err    error
+	}{
+		{&Bundle{MinBlock: 11, MaxBlock: 11}, ErrBundleEmpty},
+		{&Bundle{Txs: types.Transactions{transfer(0)}, MinBlock: 12, MaxBlock: 11}, ErrBundleRange},
+		{&Bundle{Txs: types.Transactions{transfer(0)}, MinBlock: 9, MaxBlock: 10}, ErrBundleRange},
+		{&Bundle{Txs: types.Transactions{transfer(0)}, MinBlock: 11, MaxBlock: 11 + MaxBundleBlockRange}, ErrBundleRange},
+	}
+	for i, tt := range invalid {
+		if _, err := pool.add(tt.bundle, 10, signer); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	a := &Bundle{Txs: types.Transactions{transfer(0)}, MinBlock: 11, MaxBlock: 12}
+	b := &Bundle{Txs: types.Transactions{transfer(1), transfer(2)}, MinBlock: 13, MaxBlock: 13}
+	for _, bundle := range []*Bundle{a, b} {
+		if _, err := pool.add(bundle, 10, signer); err != nil {
+			t.Fatalf("failed to add bundle: %v", err)
+		}
+	}
+	// Resubmitting a bundle updates its range
+	hash, err := pool.add(&Bundle{Txs: a.Txs, MinBlock: 11, MaxBlock: 15}, 10, signer)
+	if err != nil {
+		t.Fatalf("failed to resubmit bundle: %v", err)
+	}
+	if hash != a.Hash() || pool.len() != 2 {
+		t.Fatalf("bundle not replaced: hash %x, pool size %d", hash, pool.len())
+	}
+	if pending := pool.pending(11); len(pending) != 1 || pending[0].hash != a.Hash() {
+		t.Fatalf("unexpected pending bundles at block 11: %d", len(pending))
+	}
+	pending := pool.pending(14)
+	if len(pending) != 1 || pending[0].hash != a.Hash() || pool.len() != 1 {
+		t.Fatalf("expired bundle not dropped: %d pending, pool size %d", len(pending), pool.len())
+	}
+	if !pool.attempt(pending[0], 14) || pool.attempt(pending[0], 14) || !pool.attempt(pending[0], 15) {
+		t.Fatal("bundle attempts not tracked per block")
+	}
+	if pending := pool.pending(16); len(pending) != 0 || pool.len() != 0 {
+		t.Fatalf("expired bundles not dropped: %d pending, pool size %d", len(pending), pool.len())
+	}
+	if pool.txs != 0 || len(pool.senders) != 0 {
+		t.Fatalf("expired bundles not released: %d txs, %d senders", pool.txs, len(pool.senders))
+	}
+}
+
+func TestBundlePoolLimits(t *testing.T) {
+	signer := types.LatestSigner(params.TestChainConfig)
+	transfer := func(key *ecdsa.PrivateKey, nonce uint64) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &testUserAddress,
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(params.InitialBaseFee),
+		})
+	}
+	var pool bundlePool
+
+	// A single sender can only have a limited number of pending bundles
+	for i := 0; i < maxBundlesPerSender; i++ {
+		bundle := &Bundle{Txs: types.Transactions{transfer(testBankKey, uint64(i))}, MinBlock: 11, MaxBlock: 11}
+		if _, err := pool.add(bundle, 10, signer); err != nil {
+			t.Fatalf("failed to add bundle %d: %v", i, err)
+		}
+	}
+	bundle := &Bundle{Txs: types.Transactions{transfer(testBankKey, maxBundlesPerSender)}, MinBlock: 11, MaxBlock: 11}
+	if _, err := pool.add(bundle, 10, signer); !errors.Is(err, ErrBundleSender) {
+		t.Fatalf("sender limit error mismatch: have %v, want %v", err, ErrBundleSender)
+	}
+	// Dropping a bundle releases the slot of the sender
+	pool.remove(pool.entries[0].hash)
+	if _, err := pool.add(bundle, 10, signer); err != nil {
+		t.Fatalf("failed to add bundle after release: %v", err)
+	}
+	// The transactions of all the bundles are capped too
+	for pool.txs+maxBundleTxs <= maxBundlePoolTxs {
+		key, _ := crypto.GenerateKey()
+		txs := make(types.Transactions, maxBundleTxs)
+		for i := range txs {
+			txs[i] = transfer(key, uint64(i))
+		}
+		if _, err := pool.add(&Bundle{Txs: txs, MinBlock: 11, MaxBlock: 11}, 10, signer); err != nil {
+			t.Fatalf("failed to add bundle: %v", err)
+		}
+	}
+	key, _ := crypto.GenerateKey()
+	txs := make(types.Transactions, maxBundleTxs)
+	for i := range txs {
+		txs[i] = transfer(key, uint64(i))
+	}
+	if _, err := pool.add(&Bundle{Txs: txs, MinBlock: 11, MaxBlock: 11}, 10, signer); !errors.Is(err, ErrBundlePoolFull) {
+		t.Fatalf("pool limit error mismatch: have %v, want %v", err, ErrBundlePoolFull)
+	}
+}
+
+func TestSendBundleValidation(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	chainConfig := *params.AllEthashProtocolChanges
+	chainConfig.ChainID = big.NewInt(2020)
+	chainConfig.CancunBlock = nil
+	engine := clique.New(cliqueChainConfig.Clique, db)
+
+	backend := newTestWorkerBackend(t, &chainConfig, engine, db, 0)
+	w := newWorker(testConfig, &chainConfig, engine, backend, new(event.TypeMux), nil, false)
+	defer w.close()
+	miner := &Miner{eth: backend, worker: w}
+
+	var (
+		signer   = types.LatestSigner(&chainConfig)
+		unfunded = func() *ecdsa.PrivateKey { key, _ := crypto.GenerateKey(); return key }()
+	)
+	transfer := func(key *ecdsa.PrivateKey, price *big.Int) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.LegacyTx{
+			To:       &testUserAddress,
+			Gas:      params.TxGas,
+			GasPrice: price,
+		})
+	}
+	tests := []struct {
+		tx  *types.Transaction
+		err error
+	}{
+		{transfer(testBankKey, new(big.Int)), txpool.ErrUnderpriced},
+		{transfer(unfunded, big.NewInt(params.InitialBaseFee)), core.ErrInsufficientFunds},
+		{transfer(testBankKey, big.NewInt(params.InitialBaseFee)), nil},
+	}
+	for i, tt := range tests {
+		_, err := miner.SendBundle(&Bundle{Txs: types.Transactions{tt.tx}, MinBlock: 1, MaxBlock: 1})
+		if !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	if w.bundles.len() != 1 {
+		t.Fatalf("pending bundles mismatch: have %d, want 1", w.bundles.len())
+	}
+}
+
+func TestCommitBundles(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	chainConfig := *params.AllEthashProtocolChanges
+	chainConfig.ChainID = big.NewInt(2020)
+	chainConfig.CancunBlock = nil
+	engine := clique.New(cliqueChainConfig.Clique, db)
+
+	backend := newTestWorkerBackend(t, &chainConfig, engine, db, 0)
+	w := newWorker(testConfig, &chainConfig, engine, backend, new(event.TypeMux), nil, false)
+	defer w.close()
+
+	var (
+		signer     = types.LatestSigner(&chainConfig)
+		key, _     = crypto.GenerateKey()
+		sender     = crypto.PubkeyToAddress(key.PublicKey)
+		revertAddr = common.Address{0xde, 0xad}
+	)
+	transfer := func(nonce uint64, to common.Address) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &to,
+			Gas:      50000,
+			GasPrice: big.NewInt(params.InitialBaseFee),
+		})
+	}
+	w.current = newCurrent(t, signer, w.chain)
+	w.current.state.AddBalance(sender, new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil))
+	w.current.state.SetCode(revertAddr, common.FromHex("0x60006000fd")) // revert(0, 0)
+
+	reverting := &Bundle{Txs: types.Transactions{transfer(0, testUserAddress), transfer(1, revertAddr)}, MaxBlock: 1}
+	valid := &Bundle{Txs: types.Transactions{transfer(0, testUserAddress), transfer(1, testUserAddress)}, MaxBlock: 1}
+	for _, bundle := range []*Bundle{reverting, valid} {
+		if _, err := w.bundles.add(bundle, 0, signer); err != nil {
+			t.Fatalf("failed to add bundle: %v", err)
+		}
+	}
+	if w.commitBundles(sender, nil) {
+		t.Fatal("bundle commit interrupted")
+	}
+	// The reverting bundle is dropped as a whole, the valid one applied
+	if len(w.current.txs) != 2 || len(w.current.receipts) != 2 || w.current.tcount != 2 {
+		t.Fatalf("unexpected block: %d txs, %d receipts, tcount %d", len(w.current.txs), len(w.current.receipts), w.current.tcount)
+	}
+	if w.bundles.len() != 1 {
+		t.Fatalf("reverting bundle not discarded: %d bundles", w.bundles.len())
+	}
+	for i, tx := range valid.Txs {
+		if w.current.txs[i].Hash() != tx.Hash() {
+			t.Errorf("transaction %d mismatch: have %x, want %x", i, w.current.txs[i].Hash(), tx.Hash())
+		}
+	}
+	if nonce := w.current.state.GetNonce(sender); nonce != 2 {
+		t.Errorf("sender nonce mismatch: have %d, want 2", nonce)
+	}
+	if w.current.header.GasUsed != 2*params.TxGas {
+		t.Errorf("gas used mismatch: have %d, want %d", w.current.header.GasUsed, 2*params.TxGas)
+	}
+	if gas := w.current.gasPool.Gas(); gas != w.current.header.GasLimit-2*params.TxGas {
+		t.Errorf("gas pool mismatch: have %d, want %d", gas, w.current.header.GasLimit-2*params.TxGas)
+	}
+	// The valid bundle is stale once its transactions are in the block
+	if w.commitBundles(sender, nil) {
+		t.Fatal("bundle commit interrupted")
+	}
+	if len(w.current.txs) != 2 || w.bundles.len() != 0 {
+		t.Fatalf("stale bundles not discarded: %d txs, %d bundles", len(w.current.txs), w.bundles.len())
+	}
+}
+
+func TestBundleIncluded(t *testing.T) {
+	// Track the failed bundles regardless of the metrics being enabled
+	defer func(meter metrics.Meter) { bundleFailedMeter = meter }(bundleFailedMeter)
+	bundleFailedMeter = metrics.NewMeterForced()
+
+	db := rawdb.NewMemoryDatabase()
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	backend := newTestWorkerBackend(t, ethashChainConfig, engine, db, 0)
+	w := newWorker(testConfig, ethashChainConfig, engine, backend, new(event.TypeMux), nil, false)
+	w.setEtherbase(testBankAddress)
+	defer w.close()
+
+	// Only seal the blocks with transactions
+	w.skipSealHook = func(task *task) bool {
+		return len(task.receipts) == 0
+	}
+	sub := w.mux.Subscribe(core.NewMinedBlockEvent{})
+	defer sub.Unsubscribe()
+
+	signer := types.LatestSigner(ethashChainConfig)
+	transfer := func(nonce uint64) *types.Transaction {
+		return types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &testUserAddress,
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(10 * params.InitialBaseFee),
+		})
+	}
+	bundle := &Bundle{Txs: types.Transactions{transfer(0), transfer(1)}, MinBlock: 1, MaxBlock: 4}
+	if _, err := w.bundles.add(bundle, 0, signer); err != nil {
+		t.Fatalf("failed to add bundle: %v", err)
+	}
+	// Queue a transaction executable once the bundle is included, so that the
+	// next block is sealed as well
+	if errs := backend.txPool.Add([]*types.Transaction{transfer(2)}, true, false); errs[0] != nil {
+		t.Fatalf("failed to add transaction: %v", errs[0])
+	}
+	w.start()
+
+	// Seal the block including the bundle, then one more block within its range
+	var blocks []*types.Block
+	for len(blocks) < 2 {
+		select {
+		case ev := <-sub.Chan():
+			block := ev.Data.(core.NewMinedBlockEvent).Block
+			if block.NumberU64() > bundle.MaxBlock {
+				t.Fatalf("block %d sealed beyond the bundle range", block.NumberU64())
+			}
+			blocks = append(blocks, block)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timeout waiting for block %d", len(blocks)+1)
+		}
+	}
+	txs := blocks[0].Transactions()
+	if len(txs) != 2 || txs[0].Hash() != bundle.Txs[0].Hash() || txs[1].Hash() != bundle.Txs[1].Hash() {
+		t.Fatalf("bundle not included at the top of block %d", blocks[0].NumberU64())
+	}
+	if n := w.bundles.len(); n != 0 {
+		t.Fatalf("included bundle not discarded: %d bundles", n)
+	}
+	if n := bundleFailedMeter.Count(); n != 0 {
+		t.Fatalf("included bundle attempted again: %d failed bundles", n)
+	}
+}
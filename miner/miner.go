@@ -208,6 +208,20 @@ func (miner *Miner) PendingBlockAndReceipts() (*types.Block, types.Receipts) {
 	return miner.worker.pendingBlockAndReceipts()
 }
 
+// SendBundle queues a bundle to be included at the top of a block within its
+// target range, returning the bundle hash. The bundle transactions are subject
+// to the same validation as the transactions entering the pool.
+func (miner *Miner) SendBundle(bundle *Bundle) (common.Hash, error) {
+	pool := miner.eth.TxPool()
+	for i, tx := range bundle.Txs {
+		if err := pool.ValidateTx(tx); err != nil {
+			return common.Hash{}, fmt.Errorf("invalid transaction %d [%v]: %w", i, tx.Hash(), err)
+		}
+	}
+	head := miner.worker.chain.CurrentBlock().Header()
+	return miner.worker.bundles.add(bundle, head.Number.Uint64(), types.MakeSigner(miner.worker.chainConfig, head.Number))
+}
+
 func (miner *Miner) SetEtherbase(addr common.Address) {
 	miner.coinbase = addr
 	miner.worker.setEtherbase(addr)
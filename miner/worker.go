@@ -19,6 +19,7 @@ package miner
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"math/big"
 	"sync"
 	"sync/atomic"
@@ -231,6 +232,8 @@ type worker struct {
 	resubmitHook func(time.Duration, time.Duration) // Method to call upon updating resubmitting interval.
 
 	recentMinedBlocks *lru.Cache[uint64, []common.Hash]
+
+	bundles bundlePool // Bundles waiting to be included at the top of a block
 }
 
 func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus.Engine, eth Backend, mux *event.TypeMux, isLocalBlock func(*types.Block) bool, init bool) *worker {
@@ -452,6 +455,8 @@ func (w *worker) newWorkLoop(recommit time.Duration) {
 
 		case head := <-w.chainHeadCh:
 			clearPending(head.Block.NumberU64())
+			// Drop the bundles included in the new head before building on it
+			w.bundles.included(head.Block)
 			timestamp = time.Now().Unix()
 			commit(false, commitInterruptNewHead)
 
@@ -898,36 +903,162 @@ func (w *worker) commitTransaction(tx *types.Transaction, coinbase common.Addres
 	return receipt.Logs, nil
 }
 
-func (w *worker) commitTransactions(plainTxs, blobTxs *TransactionsByPriceAndNonce, coinbase common.Address, interrupt *int32) bool {
-	// Short circuit if current is nil
-	if w.current == nil {
+// prepareGasPool creates the gas pool of the current block if it does not exist
+// yet, reserving the gas of the consortium system transactions. It returns false
+// if the gas cannot be reserved.
+func (w *worker) prepareGasPool() bool {
+	if w.current.gasPool != nil {
 		return true
 	}
+	w.current.gasPool = new(core.GasPool).AddGas(w.current.header.GasLimit)
 
-	var reservedGas uint64 = 0
+	// If the gas pool is newly created, reserve some gas for system transactions
 	if w.chainConfig.Consortium != nil {
+		var reservedGas uint64
 		if w.current.header.Number.Uint64()%w.chainConfig.Consortium.EpochV2 == w.chainConfig.Consortium.EpochV2-1 {
 			reservedGas = params.ReservedGasForCheckpointSystemTransactions
 		} else {
 			reservedGas = params.ReservedGasForNormalSystemTransactions
 		}
+		if err := w.current.gasPool.SubGas(reservedGas); err != nil {
+			log.Error(
+				"Failed to reserve gas for system transactions",
+				"pool", w.current.gasPool,
+				"reserve", reservedGas,
+				"error", err,
+			)
+			return false
+		}
 	}
-	gasLimit := w.current.header.GasLimit
-	if w.current.gasPool == nil {
-		w.current.gasPool = new(core.GasPool).AddGas(gasLimit)
-
-		// If the gas pool is newly created, reserve some gas for system transactions
-		if w.chainConfig.Consortium != nil {
-			if err := w.current.gasPool.SubGas(reservedGas); err != nil {
-				log.Error(
-					"Failed to reserve gas for system transactions",
-					"pool", w.current.gasPool,
-					"reserve", reservedGas,
-					"error", err,
-				)
-				return true
+	return true
+}
+
+// commitBundle applies the transactions of a bundle in order, returning their
+// logs. If any of them cannot be applied or reverts, the whole bundle is dropped
+// from the block and the state changes of the ones already applied are reverted.
+func (w *worker) commitBundle(bundle *Bundle, coinbase common.Address) ([]*types.Log, error) {
+	var (
+		env     = w.current
+		snap    = env.state.Copy() // Transactions finalise the state, invalidating the journal snapshots
+		gas     = env.gasPool.Gas()
+		gasUsed = env.header.GasUsed
+		tcount  = env.tcount
+		txs     = len(env.txs)
+		size    = env.estimatedBlockSize
+		logs    []*types.Log
+		err     error
+	)
+	bloomProcessor := core.NewReceiptBloomGenerator()
+	for _, tx := range bundle.Txs {
+		if tx.Protected() && !w.chainConfig.IsEIP155(env.header.Number) {
+			err = fmt.Errorf("replay protected transaction %s before EIP155", tx.Hash())
+			break
+		}
+		env.state.SetTxContext(tx.Hash(), env.tcount)
+		var txLogs []*types.Log
+		if txLogs, err = w.commitTransaction(tx, coinbase, bloomProcessor); err != nil {
+			err = fmt.Errorf("transaction %s: %w", tx.Hash(), err)
+			break
+		}
+		logs = append(logs, txLogs...)
+		if env.receipts[len(env.receipts)-1].Status == types.ReceiptStatusFailed {
+			err = fmt.Errorf("%w: %s", errBundleReverted, tx.Hash())
+			break
+		}
+	}
+	if err != nil {
+		env.state = snap
+		*env.gasPool = core.GasPool(gas)
+		env.header.GasUsed = gasUsed
+		env.tcount = tcount
+		env.txs = env.txs[:txs]
+		env.receipts = env.receipts[:txs]
+		env.estimatedBlockSize = size
+		return nil, err
+	}
+	bundleGasMeter.Mark(int64(env.header.GasUsed - gasUsed))
+	return logs, nil
+}
+
+// commitBundles attempts the bundles targeting the current block at its top, in
+// the order they were submitted.
+func (w *worker) commitBundles(coinbase common.Address, interrupt *int32) bool {
+	// Short circuit if current is nil
+	if w.current == nil {
+		return true
+	}
+	number := w.current.header.Number.Uint64()
+	bundles := w.bundles.pending(number)
+	if len(bundles) == 0 {
+		return false
+	}
+	if !w.prepareGasPool() {
+		return true
+	}
+	var coalescedLogs []*types.Log
+	for _, entry := range bundles {
+		if interrupt != nil && atomic.LoadInt32(interrupt) != commitInterruptNone {
+			return atomic.LoadInt32(interrupt) == commitInterruptNewHead
+		}
+		logs, err := w.commitBundle(entry.bundle, coinbase)
+		if err == nil {
+			log.Debug("Committed bundle", "hash", entry.hash, "number", number, "txs", len(entry.bundle.Txs))
+			if w.bundles.attempt(entry, number) {
+				bundleCommittedMeter.Mark(1)
 			}
+			coalescedLogs = append(coalescedLogs, logs...)
+			continue
+		}
+		// The bundle failed, drop it rather than re-executing it on every recommit
+		w.bundles.remove(entry.hash)
+		switch {
+		case errors.Is(err, core.ErrNonceTooLow):
+			// The bundle, or a conflicting transaction, is already in the chain
+			log.Debug("Discarding stale bundle", "hash", entry.hash, "err", err)
+			bundleFailedMeter.Mark(1)
+
+		case errors.Is(err, errBundleReverted):
+			log.Debug("Bundle reverted, discarded", "hash", entry.hash, "number", number, "err", err)
+			bundleRevertedMeter.Mark(1)
+
+		default:
+			log.Debug("Bundle failed, discarded", "hash", entry.hash, "number", number, "err", err)
+			bundleFailedMeter.Mark(1)
+		}
+	}
+	w.postPendingLogs(coalescedLogs)
+	return false
+}
+
+// postPendingLogs sends the logs of the transactions committed to the pending
+// block to the pending logs subscribers.
+func (w *worker) postPendingLogs(logs []*types.Log) {
+	if !w.isRunning() && len(logs) > 0 {
+		// We don't push the pendingLogsEvent while we are mining. The reason is that
+		// when we are mining, the worker will regenerate a mining block every 3 seconds.
+		// In order to avoid pushing the repeated pendingLog, we disable the pending log pushing.
+
+		// make a copy, the state caches the logs and these logs get "upgraded" from pending to mined
+		// logs by filling in the block hash when the block was mined by the local miner. This can
+		// cause a race condition if a log was "upgraded" before the PendingLogsEvent is processed.
+		cpy := make([]*types.Log, len(logs))
+		for i, l := range logs {
+			cpy[i] = new(types.Log)
+			*cpy[i] = *l
 		}
+		w.pendingLogsFeed.Send(cpy)
+	}
+}
+
+func (w *worker) commitTransactions(plainTxs, blobTxs *TransactionsByPriceAndNonce, coinbase common.Address, interrupt *int32) bool {
+	// Short circuit if current is nil
+	if w.current == nil {
+		return true
+	}
+
+	gasLimit := w.current.header.GasLimit
+	if !w.prepareGasPool() {
+		return true
 	}
 
 	var coalescedLogs []*types.Log
@@ -1057,6 +1188,18 @@ Loop:
 			txs.Pop()
 			continue
 		}
+		// Re-validate the inclusion conditions of the transaction against the block
+		if cond := selectedTx.Conditional; cond != nil {
+			err := cond.CheckHeader(w.current.header)
+			if err == nil {
+				err = cond.CheckState(w.current.state)
+			}
+			if err != nil {
+				log.Debug("Dropping conditional transaction", "hash", tx.Hash(), "sender", from, "reason", err)
+				txs.Pop()
+				continue
+			}
+		}
 		// Start executing the transaction
 		w.current.state.SetTxContext(tx.Hash(), w.current.tcount)
 
@@ -1095,21 +1238,8 @@ Loop:
 		}
 	}
 
-	if !w.isRunning() && len(coalescedLogs) > 0 {
-		// We don't push the pendingLogsEvent while we are mining. The reason is that
-		// when we are mining, the worker will regenerate a mining block every 3 seconds.
-		// In order to avoid pushing the repeated pendingLog, we disable the pending log pushing.
+	w.postPendingLogs(coalescedLogs)
 
-		// make a copy, the state caches the logs and these logs get "upgraded" from pending to mined
-		// logs by filling in the block hash when the block was mined by the local miner. This can
-		// cause a race condition if a log was "upgraded" before the PendingLogsEvent is processed.
-		cpy := make([]*types.Log, len(coalescedLogs))
-		for i, l := range coalescedLogs {
-			cpy[i] = new(types.Log)
-			*cpy[i] = *l
-		}
-		w.pendingLogsFeed.Send(cpy)
-	}
 	// Notify resubmit loop to decrease resubmitting interval if current interval is larger
 	// than the user-specified one.
 	if interrupt != nil {
@@ -1238,6 +1368,11 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 		w.commit(uncles, nil, false, tstart)
 	}
 
+	// Bundles go at the top of the block, ahead of the pool transactions
+	if w.commitBundles(w.coinbase, interrupt) {
+		return
+	}
+
 	filter := txpool.PendingFilter{
 		EnforceTip: true,
 	}
@@ -1256,7 +1391,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 	// Short circuit if there is no available pending transactions.
 	// But if we disable empty precommit already, ignore it. Since
 	// empty block is necessary to keep the liveness of the network.
-	if len(pendingPlainTxs) == 0 && len(pendingBlobTxs) == 0 && atomic.LoadUint32(&w.noempty) == 0 {
+	if len(pendingPlainTxs) == 0 && len(pendingBlobTxs) == 0 && w.current.tcount == 0 && atomic.LoadUint32(&w.noempty) == 0 {
 		w.updateSnapshot()
 		return
 	}
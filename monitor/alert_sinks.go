@@ -0,0 +1,187 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	reponseBuffer       = 2048
+	alertRequestTimeout = 10 * time.Second
+
+	pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+	opsgenieAlertsURL  = "https://api.opsgenie.com/v2/alerts"
+
+	// opsgenieMaxMessageLength is the maximum length of the Opsgenie alert message
+	opsgenieMaxMessageLength = 130
+)
+
+// postJSON sends the payload encoded as JSON to the url and returns an error if
+// the request fails or the server responds with an error status.
+func postJSON(client *http.Client, url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 400 {
+		responseBody, _ := io.ReadAll(io.LimitReader(response.Body, reponseBuffer))
+		return fmt.Errorf("error response from server, status %d: %s", response.StatusCode, responseBody)
+	}
+	return nil
+}
+
+// webhookAlerter posts the alerts as JSON to a generic HTTP endpoint.
+type webhookAlerter struct {
+	url    string
+	client *http.Client
+}
+
+func newWebhookAlerter(url string) *webhookAlerter {
+	return &webhookAlerter{
+		url:    url,
+		client: newAlertHTTPClient(),
+	}
+}
+
+func (alerter *webhookAlerter) Name() string {
+	return "webhook"
+}
+
+func (alerter *webhookAlerter) Alert(alert *Alert) error {
+	return postJSON(alerter.client, alerter.url, nil, alert)
+}
+
+// fileAlerter appends the alerts as JSON lines to a file.
+type fileAlerter struct {
+	path string
+	lock sync.Mutex
+}
+
+func newFileAlerter(path string) *fileAlerter {
+	return &fileAlerter{path: path}
+}
+
+func (alerter *fileAlerter) Name() string {
+	return "file"
+}
+
+func (alerter *fileAlerter) Alert(alert *Alert) error {
+	line, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	alerter.lock.Lock()
+	defer alerter.lock.Unlock()
+
+	file, err := os.OpenFile(alerter.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// pagerDutyAlerter triggers incidents through the PagerDuty Events API v2.
+type pagerDutyAlerter struct {
+	url        string
+	routingKey string
+	client     *http.Client
+}
+
+func newPagerDutyAlerter(routingKey string) *pagerDutyAlerter {
+	return &pagerDutyAlerter{
+		url:        pagerDutyEventsURL,
+		routingKey: routingKey,
+		client:     newAlertHTTPClient(),
+	}
+}
+
+func (alerter *pagerDutyAlerter) Name() string {
+	return "pagerduty"
+}
+
+func (alerter *pagerDutyAlerter) Alert(alert *Alert) error {
+	severity := "critical"
+	if alert.Severity == SeverityWarning {
+		severity = "warning"
+	}
+	event := map[string]interface{}{
+		"routing_key":  alerter.routingKey,
+		"event_action": "trigger",
+		"payload": map[string]interface{}{
+			"summary":        alert.Header,
+			"source":         "ronin",
+			"severity":       severity,
+			"timestamp":      alert.Time.UTC().Format(time.RFC3339),
+			"custom_details": map[string]interface{}{"body": alert.Body},
+		},
+	}
+	if alert.Key != "" {
+		event["dedup_key"] = alert.Key
+	}
+	return postJSON(alerter.client, alerter.url, nil, event)
+}
+
+// opsgenieAlerter creates alerts through the Opsgenie Alert API.
+type opsgenieAlerter struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func newOpsgenieAlerter(apiKey string) *opsgenieAlerter {
+	return &opsgenieAlerter{
+		url:    opsgenieAlertsURL,
+		apiKey: apiKey,
+		client: newAlertHTTPClient(),
+	}
+}
+
+func (alerter *opsgenieAlerter) Name() string {
+	return "opsgenie"
+}
+
+func (alerter *opsgenieAlerter) Alert(alert *Alert) error {
+	message := alert.Header
+	if len(message) > opsgenieMaxMessageLength {
+		message = message[:opsgenieMaxMessageLength]
+	}
+	priority := "P1"
+	if alert.Severity == SeverityWarning {
+		priority = "P3"
+	}
+	payload := map[string]interface{}{
+		"message":     message,
+		"description": alert.Body,
+		"priority":    priority,
+		"source":      "ronin",
+	}
+	if alert.Key != "" {
+		payload["alias"] = alert.Key
+	}
+	headers := map[string]string{"Authorization": "GenieKey " + alerter.apiKey}
+	return postJSON(alerter.client, alerter.url, headers, payload)
+}
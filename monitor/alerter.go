@@ -0,0 +1,215 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/time/rate"
+)
+
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+
+	alertDedupCache    = 1024
+	alertQueueSize     = 256
+	maxAlertRetries    = 256
+	maxAlertAttempts   = 5
+	alertRetryInterval = 5 * time.Second
+)
+
+// Alert is a notification raised by a monitor.
+type Alert struct {
+	Key      string    `json:"key"` // Alerts with the same key are deduplicated
+	Severity string    `json:"severity"`
+	Header   string    `json:"header"`
+	Body     string    `json:"body"`
+	Time     time.Time `json:"time"`
+}
+
+// Alerter is a sink which delivers alerts to an external system.
+type Alerter interface {
+	Name() string
+	Alert(alert *Alert) error
+}
+
+// AlertConfig contains the settings of the alert sinks and the alert manager.
+type AlertConfig struct {
+	SlackURL            string        // Slack incoming webhook URL
+	WebhookURL          string        // Generic endpoint receiving the alerts as JSON
+	FilePath            string        // File the alerts are appended to as JSON lines
+	PagerDutyRoutingKey string        // PagerDuty Events API v2 routing key
+	OpsgenieAPIKey      string        // Opsgenie Alert API key
+	RateLimit           int           // Maximum number of alerts sent per minute, 0 means unlimited
+	DedupWindow         time.Duration // Alerts with the same key are dropped within this window
+}
+
+// DefaultAlertConfig contains the default alert manager settings.
+var DefaultAlertConfig = AlertConfig{
+	RateLimit:   30,
+	DedupWindow: 10 * time.Minute,
+}
+
+// pendingAlert is an alert waiting to be delivered to a sink.
+type pendingAlert struct {
+	alert    *Alert
+	sink     Alerter
+	attempts int
+	next     time.Time
+}
+
+// AlertManager deduplicates, rate limits and fans out the alerts to all the
+// configured sinks. Failed deliveries are retried with an exponential backoff.
+type AlertManager struct {
+	sinks         []Alerter
+	limiter       *rate.Limiter
+	dedupWindow   time.Duration
+	retryInterval time.Duration
+
+	lock  sync.Mutex
+	seen  *lru.Cache[string, time.Time]
+	queue chan *pendingAlert
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewAlertManager creates the alert sinks from the config and starts the
+// delivery loop.
+func NewAlertManager(config AlertConfig) *AlertManager {
+	var sinks []Alerter
+	if config.SlackURL != "" {
+		sinks = append(sinks, newSlackAlerter(config.SlackURL))
+	}
+	if config.WebhookURL != "" {
+		sinks = append(sinks, newWebhookAlerter(config.WebhookURL))
+	}
+	if config.FilePath != "" {
+		sinks = append(sinks, newFileAlerter(config.FilePath))
+	}
+	if config.PagerDutyRoutingKey != "" {
+		sinks = append(sinks, newPagerDutyAlerter(config.PagerDutyRoutingKey))
+	}
+	if config.OpsgenieAPIKey != "" {
+		sinks = append(sinks, newOpsgenieAlerter(config.OpsgenieAPIKey))
+	}
+	return newAlertManager(sinks, config.RateLimit, config.DedupWindow, alertRetryInterval)
+}
+
+func newAlertManager(sinks []Alerter, rateLimit int, dedupWindow time.Duration, retryInterval time.Duration) *AlertManager {
+	seen, _ := lru.New[string, time.Time](alertDedupCache)
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	if rateLimit > 0 {
+		limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(rateLimit)), rateLimit)
+	}
+	manager := &AlertManager{
+		sinks:         sinks,
+		limiter:       limiter,
+		dedupWindow:   dedupWindow,
+		retryInterval: retryInterval,
+		seen:          seen,
+		queue:         make(chan *pendingAlert, alertQueueSize),
+		quit:          make(chan struct{}),
+	}
+	manager.wg.Add(1)
+	go manager.loop()
+	return manager
+}
+
+// Alert queues the alert for delivery to all sinks. It never blocks, alerts
+// are dropped when they are duplicated, rate limited or the queue is full.
+func (m *AlertManager) Alert(alert *Alert) {
+	if m == nil || len(m.sinks) == 0 {
+		return
+	}
+	if alert.Time.IsZero() {
+		alert.Time = time.Now()
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	dedup := alert.Key != "" && m.dedupWindow > 0
+	if dedup {
+		if last, ok := m.seen.Get(alert.Key); ok && alert.Time.Sub(last) < m.dedupWindow {
+			log.Debug("Dropped duplicated alert", "key", alert.Key)
+			return
+		}
+	}
+	if !m.limiter.Allow() {
+		log.Warn("Alert rate limit exceeded, dropping alert", "key", alert.Key, "header", alert.Header)
+		return
+	}
+	queued := false
+	for _, sink := range m.sinks {
+		select {
+		case m.queue <- &pendingAlert{alert: alert, sink: sink}:
+			queued = true
+		default:
+			log.Warn("Alert queue is full, dropping alert", "sink", sink.Name(), "key", alert.Key)
+		}
+	}
+	// The key is only recorded once the alert is sent, so that a dropped alert
+	// does not suppress the next ones
+	if dedup && queued {
+		m.seen.Add(alert.Key, alert.Time)
+	}
+}
+
+// Stop terminates the delivery loop, the alerts waiting for a retry are dropped.
+func (m *AlertManager) Stop() {
+	if m == nil {
+		return
+	}
+	close(m.quit)
+	m.wg.Wait()
+}
+
+func (m *AlertManager) loop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.retryInterval)
+	defer ticker.Stop()
+
+	var retries []*pendingAlert
+	for {
+		select {
+		case pending := <-m.queue:
+			if !m.deliver(pending) {
+				if len(retries) >= maxAlertRetries {
+					log.Warn("Alert retry queue is full, dropping oldest alert", "sink", retries[0].sink.Name(), "key", retries[0].alert.Key)
+					retries = retries[1:]
+				}
+				retries = append(retries, pending)
+			}
+		case now := <-ticker.C:
+			remaining := retries[:0]
+			for _, pending := range retries {
+				if now.Before(pending.next) || !m.deliver(pending) {
+					remaining = append(remaining, pending)
+				}
+			}
+			retries = remaining
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// deliver sends the alert to its sink, it returns false if the delivery should
+// be retried later.
+func (m *AlertManager) deliver(pending *pendingAlert) bool {
+	err := pending.sink.Alert(pending.alert)
+	if err == nil {
+		return true
+	}
+	pending.attempts++
+	if pending.attempts >= maxAlertAttempts {
+		log.Error("Failed to deliver alert, giving up", "sink", pending.sink.Name(), "key", pending.alert.Key, "attempts", pending.attempts, "err", err)
+		return true
+	}
+	pending.next = time.Now().Add(m.retryInterval << (pending.attempts - 1))
+	log.Warn("Failed to deliver alert, will retry", "sink", pending.sink.Name(), "key", pending.alert.Key, "attempts", pending.attempts, "err", err)
+	return false
+}
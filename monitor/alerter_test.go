@@ -0,0 +1,101 @@
+package monitor
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+type testAlerter struct {
+	lock     sync.Mutex
+	failures int
+	alerts   []*Alert
+}
+
+func (alerter *testAlerter) Name() string {
+	return "test"
+}
+
+func (alerter *testAlerter) Alert(alert *Alert) error {
+	alerter.lock.Lock()
+	defer alerter.lock.Unlock()
+
+	if alerter.failures > 0 {
+		alerter.failures--
+		return errors.New("delivery failed")
+	}
+	alerter.alerts = append(alerter.alerts, alert)
+	return nil
+}
+
+func (alerter *testAlerter) delivered() int {
+	alerter.lock.Lock()
+	defer alerter.lock.Unlock()
+
+	return len(alerter.alerts)
+}
+
+func waitDelivered(t *testing.T, alerter *testAlerter, want int) {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if alerter.delivered() == want {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Expect %d delivered alerts, got %d", want, alerter.delivered())
+}
+
+func TestAlertManagerDedup(t *testing.T) {
+	sink := &testAlerter{}
+	manager := newAlertManager([]Alerter{sink}, 0, time.Minute, alertRetryInterval)
+	defer manager.Stop()
+
+	manager.Alert(&Alert{Key: "a", Header: "first"})
+	manager.Alert(&Alert{Key: "a", Header: "duplicated"})
+	manager.Alert(&Alert{Key: "b", Header: "second"})
+	waitDelivered(t, sink, 2)
+}
+
+func TestAlertManagerRateLimit(t *testing.T) {
+	sink := &testAlerter{}
+	manager := newAlertManager([]Alerter{sink}, 2, 0, alertRetryInterval)
+	defer manager.Stop()
+
+	for i := 0; i < 5; i++ {
+		manager.Alert(&Alert{Header: "alert"})
+	}
+	waitDelivered(t, sink, 2)
+}
+
+func TestAlertManagerRetry(t *testing.T) {
+	sink := &testAlerter{failures: 2}
+	manager := newAlertManager([]Alerter{sink}, 0, 0, 10*time.Millisecond)
+	defer manager.Stop()
+
+	manager.Alert(&Alert{Key: "retry", Header: "alert"})
+	waitDelivered(t, sink, 1)
+}
+
+func TestAlertManagerDedupDropped(t *testing.T) {
+	sink := &testAlerter{}
+	manager := newAlertManager([]Alerter{sink}, 0, time.Minute, alertRetryInterval)
+	defer manager.Stop()
+
+	// The rate limited alert does not suppress the next one with the same key
+	manager.limiter = rate.NewLimiter(0, 0)
+	manager.Alert(&Alert{Key: "a", Header: "rate limited"})
+	manager.limiter = rate.NewLimiter(rate.Inf, 0)
+	manager.Alert(&Alert{Key: "a", Header: "sent"})
+	waitDelivered(t, sink, 1)
+
+	manager.Alert(&Alert{Key: "a", Header: "duplicated"})
+	manager.Alert(&Alert{Key: "b", Header: "second"})
+	waitDelivered(t, sink, 2)
+	if header := sink.alerts[0].Header; header != "sent" {
+		t.Fatalf("Expect alert %q delivered, got %q", "sent", header)
+	}
+}
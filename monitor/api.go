@@ -0,0 +1,118 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// maxEvidenceBlockRange is the maximum block range that can be queried in one
+// GetDoubleSignEvidence call.
+const maxEvidenceBlockRange = 100_000
+
+// API exposes the data collected by the monitors over RPC.
+type API struct {
+	chain  consensus.ChainHeaderReader
+	db     ethdb.Database
+	uptime *ValidatorUptimeMonitor
+}
+
+// NewAPI creates a new monitor API, uptime is nil when the validator uptime
+// monitor is disabled.
+func NewAPI(chain consensus.ChainHeaderReader, db ethdb.Database, uptime *ValidatorUptimeMonitor) *API {
+	return &API{chain: chain, db: db, uptime: uptime}
+}
+
+// DoubleSignEvidence is the RPC representation of a double sign evidence. The
+// Input field can be submitted as is to the consortiumVerifyHeaders precompiled
+// contract, Header1 and Header2 are its encoded header arguments.
+type DoubleSignEvidence struct {
+	Signer         common.Address `json:"signer"`
+	BlockNumber    hexutil.Uint64 `json:"blockNumber"`
+	Header1        *types.Header  `json:"header1"`
+	Header2        *types.Header  `json:"header2"`
+	EncodedHeader1 hexutil.Bytes  `json:"encodedHeader1"`
+	EncodedHeader2 hexutil.Bytes  `json:"encodedHeader2"`
+	Input          hexutil.Bytes  `json:"input"`
+}
+
+// GetDoubleSignEvidence returns the double sign evidences detected in the block
+// range [from, to]. The range defaults to the latest maxEvidenceBlockRange blocks.
+func (api *API) GetDoubleSignEvidence(from *hexutil.Uint64, to *hexutil.Uint64) ([]*DoubleSignEvidence, error) {
+	head := api.chain.CurrentHeader()
+	last := head.Number.Uint64()
+	if to != nil {
+		last = uint64(*to)
+	}
+	var first uint64
+	if from != nil {
+		first = uint64(*from)
+	} else if last > maxEvidenceBlockRange {
+		first = last - maxEvidenceBlockRange
+	}
+	if first > last {
+		return nil, errors.New("invalid block range")
+	}
+	if last-first > maxEvidenceBlockRange {
+		return nil, errors.New("block range is too large")
+	}
+	// The encoding follows the rules at the current head, as the slash
+	// transaction is executed on top of it.
+	var (
+		config   = api.chain.Config()
+		isVenoki = config.IsVenoki(head.Number)
+		result   = make([]*DoubleSignEvidence, 0)
+	)
+	for _, evidence := range rawdb.ReadDoubleSignEvidencesInRange(api.db, first, last) {
+		encodedHeader1, err := vm.EncodeDoubleSignHeader(evidence.Header1, config.ChainID, isVenoki)
+		if err != nil {
+			return nil, err
+		}
+		encodedHeader2, err := vm.EncodeDoubleSignHeader(evidence.Header2, config.ChainID, isVenoki)
+		if err != nil {
+			return nil, err
+		}
+		input, err := vm.EncodeDoubleSignProof(evidence.Signer, evidence.Header1, evidence.Header2, config.ChainID, isVenoki)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &DoubleSignEvidence{
+			Signer:         evidence.Signer,
+			BlockNumber:    hexutil.Uint64(evidence.Header1.Number.Uint64()),
+			Header1:        evidence.Header1,
+			Header2:        evidence.Header2,
+			EncodedHeader1: encodedHeader1,
+			EncodedHeader2: encodedHeader2,
+			Input:          input,
+		})
+	}
+	return result, nil
+}
+
+// GetValidatorUptime returns the block production statistics of the validators
+// in the given epoch, the epoch defaults to the latest tracked one.
+func (api *API) GetValidatorUptime(epoch *hexutil.Uint64) (*EpochUptime, error) {
+	if api.uptime == nil {
+		return nil, errors.New("validator uptime monitor is not enabled")
+	}
+	epochs := api.uptime.Epochs()
+	if len(epochs) == 0 {
+		return nil, errors.New("no epoch has been tracked yet")
+	}
+	number := epochs[len(epochs)-1]
+	if epoch != nil {
+		number = uint64(*epoch)
+	}
+	result := api.uptime.Uptime(number)
+	if result == nil {
+		return nil, fmt.Errorf("epoch %d is not tracked, available epochs: %v", number, epochs)
+	}
+	return result, nil
+}
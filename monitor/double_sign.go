@@ -3,10 +3,13 @@ package monitor
 import (
 	"bytes"
 	"encoding/hex"
+	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	lru "github.com/hashicorp/golang-lru/v2"
 )
@@ -14,15 +17,21 @@ import (
 const monitorBlockRange = 20
 
 type DoubleSignMonitor struct {
+	db              ethdb.KeyValueStore
+	alerter         *AlertManager
 	observerdBlocks *lru.Cache[common.Hash, []*types.Header]
 }
 
-func NewDoubleSignMonitor() (*DoubleSignMonitor, error) {
+// NewDoubleSignMonitor creates a double sign monitor, the detected double sign
+// evidences are persisted into db when it is not nil.
+func NewDoubleSignMonitor(db ethdb.KeyValueStore, alerter *AlertManager) (*DoubleSignMonitor, error) {
 	observerdBlocks, err := lru.New[common.Hash, []*types.Header](monitorBlockRange)
 	if err != nil {
 		return nil, err
 	}
 	monitor := DoubleSignMonitor{
+		db:              db,
+		alerter:         alerter,
 		observerdBlocks: observerdBlocks,
 	}
 
@@ -36,19 +45,49 @@ func getSignature(blockHeader *types.Header) string {
 
 func (monitor *DoubleSignMonitor) CheckDoubleSign(blockHeader *types.Header) {
 	if blockHeaders, ok := monitor.observerdBlocks.Get(blockHeader.ParentHash); ok {
+		for _, header := range blockHeaders {
+			if bytes.Equal(header.Hash().Bytes(), blockHeader.Hash().Bytes()) {
+				return
+			}
+		}
 		for _, header := range blockHeaders {
 			// Simple check for monitoring only
-			if !bytes.Equal(header.Hash().Bytes(), blockHeader.Hash().Bytes()) &&
-				bytes.Equal(header.Coinbase[:], blockHeader.Coinbase[:]) {
+			if bytes.Equal(header.Coinbase[:], blockHeader.Coinbase[:]) {
 				log.Error("Double sign detected", "block number", header.Number, "signer", header.Coinbase,
 					"block 1 hash", header.Hash().Hex(), "block 1 signature", getSignature(header),
 					"block 2 hash", blockHeader.Hash().Hex(), "block 2 signature", getSignature(blockHeader),
 				)
+				monitor.alerter.Alert(&Alert{
+					Key:      fmt.Sprintf("double-sign-%d-%s", header.Number, header.Coinbase.Hex()),
+					Severity: SeverityCritical,
+					Header:   "Double sign detected",
+					Body: fmt.Sprintf("- Signer: %s\n- Block number: %d\n- Block 1 hash: %s\n- Block 2 hash: %s\n",
+						header.Coinbase.Hex(), header.Number, header.Hash().Hex(), blockHeader.Hash().Hex()),
+				})
+				monitor.storeEvidence(header, blockHeader)
 				break
 			}
 		}
+		monitor.observerdBlocks.Add(blockHeader.ParentHash, append(blockHeaders, blockHeader))
 	} else {
 		blockHeaders := []*types.Header{blockHeader}
 		monitor.observerdBlocks.Add(blockHeader.ParentHash, blockHeaders)
 	}
 }
+
+// storeEvidence persists the conflicting headers, only the first evidence of a
+// signer at a block height is kept.
+func (monitor *DoubleSignMonitor) storeEvidence(header1, header2 *types.Header) {
+	if monitor.db == nil {
+		return
+	}
+	number := header1.Number.Uint64()
+	if rawdb.ReadDoubleSignEvidence(monitor.db, number, header1.Coinbase) != nil {
+		return
+	}
+	rawdb.WriteDoubleSignEvidence(monitor.db, &rawdb.DoubleSignEvidence{
+		Signer:  header1.Coinbase,
+		Header1: header1,
+		Header2: header2,
+	})
+}
@@ -0,0 +1,51 @@
+package monitor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestCheckDoubleSignStoresEvidence(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	monitor, err := NewDoubleSignMonitor(db, nil)
+	if err != nil {
+		t.Fatalf("Failed to create double sign monitor, err %s", err)
+	}
+
+	signer := common.Address{0x1}
+	newHeader := func(coinbase common.Address, time uint64) *types.Header {
+		return &types.Header{
+			ParentHash: common.Hash{0x1},
+			Number:     big.NewInt(10),
+			Coinbase:   coinbase,
+			Time:       time,
+			Extra:      make([]byte, crypto.SignatureLength),
+		}
+	}
+
+	monitor.CheckDoubleSign(newHeader(signer, 1))
+	monitor.CheckDoubleSign(newHeader(common.Address{0x2}, 2))
+	if evidence := rawdb.ReadDoubleSignEvidence(db, 10, signer); evidence != nil {
+		t.Fatalf("Expect no evidence, got %v", evidence)
+	}
+
+	monitor.CheckDoubleSign(newHeader(signer, 3))
+	evidence := rawdb.ReadDoubleSignEvidence(db, 10, signer)
+	if evidence == nil {
+		t.Fatal("Expect double sign evidence to be stored")
+	}
+	if evidence.Header1.Time != 1 || evidence.Header2.Time != 3 {
+		t.Fatalf("Unexpected evidence headers, got %d and %d", evidence.Header1.Time, evidence.Header2.Time)
+	}
+	if evidences := rawdb.ReadDoubleSignEvidencesInRange(db, 0, 9); len(evidences) != 0 {
+		t.Fatalf("Expect no evidence in range, got %d", len(evidences))
+	}
+	if evidences := rawdb.ReadDoubleSignEvidencesInRange(db, 10, 20); len(evidences) != 1 {
+		t.Fatalf("Expect 1 evidence in range, got %d", len(evidences))
+	}
+}
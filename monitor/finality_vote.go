@@ -26,12 +26,13 @@ type FinalityVoteMonitor struct {
 	chain         consensus.ChainHeaderReader
 	engine        consensus.FastFinalityPoSA
 	observedVotes *lru.Cache[uint64, []blockInformation]
-	alerter       *slackAlerter
+	alerter       *AlertManager
 }
 
 func NewFinalityVoteMonitor(
 	chain consensus.ChainHeaderReader,
 	engine consensus.FastFinalityPoSA,
+	alerter *AlertManager,
 ) (*FinalityVoteMonitor, error) {
 	observedVotes, err := lru.New[uint64, []blockInformation](finalityVoteCache)
 	if err != nil {
@@ -42,7 +43,7 @@ func NewFinalityVoteMonitor(
 		chain:         chain,
 		engine:        engine,
 		observedVotes: observedVotes,
-		alerter:       NewSlackAlert(),
+		alerter:       alerter,
 	}, nil
 }
 
@@ -158,9 +159,12 @@ func (monitor *FinalityVoteMonitor) checkSameHeightVote(
 						common.Bytes2Hex(aggregatedSignature.Marshal()),
 					)
 
-					if monitor.alerter != nil {
-						monitor.alerter.Alert(alertHeader, alertBody)
-					}
+					monitor.alerter.Alert(&Alert{
+						Key:      fmt.Sprintf("finality-vote-%d-%x", blockNumber, blockPublicKey.Marshal()),
+						Severity: SeverityCritical,
+						Header:   alertHeader,
+						Body:     alertBody,
+					})
 					log.Error(alertHeader, "message", alertBody)
 
 					violated = true
@@ -9,7 +9,7 @@ import (
 )
 
 func TestCheckSameHeightVote(t *testing.T) {
-	monitor, err := NewFinalityVoteMonitor(nil, nil)
+	monitor, err := NewFinalityVoteMonitor(nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed to create finality vote monitor, err %s", err)
 	}
@@ -4,14 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
-	"os"
-	"strings"
-
-	"github.com/ethereum/go-ethereum/log"
 )
 
-const reponseBuffer = 2048
-
 type slackAlerter struct {
 	url    string
 	client *http.Client
@@ -47,39 +41,26 @@ func formatMessage(header, body string) string {
 	return string(message)
 }
 
-func (alerter *slackAlerter) Alert(header, body string) {
-	message := formatMessage(header, body)
-
-	request, err := http.NewRequest("POST", alerter.url, strings.NewReader(message))
-	if err != nil {
-		log.Error("Failed to send Slack alert", "err", err)
-		return
-	}
+func (alerter *slackAlerter) Name() string {
+	return "slack"
+}
 
-	response, err := alerter.client.Do(request)
-	if err != nil {
-		log.Error("Failed to send HTTP request", "err", err)
-		return
-	}
+func (alerter *slackAlerter) Alert(alert *Alert) error {
+	return postJSON(alerter.client, alerter.url, nil, json.RawMessage(formatMessage(alert.Header, alert.Body)))
+}
 
-	if response.StatusCode >= 400 {
-		responseBody := make([]byte, reponseBuffer)
-		response.Body.Read(responseBody)
-		log.Error("Error response from server", "status", response.StatusCode, "body", string(responseBody))
-		return
+func newSlackAlerter(url string) *slackAlerter {
+	return &slackAlerter{
+		url:    url,
+		client: newAlertHTTPClient(),
 	}
 }
 
-func NewSlackAlert() *slackAlerter {
-	slackUrl := os.Getenv("SLACK_WEBHOOK_URL")
-	client := &http.Client{
+func newAlertHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: alertRequestTimeout,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return errors.New("invalid redirect")
 		},
 	}
-
-	return &slackAlerter{
-		url:    slackUrl,
-		client: client,
-	}
 }
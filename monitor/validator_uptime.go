@@ -0,0 +1,347 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// uptimeEpochsKept is the number of epochs whose statistics are kept in memory
+	uptimeEpochsKept = 10
+
+	// DefaultMissThreshold is the default number of consecutive missed in-turn
+	// slots before an alert is fired
+	DefaultMissThreshold = 3
+)
+
+var (
+	uptimeExpectedCounter = metrics.NewRegisteredCounter("monitor/uptime/expected", nil)
+	uptimeMissedCounter   = metrics.NewRegisteredCounter("monitor/uptime/missed", nil)
+)
+
+// ValidatorUptime is the block production statistics of a validator in an epoch.
+// Expected is the number of in-turn slots assigned to the validator, Missed is
+// the number of those slots filled by another validator and Produced is the
+// number of blocks sealed by the validator, in-turn or not.
+type ValidatorUptime struct {
+	Address  common.Address `json:"address"`
+	Expected hexutil.Uint64 `json:"expected"`
+	Produced hexutil.Uint64 `json:"produced"`
+	Missed   hexutil.Uint64 `json:"missed"`
+}
+
+// EpochUptime is the block production statistics of all validators in an epoch.
+type EpochUptime struct {
+	Epoch      hexutil.Uint64     `json:"epoch"`
+	FirstBlock hexutil.Uint64     `json:"firstBlock"`
+	LastBlock  hexutil.Uint64     `json:"lastBlock"`
+	Validators []*ValidatorUptime `json:"validators"`
+}
+
+type epochUptime struct {
+	firstBlock uint64
+	lastBlock  uint64
+	validators map[common.Address]*ValidatorUptime
+}
+
+func (e *epochUptime) validator(address common.Address) *ValidatorUptime {
+	uptime, ok := e.validators[address]
+	if !ok {
+		uptime = &ValidatorUptime{Address: address}
+		e.validators[address] = uptime
+	}
+	return uptime
+}
+
+type missStreak struct {
+	count      uint64
+	firstBlock uint64
+}
+
+// uptimeBlock is a counted block, kept so that the block can be uncounted when
+// it is reorged out of the canonical chain.
+type uptimeBlock struct {
+	hash   common.Hash
+	sealer common.Address
+	inturn common.Address
+}
+
+// ValidatorUptimeMonitor tracks the in-turn slots missed by the consortium v2
+// validators and alerts when a watched validator misses too many consecutive
+// slots.
+type ValidatorUptimeMonitor struct {
+	chain         consensus.ChainHeaderReader
+	engine        consensus.FastFinalityPoSA
+	watched       map[common.Address]struct{}
+	missThreshold uint64
+	alerter       *AlertManager
+
+	lock       sync.RWMutex
+	lastBlock  uint64
+	blocks     map[uint64]*uptimeBlock
+	epochs     map[uint64]*epochUptime
+	streaks    map[common.Address]*missStreak
+	missGauges map[common.Address]metrics.Gauge
+}
+
+func NewValidatorUptimeMonitor(
+	chain consensus.ChainHeaderReader,
+	engine consensus.FastFinalityPoSA,
+	watched []common.Address,
+	missThreshold uint64,
+	alerter *AlertManager,
+) *ValidatorUptimeMonitor {
+	if missThreshold == 0 {
+		missThreshold = DefaultMissThreshold
+	}
+	monitor := &ValidatorUptimeMonitor{
+		chain:         chain,
+		engine:        engine,
+		watched:       make(map[common.Address]struct{}, len(watched)),
+		missThreshold: missThreshold,
+		alerter:       alerter,
+		blocks:        make(map[uint64]*uptimeBlock),
+		epochs:        make(map[uint64]*epochUptime),
+		streaks:       make(map[common.Address]*missStreak),
+		missGauges:    make(map[common.Address]metrics.Gauge, len(watched)),
+	}
+	for _, address := range watched {
+		monitor.watched[address] = struct{}{}
+		monitor.missGauges[address] = metrics.GetOrRegisterGauge(
+			fmt.Sprintf("monitor/uptime/consecutiveMissed/%s", address.Hex()),
+			nil,
+		)
+	}
+	return monitor
+}
+
+// CheckBlock compares the sealer of the new canonical block against the
+// in-turn validator of its slot. When the block replaces an already counted
+// block (i.e. the chain is reorged), the counted blocks from the fork point are
+// uncounted and the new canonical blocks down to the fork point are counted
+// instead, so that each slot is counted once with its canonical block.
+func (monitor *ValidatorUptimeMonitor) CheckBlock(header *types.Header) {
+	if !monitor.countable(header) {
+		return
+	}
+	monitor.lock.RLock()
+	lastBlock := monitor.lastBlock
+	counted, ok := monitor.blocks[header.Number.Uint64()]
+	monitor.lock.RUnlock()
+	if header.Number.Uint64() <= lastBlock && (!ok || counted.hash == header.Hash()) {
+		return
+	}
+
+	// Collect the reorged ancestors whose counted block is not canonical anymore
+	headers := []*types.Header{header}
+	for {
+		child := headers[len(headers)-1]
+		number := child.Number.Uint64() - 1
+		monitor.lock.RLock()
+		counted, ok := monitor.blocks[number]
+		monitor.lock.RUnlock()
+		if !ok || counted.hash == child.ParentHash {
+			break
+		}
+		parent := monitor.chain.GetHeader(child.ParentHash, number)
+		if parent == nil || !monitor.countable(parent) {
+			break
+		}
+		headers = append(headers, parent)
+	}
+
+	inturns := make([]common.Address, len(headers))
+	for i, header := range headers {
+		number := header.Number.Uint64()
+		inturn, err := monitor.engine.GetInturnValidatorAt(monitor.chain, number-1, header.ParentHash)
+		if err != nil {
+			log.Warn("Failed to get in-turn validator", "number", number, "err", err)
+			return
+		}
+		inturns[i] = inturn
+	}
+
+	monitor.lock.Lock()
+	defer monitor.lock.Unlock()
+
+	first := headers[len(headers)-1].Number.Uint64()
+	for number := monitor.lastBlock; number >= first; number-- {
+		monitor.revertBlock(number)
+	}
+	if first <= monitor.lastBlock {
+		monitor.lastBlock = first - 1
+	}
+	for i := len(headers) - 1; i >= 0; i-- {
+		monitor.recordBlock(headers[i], inturns[i])
+	}
+}
+
+// countable returns whether the slot of the header is assigned to an in-turn
+// validator.
+func (monitor *ValidatorUptimeMonitor) countable(header *types.Header) bool {
+	return header.Number.Sign() > 0 && monitor.chain.Config().IsConsortiumV2(header.Number)
+}
+
+// revertBlock uncounts the block at the given number if it is counted. The
+// miss streaks reset by the reverted block are not restored.
+func (monitor *ValidatorUptimeMonitor) revertBlock(number uint64) {
+	block, ok := monitor.blocks[number]
+	if !ok {
+		return
+	}
+	delete(monitor.blocks, number)
+
+	epochNumber := number / monitor.chain.Config().Consortium.EpochV2
+	epoch, ok := monitor.epochs[epochNumber]
+	if !ok {
+		return
+	}
+	if number == epoch.firstBlock {
+		delete(monitor.epochs, epochNumber)
+	} else {
+		epoch.lastBlock = number - 1
+	}
+
+	uptimeExpectedCounter.Dec(1)
+	epoch.validator(block.inturn).Expected--
+	epoch.validator(block.sealer).Produced--
+	if block.sealer == block.inturn {
+		return
+	}
+	uptimeMissedCounter.Dec(1)
+	epoch.validator(block.inturn).Missed--
+	if streak, ok := monitor.streaks[block.inturn]; ok && streak.firstBlock <= number {
+		streak.count--
+		if streak.count == 0 {
+			delete(monitor.streaks, block.inturn)
+		}
+		if gauge, ok := monitor.missGauges[block.inturn]; ok {
+			gauge.Update(int64(streak.count))
+		}
+	}
+}
+
+func (monitor *ValidatorUptimeMonitor) recordBlock(header *types.Header, inturn common.Address) {
+	number, sealer := header.Number.Uint64(), header.Coinbase
+	if number <= monitor.lastBlock {
+		return
+	}
+	monitor.lastBlock = number
+	monitor.blocks[number] = &uptimeBlock{hash: header.Hash(), sealer: sealer, inturn: inturn}
+
+	epochLength := monitor.chain.Config().Consortium.EpochV2
+	epochNumber := number / epochLength
+	epoch, ok := monitor.epochs[epochNumber]
+	if !ok {
+		epoch = &epochUptime{
+			firstBlock: number,
+			validators: make(map[common.Address]*ValidatorUptime),
+		}
+		monitor.epochs[epochNumber] = epoch
+		if epochNumber >= uptimeEpochsKept {
+			if old, ok := monitor.epochs[epochNumber-uptimeEpochsKept]; ok {
+				for block := old.firstBlock; block <= old.lastBlock; block++ {
+					delete(monitor.blocks, block)
+				}
+				delete(monitor.epochs, epochNumber-uptimeEpochsKept)
+			}
+		}
+	}
+	epoch.lastBlock = number
+
+	uptimeExpectedCounter.Inc(1)
+	epoch.validator(inturn).Expected++
+	epoch.validator(sealer).Produced++
+
+	// Any block sealed by the validator proves that it is alive
+	delete(monitor.streaks, sealer)
+	if gauge, ok := monitor.missGauges[sealer]; ok {
+		gauge.Update(0)
+	}
+	if sealer == inturn {
+		return
+	}
+
+	uptimeMissedCounter.Inc(1)
+	epoch.validator(inturn).Missed++
+
+	streak, ok := monitor.streaks[inturn]
+	if !ok {
+		streak = &missStreak{firstBlock: number}
+		monitor.streaks[inturn] = streak
+	}
+	streak.count++
+	if gauge, ok := monitor.missGauges[inturn]; ok {
+		gauge.Update(int64(streak.count))
+	}
+
+	if _, ok := monitor.watched[inturn]; !ok || streak.count != monitor.missThreshold {
+		return
+	}
+	alertHeader := "Validator missed consecutive in-turn slots"
+	alertBody := fmt.Sprintf(
+		"- Validator: %s\n"+
+			"- Consecutive missed slots: %d\n"+
+			"- First missed block: %d\n"+
+			"- Last missed block: %d\n",
+		inturn.Hex(),
+		streak.count,
+		streak.firstBlock,
+		number,
+	)
+	monitor.alerter.Alert(&Alert{
+		Key:      fmt.Sprintf("validator-downtime-%s-%d", inturn.Hex(), streak.firstBlock),
+		Severity: SeverityWarning,
+		Header:   alertHeader,
+		Body:     alertBody,
+	})
+	log.Warn(alertHeader, "validator", inturn, "missed", streak.count, "from", streak.firstBlock, "to", number)
+}
+
+// Epochs returns the numbers of the epochs whose statistics are available,
+// in ascending order.
+func (monitor *ValidatorUptimeMonitor) Epochs() []uint64 {
+	monitor.lock.RLock()
+	defer monitor.lock.RUnlock()
+
+	epochs := make([]uint64, 0, len(monitor.epochs))
+	for epoch := range monitor.epochs {
+		epochs = append(epochs, epoch)
+	}
+	sort.Slice(epochs, func(i, j int) bool { return epochs[i] < epochs[j] })
+	return epochs
+}
+
+// Uptime returns the statistics of the given epoch, validators are sorted by
+// address. It returns nil if the epoch is not tracked.
+func (monitor *ValidatorUptimeMonitor) Uptime(epochNumber uint64) *EpochUptime {
+	monitor.lock.RLock()
+	defer monitor.lock.RUnlock()
+
+	epoch, ok := monitor.epochs[epochNumber]
+	if !ok {
+		return nil
+	}
+	result := &EpochUptime{
+		Epoch:      hexutil.Uint64(epochNumber),
+		FirstBlock: hexutil.Uint64(epoch.firstBlock),
+		LastBlock:  hexutil.Uint64(epoch.lastBlock),
+		Validators: make([]*ValidatorUptime, 0, len(epoch.validators)),
+	}
+	for _, uptime := range epoch.validators {
+		copied := *uptime
+		result.Validators = append(result.Validators, &copied)
+	}
+	sort.Slice(result.Validators, func(i, j int) bool {
+		return result.Validators[i].Address.Cmp(result.Validators[j].Address) < 0
+	})
+	return result
+}
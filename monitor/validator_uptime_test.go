@@ -0,0 +1,186 @@
+package monitor
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+type mockUptimeChain struct {
+	consensus.ChainHeaderReader
+	config  *params.ChainConfig
+	headers map[common.Hash]*types.Header
+}
+
+func (chain *mockUptimeChain) Config() *params.ChainConfig {
+	return chain.config
+}
+
+func (chain *mockUptimeChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	return chain.headers[hash]
+}
+
+// extend adds the headers sealed by the sealers on top of parent
+func (chain *mockUptimeChain) extend(parent *types.Header, sealers ...common.Address) []*types.Header {
+	var headers []*types.Header
+	for _, sealer := range sealers {
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			Coinbase:   sealer,
+		}
+		chain.headers[header.Hash()] = header
+		headers = append(headers, header)
+		parent = header
+	}
+	return headers
+}
+
+type mockUptimeEngine struct {
+	consensus.FastFinalityPoSA
+	validators []common.Address
+}
+
+func (engine *mockUptimeEngine) GetInturnValidatorAt(
+	chain consensus.ChainHeaderReader,
+	blockNumber uint64,
+	blockHash common.Hash,
+) (common.Address, error) {
+	return engine.validators[(blockNumber+1)%uint64(len(engine.validators))], nil
+}
+
+func TestValidatorUptime(t *testing.T) {
+	var (
+		validator1 = common.Address{0x1}
+		validator2 = common.Address{0x2}
+		chain      = &mockUptimeChain{
+			config: &params.ChainConfig{
+				ConsortiumV2Block: big.NewInt(0),
+				Consortium:        &params.ConsortiumConfig{EpochV2: 10},
+			},
+		}
+		engine = &mockUptimeEngine{validators: []common.Address{validator1, validator2}}
+		sink   = &testAlerter{}
+	)
+	alerter := newAlertManager([]Alerter{sink}, 0, time.Minute, time.Second)
+	defer alerter.Stop()
+	monitor := NewValidatorUptimeMonitor(chain, engine, []common.Address{validator2}, 3, alerter)
+
+	// Validator 2 is offline from block 1 to 9, validator 1 seals all these blocks
+	var header *types.Header
+	for number := uint64(1); number < 10; number++ {
+		header = &types.Header{Number: new(big.Int).SetUint64(number), Coinbase: validator1}
+		monitor.CheckBlock(header)
+	}
+	// Already checked blocks are ignored
+	monitor.CheckBlock(header)
+
+	uptime := monitor.Uptime(0)
+	if uptime == nil {
+		t.Fatal("Expect epoch 0 to be tracked")
+	}
+	if uptime.FirstBlock != 1 || uptime.LastBlock != 9 {
+		t.Fatalf("Wrong epoch range, got [%d, %d]", uptime.FirstBlock, uptime.LastBlock)
+	}
+	expected := []ValidatorUptime{
+		{Address: validator1, Expected: 4, Produced: 9, Missed: 0},
+		{Address: validator2, Expected: 5, Produced: 0, Missed: 5},
+	}
+	if len(uptime.Validators) != len(expected) {
+		t.Fatalf("Expect %d validators, got %d", len(expected), len(uptime.Validators))
+	}
+	for i, validator := range uptime.Validators {
+		if *validator != expected[i] {
+			t.Fatalf("Wrong uptime of validator %d, expect %+v, got %+v", i, expected[i], *validator)
+		}
+	}
+
+	// Only one alert is fired for the whole streak
+	waitDelivered(t, sink, 1)
+	time.Sleep(100 * time.Millisecond)
+	if delivered := sink.delivered(); delivered != 1 {
+		t.Fatalf("Expect 1 alert, got %d", delivered)
+	}
+
+	// Validator 2 comes back, a new streak is alerted again
+	monitor.CheckBlock(&types.Header{Number: big.NewInt(10), Coinbase: validator2})
+	for number := uint64(11); number < 20; number++ {
+		monitor.CheckBlock(&types.Header{Number: new(big.Int).SetUint64(number), Coinbase: validator1})
+	}
+	waitDelivered(t, sink, 2)
+
+	if epochs := monitor.Epochs(); len(epochs) != 2 || epochs[0] != 0 || epochs[1] != 1 {
+		t.Fatalf("Wrong tracked epochs %v", epochs)
+	}
+}
+
+func TestValidatorUptimeReorg(t *testing.T) {
+	var (
+		validator1 = common.Address{0x1}
+		validator2 = common.Address{0x2}
+		chain      = &mockUptimeChain{
+			config: &params.ChainConfig{
+				ConsortiumV2Block: big.NewInt(0),
+				Consortium:        &params.ConsortiumConfig{EpochV2: 10},
+			},
+			headers: make(map[common.Hash]*types.Header),
+		}
+		engine  = &mockUptimeEngine{validators: []common.Address{validator1, validator2}}
+		genesis = &types.Header{Number: common.Big0}
+	)
+	alerter := newAlertManager([]Alerter{&testAlerter{}}, 0, time.Minute, time.Second)
+	defer alerter.Stop()
+	monitor := NewValidatorUptimeMonitor(chain, engine, nil, 10, alerter)
+
+	checkUptime := func(lastBlock uint64, expected []ValidatorUptime) {
+		t.Helper()
+		uptime := monitor.Uptime(0)
+		if uptime == nil {
+			t.Fatal("Expect epoch 0 to be tracked")
+		}
+		if uptime.FirstBlock != 1 || uint64(uptime.LastBlock) != lastBlock {
+			t.Fatalf("Wrong epoch range, got [%d, %d]", uptime.FirstBlock, uptime.LastBlock)
+		}
+		if len(uptime.Validators) != len(expected) {
+			t.Fatalf("Expect %d validators, got %d", len(expected), len(uptime.Validators))
+		}
+		for i, validator := range uptime.Validators {
+			if *validator != expected[i] {
+				t.Fatalf("Wrong uptime of validator %d, expect %+v, got %+v", i, expected[i], *validator)
+			}
+		}
+	}
+
+	// Validator 1 seals all the blocks, validator 2 misses blocks 1, 3 and 5
+	oldChain := chain.extend(genesis, validator1, validator1, validator1, validator1, validator1, validator1)
+	for _, header := range oldChain {
+		monitor.CheckBlock(header)
+	}
+	checkUptime(6, []ValidatorUptime{
+		{Address: validator1, Expected: 3, Produced: 6, Missed: 0},
+		{Address: validator2, Expected: 3, Produced: 0, Missed: 3},
+	})
+
+	// The chain is reorged to a shorter fork after block 3 where validator 2
+	// seals block 5, only the new head is fed to the monitor
+	newChain := chain.extend(oldChain[2], validator1, validator2)
+	monitor.CheckBlock(newChain[1])
+	checkUptime(5, []ValidatorUptime{
+		{Address: validator1, Expected: 2, Produced: 4, Missed: 0},
+		{Address: validator2, Expected: 3, Produced: 1, Missed: 2},
+	})
+
+	// The blocks of the new chain are counted once
+	monitor.CheckBlock(newChain[0])
+	monitor.CheckBlock(newChain[1])
+	monitor.CheckBlock(chain.extend(newChain[1], validator1)[0])
+	checkUptime(6, []ValidatorUptime{
+		{Address: validator1, Expected: 3, Produced: 5, Missed: 0},
+		{Address: validator2, Expected: 3, Produced: 1, Missed: 2},
+	})
+}
@@ -185,6 +185,9 @@ func (api *privateAdminAPI) StartHTTP(host *string, port *int, cors *string, api
 		CorsAllowedOrigins: api.node.config.HTTPCors,
 		Vhosts:             api.node.config.HTTPVirtualHosts,
 		Modules:            api.node.config.HTTPModules,
+		jwtSecret:          api.node.jwtSecret,
+		accessRules:        api.node.config.RPCAccessRules,
+		limits:             api.node.config.RPCLimits,
 	}
 	if cors != nil {
 		config.CorsAllowedOrigins = nil
@@ -256,8 +259,11 @@ func (api *privateAdminAPI) StartWS(host *string, port *int, allowedOrigins *str
 
 	// Determine config.
 	config := wsConfig{
-		Modules: api.node.config.WSModules,
-		Origins: api.node.config.WSOrigins,
+		Modules:     api.node.config.WSModules,
+		Origins:     api.node.config.WSOrigins,
+		jwtSecret:   api.node.jwtSecret,
+		accessRules: api.node.config.RPCAccessRules,
+		limits:      api.node.config.RPCLimits,
 		// ExposeAll: api.node.config.WSExposeAll,
 	}
 	if apis != nil {
@@ -25,6 +25,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -141,6 +142,21 @@ type Config struct {
 	// HTTPPathPrefix specifies a path prefix on which http-rpc is to be served.
 	HTTPPathPrefix string `toml:",omitempty"`
 
+	// JWTSecret is the path to the hex encoded secret used to authenticate the HTTP
+	// and websocket RPC requests with HS256 signed JSON web tokens. A new secret is
+	// generated if the file does not exist. An empty path disables authentication.
+	JWTSecret string `toml:",omitempty"`
+
+	// RPCAccessRules maps the role claim of the authenticating tokens to the API
+	// namespaces (e.g. "eth") and methods (e.g. "debug_traceTransaction") callable
+	// with them. Requests without a token are refused unless an "anonymous" rule
+	// is defined, tokens without a role may call any method served.
+	RPCAccessRules map[string][]string `toml:",omitempty"`
+
+	// RPCLimits are the batch size and the per client rate limits applied to the
+	// HTTP and websocket RPC requests.
+	RPCLimits rpc.Limits
+
 	// WSHost is the host interface on which to start the websocket RPC server. If
 	// this field is empty, no websocket API endpoint will be started.
 	WSHost string
@@ -207,6 +223,17 @@ type Config struct {
 	// The path of password and encrypted BLS secret key used for fast finality voting
 	BlsPasswordPath string
 	BlsWalletPath   string
+	// The remote signer holding the BLS key, used instead of the local wallet when
+	// set. It speaks the Web3Signer eth2 signing API
+	BlsRemoteSignerURL       string        `toml:",omitempty"`
+	BlsRemoteSignerPublicKey string        `toml:",omitempty"`
+	BlsRemoteSignerTLSCert   string        `toml:",omitempty"`
+	BlsRemoteSignerTLSKey    string        `toml:",omitempty"`
+	BlsRemoteSignerTLSCA     string        `toml:",omitempty"`
+	BlsRemoteSignerTimeout   time.Duration `toml:",omitempty"`
+	// The journal of finality votes to survive node restarts, empty to disable
+	VoteJournal   string        `toml:",omitempty"`
+	VoteRejournal time.Duration `toml:",omitempty"`
 
 	DBEngine string `toml:",omitempty"`
 }
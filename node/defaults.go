@@ -52,6 +52,10 @@ var DefaultConfig = Config{
 		NAT:        nat.Any(),
 	},
 	DBEngine: "", // Use whatever exists, will default to Pebble if non-existent and supported
+	RPCLimits: rpc.Limits{
+		BatchItems:        1000,
+		BatchResponseSize: 25 * 1000 * 1000,
+	},
 }
 
 // DefaultDataDir is the default data directory to use for the databases and other
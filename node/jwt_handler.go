@@ -0,0 +1,207 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// jwtIssuedAtDrift is the maximum distance between the issuance time of a
+	// token without expiry and the local time.
+	jwtIssuedAtDrift = 60 * time.Second
+
+	// jwtSecretLength is the length of the secret signing the tokens.
+	jwtSecretLength = 32
+
+	// AnonymousRole is the access rule applied to the requests without a token.
+	// If it is not defined, such requests are refused.
+	AnonymousRole = "anonymous"
+)
+
+var (
+	errJWTMissing     = errors.New("missing token")
+	errJWTMalformed   = errors.New("malformed token")
+	errJWTAlgorithm   = errors.New("unsupported signing algorithm, want HS256")
+	errJWTSignature   = errors.New("invalid token signature")
+	errJWTNoIssuedAt  = errors.New("missing issued-at claim")
+	errJWTExpired     = errors.New("token expired")
+	errJWTStale       = errors.New("stale token, issued-at out of range")
+	errJWTUnknownRole = errors.New("unknown role")
+)
+
+// jwtClaims are the token claims checked by the server.
+type jwtClaims struct {
+	IssuedAt *int64 `json:"iat"`
+	Expiry   *int64 `json:"exp"`
+	Role     string `json:"role"`
+}
+
+// jwtHandler is a handler authenticating the incoming requests with HS256 signed
+// JSON web tokens, and restricting the methods they may call based on the role
+// claim of the token.
+type jwtHandler struct {
+	secret []byte
+	rules  map[string]rpc.AccessFilter
+	next   http.Handler
+}
+
+// newJWTHandler creates a http.Handler with JWT authentication support. The rules
+// map the token roles to the allowed namespaces and methods; tokens without a
+// role are granted all the methods served.
+func newJWTHandler(secret []byte, rules map[string][]string, next http.Handler) http.Handler {
+	filters := make(map[string]rpc.AccessFilter, len(rules))
+	for role, allowed := range rules {
+		filters[role] = rpc.NewAccessFilter(allowed)
+	}
+	return &jwtHandler{
+		secret: secret,
+		rules:  filters,
+		next:   next,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (handler *jwtHandler) ServeHTTP(out http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if auth == "" {
+		if filter, ok := handler.rules[AnonymousRole]; ok {
+			handler.next.ServeHTTP(out, r.WithContext(rpc.WithAccessFilter(r.Context(), filter)))
+			return
+		}
+		http.Error(out, errJWTMissing.Error(), http.StatusUnauthorized)
+		return
+	}
+	token := strings.TrimPrefix(auth, "Bearer ")
+	if token == auth {
+		http.Error(out, errJWTMalformed.Error(), http.StatusUnauthorized)
+		return
+	}
+	claims, err := verifyJWT(token, handler.secret, time.Now())
+	if err != nil {
+		http.Error(out, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if claims.Role == "" {
+		handler.next.ServeHTTP(out, r)
+		return
+	}
+	filter, ok := handler.rules[claims.Role]
+	if !ok {
+		http.Error(out, errJWTUnknownRole.Error(), http.StatusForbidden)
+		return
+	}
+	handler.next.ServeHTTP(out, r.WithContext(rpc.WithAccessFilter(r.Context(), filter)))
+}
+
+// verifyJWT checks the signature and the time claims of a HS256 token, returning
+// its claims. Tokens with an expiry are valid until then, the ones without must
+// have been issued within jwtIssuedAtDrift.
+func verifyJWT(token string, secret []byte, now time.Time) (*jwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errJWTMalformed
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if header.Alg != "HS256" {
+		return nil, errJWTAlgorithm
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errJWTMalformed
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, errJWTSignature
+	}
+	claims := new(jwtClaims)
+	if err := decodeJWTSegment(parts[1], claims); err != nil {
+		return nil, err
+	}
+	if claims.IssuedAt == nil {
+		return nil, errJWTNoIssuedAt
+	}
+	issued := time.Unix(*claims.IssuedAt, 0)
+	if issued.After(now.Add(jwtIssuedAtDrift)) {
+		return nil, errJWTStale
+	}
+	if claims.Expiry != nil {
+		if !now.Before(time.Unix(*claims.Expiry, 0)) {
+			return nil, errJWTExpired
+		}
+	} else if issued.Before(now.Add(-jwtIssuedAtDrift)) {
+		return nil, errJWTStale
+	}
+	return claims, nil
+}
+
+// decodeJWTSegment decodes a base64url encoded JSON segment of a token.
+func decodeJWTSegment(segment string, v interface{}) error {
+	blob, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errJWTMalformed
+	}
+	if err := json.Unmarshal(blob, v); err != nil {
+		return errJWTMalformed
+	}
+	return nil
+}
+
+// obtainJWTSecret loads the hex encoded JWT secret from the given file, or
+// generates a new one and stores it there if the file does not exist.
+func obtainJWTSecret(path string) ([]byte, error) {
+	if data, err := os.ReadFile(path); err == nil {
+		secret := common.FromHex(strings.TrimSpace(string(data)))
+		if len(secret) != jwtSecretLength {
+			return nil, fmt.Errorf("invalid JWT secret in %s: length %d, want %d", path, len(secret), jwtSecretLength)
+		}
+		log.Info("Loaded JWT secret file", "path", path)
+		return secret, nil
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	secret := make([]byte, jwtSecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(hexutil.Encode(secret)), 0600); err != nil {
+		return nil, err
+	}
+	log.Info("Generated JWT secret", "path", path)
+	return secret, nil
+}
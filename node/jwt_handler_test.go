@@ -0,0 +1,151 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// signJWT creates a token with the given header and claims.
+func signJWT(t *testing.T, secret []byte, header string, claims map[string]interface{}) string {
+	t.Helper()
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyJWT(t *testing.T) {
+	var (
+		secret = bytes.Repeat([]byte{0x01}, jwtSecretLength)
+		now    = time.Unix(1700000000, 0)
+		hs256  = `{"alg":"HS256","typ":"JWT"}`
+	)
+	tests := []struct {
+		token string
+		err   error
+	}{
+		{signJWT(t, secret, hs256, map[string]interface{}{"iat": now.Unix()}), nil},
+		{signJWT(t, secret, hs256, map[string]interface{}{"iat": now.Unix() - 30, "role": "admin"}), nil},
+		{signJWT(t, secret, hs256, map[string]interface{}{"iat": now.Unix() - 3600, "exp": now.Unix() + 60}), nil},
+		{signJWT(t, secret, hs256, map[string]interface{}{"iat": now.Unix() - 3600, "exp": now.Unix()}), errJWTExpired},
+		{signJWT(t, secret, hs256, map[string]interface{}{"iat": now.Unix() - 120}), errJWTStale},
+		{signJWT(t, secret, hs256, map[string]interface{}{"iat": now.Unix() + 120}), errJWTStale},
+		{signJWT(t, secret, hs256, map[string]interface{}{"role": "admin"}), errJWTNoIssuedAt},
+		{signJWT(t, secret, `{"alg":"none"}`, map[string]interface{}{"iat": now.Unix()}), errJWTAlgorithm},
+		{signJWT(t, bytes.Repeat([]byte{0x02}, jwtSecretLength), hs256, map[string]interface{}{"iat": now.Unix()}), errJWTSignature},
+		{"not.a-token", errJWTMalformed},
+	}
+	for i, tt := range tests {
+		if _, err := verifyJWT(tt.token, secret, now); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+// TestJWTAccessRules makes sure the requests are authenticated and restricted to
+// the methods allowed to their role.
+func TestJWTAccessRules(t *testing.T) {
+	secret := bytes.Repeat([]byte{0x01}, jwtSecretLength)
+	conf := &httpConfig{
+		jwtSecret: secret,
+		accessRules: map[string][]string{
+			AnonymousRole: {"eth"},
+			"admin":       {"rpc_modules"},
+		},
+	}
+	srv := createAndStartServer(t, conf, false, &wsConfig{})
+	defer srv.stop()
+	url := "http://" + srv.listenAddr()
+
+	bearer := func(claims map[string]interface{}) string {
+		claims["iat"] = time.Now().Unix()
+		return "Bearer " + signJWT(t, secret, `{"alg":"HS256","typ":"JWT"}`, claims)
+	}
+	tests := []struct {
+		headers []string
+		status  int
+		denied  bool
+	}{
+		{nil, http.StatusOK, true},
+		{[]string{"Authorization", bearer(map[string]interface{}{})}, http.StatusOK, false},
+		{[]string{"Authorization", bearer(map[string]interface{}{"role": "admin"})}, http.StatusOK, false},
+		{[]string{"Authorization", bearer(map[string]interface{}{"role": "unknown"})}, http.StatusForbidden, false},
+		{[]string{"Authorization", "Basic Zm9vOmJhcg=="}, http.StatusUnauthorized, false},
+	}
+	for i, tt := range tests {
+		resp := rpcRequest(t, url, tt.headers...)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, tt.status, resp.StatusCode, "test %d", i)
+		if tt.status == http.StatusOK {
+			assert.Equal(t, tt.denied, strings.Contains(string(body), "is not allowed"), "test %d: %s", i, body)
+		}
+	}
+	// Without an anonymous rule, requests without a token are refused
+	delete(conf.accessRules, AnonymousRole)
+	srv2 := createAndStartServer(t, conf, false, &wsConfig{})
+	defer srv2.stop()
+
+	resp := rpcRequest(t, "http://"+srv2.listenAddr())
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestObtainJWTSecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwtsecret")
+
+	secret, err := obtainJWTSecret(path)
+	if err != nil {
+		t.Fatalf("failed to generate secret: %v", err)
+	}
+	loaded, err := obtainJWTSecret(path)
+	if err != nil {
+		t.Fatalf("failed to load secret: %v", err)
+	}
+	if !bytes.Equal(secret, loaded) {
+		t.Fatalf("secret mismatch: have %x, want %x", loaded, secret)
+	}
+	if err := os.WriteFile(path, []byte("0xdeadbeef"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := obtainJWTSecret(path); err == nil {
+		t.Fatal("short secret accepted")
+	}
+}
@@ -57,6 +57,7 @@ type Node struct {
 	ws            *httpServer //
 	ipc           *ipcServer  // Stores information about the ipc http server
 	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
+	jwtSecret     []byte      // JWT secret authenticating the HTTP and WebSocket requests, if enabled
 
 	databases map[*closeTrackingDB]struct{} // All open databases
 }
@@ -352,6 +353,17 @@ func (n *Node) startRPC() error {
 		return err
 	}
 
+	// Load the secret authenticating the HTTP and WebSocket requests.
+	if n.config.JWTSecret != "" {
+		secret, err := obtainJWTSecret(n.config.JWTSecret)
+		if err != nil {
+			return err
+		}
+		n.jwtSecret = secret
+	} else if len(n.config.RPCAccessRules) > 0 {
+		log.Warn("RPC access rules ignored without JWT authentication")
+	}
+
 	// Configure IPC.
 	if n.ipc.endpoint != "" {
 		if err := n.ipc.start(n.rpcAPIs); err != nil {
@@ -366,6 +378,9 @@ func (n *Node) startRPC() error {
 			Vhosts:             n.config.HTTPVirtualHosts,
 			Modules:            n.config.HTTPModules,
 			prefix:             n.config.HTTPPathPrefix,
+			jwtSecret:          n.jwtSecret,
+			accessRules:        n.config.RPCAccessRules,
+			limits:             n.config.RPCLimits,
 		}
 		if err := n.http.setListenAddr(n.config.HTTPHost, n.config.HTTPPort); err != nil {
 			return err
@@ -384,6 +399,9 @@ func (n *Node) startRPC() error {
 			prefix:        n.config.WSPathPrefix,
 			wsreadbuffer:  n.config.WSReadBuffer,
 			wswritebuffer: n.config.WSWriteBuffer,
+			jwtSecret:     n.jwtSecret,
+			accessRules:   n.config.RPCAccessRules,
+			limits:        n.config.RPCLimits,
 		}
 		if err := server.setListenAddr(n.config.WSHost, n.config.WSPort); err != nil {
 			return err
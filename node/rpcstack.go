@@ -41,7 +41,10 @@ type httpConfig struct {
 	Modules            []string
 	CorsAllowedOrigins []string
 	Vhosts             []string
-	prefix             string // path prefix on which to mount http handler
+	prefix             string              // path prefix on which to mount http handler
+	jwtSecret          []byte              // optional JWT secret authenticating the requests
+	accessRules        map[string][]string // methods allowed to each token role
+	limits             rpc.Limits          // batch size and rate limits of the requests
 }
 
 // wsConfig is the JSON-RPC/Websocket configuration
@@ -51,6 +54,9 @@ type wsConfig struct {
 	prefix        string // path prefix on which to mount ws handler
 	wsreadbuffer  int
 	wswritebuffer int
+	jwtSecret     []byte              // optional JWT secret authenticating the requests
+	accessRules   map[string][]string // methods allowed to each token role
+	limits        rpc.Limits          // batch size and rate limits of the requests
 }
 
 type rpcHandler struct {
@@ -284,12 +290,17 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 
 	// Create RPC server and handler.
 	srv := rpc.NewServer()
+	srv.SetLimits(config.limits)
 	if err := RegisterApis(apis, config.Modules, srv, false); err != nil {
 		return err
 	}
 	h.httpConfig = config
+	handler := http.Handler(srv)
+	if config.jwtSecret != nil {
+		handler = newJWTHandler(config.jwtSecret, config.accessRules, handler)
+	}
 	h.httpHandler.Store(&rpcHandler{
-		Handler: NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts),
+		Handler: NewHTTPHandlerStack(handler, config.CorsAllowedOrigins, config.Vhosts),
 		server:  srv,
 	})
 	return nil
@@ -316,12 +327,17 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 
 	// Create RPC server and handler.
 	srv := rpc.NewServer()
+	srv.SetLimits(config.limits)
 	if err := RegisterApis(apis, config.Modules, srv, false); err != nil {
 		return err
 	}
 	h.wsConfig = config
+	handler := srv.WebsocketHandler(config.Origins, config.wsreadbuffer, config.wswritebuffer)
+	if config.jwtSecret != nil {
+		handler = newJWTHandler(config.jwtSecret, config.accessRules, handler)
+	}
 	h.wsHandler.Store(&rpcHandler{
-		Handler: srv.WebsocketHandler(config.Origins, config.wsreadbuffer, config.wswritebuffer),
+		Handler: handler,
 		server:  srv,
 	})
 	return nil
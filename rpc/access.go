@@ -0,0 +1,63 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"strings"
+)
+
+// AccessFilter reports whether a method may be called.
+type AccessFilter func(method string) bool
+
+// NewAccessFilter creates a filter allowing all the methods of the listed
+// namespaces (e.g. "eth") and the listed individual methods (e.g.
+// "debug_traceTransaction").
+func NewAccessFilter(allowed []string) AccessFilter {
+	namespaces, methods := make(map[string]struct{}), make(map[string]struct{})
+	for _, entry := range allowed {
+		if strings.Contains(entry, serviceMethodSeparator) {
+			methods[entry] = struct{}{}
+		} else {
+			namespaces[entry] = struct{}{}
+		}
+	}
+	return func(method string) bool {
+		if _, ok := methods[method]; ok {
+			return true
+		}
+		namespace := strings.SplitN(method, serviceMethodSeparator, 2)[0]
+		_, ok := namespaces[namespace]
+		return ok
+	}
+}
+
+type accessFilterKey struct{}
+
+// WithAccessFilter returns a copy of the context restricting the methods callable
+// by the requests served with it to the ones allowed by the filter. HTTP handlers
+// wrapping the server set it on the request context; for WebSocket connections
+// the filter of the upgrade request applies to the whole connection.
+func WithAccessFilter(ctx context.Context, filter AccessFilter) context.Context {
+	return context.WithValue(ctx, accessFilterKey{}, filter)
+}
+
+// accessFilterFrom retrieves the access filter of the context, if any.
+func accessFilterFrom(ctx context.Context) AccessFilter {
+	filter, _ := ctx.Value(accessFilterKey{}).(AccessFilter)
+	return filter
+}
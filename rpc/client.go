@@ -82,6 +82,7 @@ type Client struct {
 	idgen    func() ID // for subscriptions
 	scheme   string    // connection type: http, ws or ipc
 	services *serviceRegistry
+	limits   *limiter // limits of the server, if served by one
 
 	idCounter uint32
 
@@ -120,7 +121,11 @@ func (c *Client) newClientConn(conn ServerCodec) *clientConn {
 	if !c.isHTTP() && c.scheme != "" {
 		ctx = context.WithValue(ctx, "scheme", c.scheme)
 	}
+	if wc, ok := conn.(*websocketCodec); ok && wc.access != nil {
+		ctx = WithAccessFilter(ctx, wc.access)
+	}
 	handler := newHandler(ctx, conn, c.idgen, c.services)
+	handler.limits = c.limits
 	return &clientConn{conn, handler}
 }
 
@@ -206,12 +211,12 @@ func newClient(initctx context.Context, connect reconnectFunc) (*Client, error)
 	if err != nil {
 		return nil, err
 	}
-	c := initClient(conn, randomIDGenerator(), new(serviceRegistry))
+	c := initClient(conn, randomIDGenerator(), new(serviceRegistry), nil)
 	c.reconnectFunc = connect
 	return c, nil
 }
 
-func initClient(conn ServerCodec, idgen func() ID, services *serviceRegistry) *Client {
+func initClient(conn ServerCodec, idgen func() ID, services *serviceRegistry, limits *limiter) *Client {
 	scheme := ""
 	switch conn.(type) {
 	case *httpConn:
@@ -225,6 +230,7 @@ func initClient(conn ServerCodec, idgen func() ID, services *serviceRegistry) *C
 		idgen:       idgen,
 		scheme:      scheme,
 		services:    services,
+		limits:      limits,
 		writeConn:   conn,
 		close:       make(chan struct{}),
 		closing:     make(chan struct{}),
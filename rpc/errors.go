@@ -54,9 +54,24 @@ var (
 	_ Error = new(invalidRequestError)
 	_ Error = new(invalidMessageError)
 	_ Error = new(invalidParamsError)
+	_ Error = new(accessDeniedError)
+	_ Error = new(responseTooLargeError)
+	_ Error = new(rateLimitedError)
 )
 
-const defaultErrorCode = -32000
+const (
+	defaultErrorCode = -32000
+
+	// ErrcodeResponseTooLarge is returned for the calls of a batch left unanswered
+	// because its response exceeded the size limit of the server.
+	ErrcodeResponseTooLarge = -32003
+
+	// ErrcodeRateLimited is returned for the calls exceeding the rate limits of the
+	// server. Clients should back off before retrying them.
+	ErrcodeRateLimited = -32005
+)
+
+const errMsgBatchTooLarge = "batch too large"
 
 type methodNotFoundError struct{ method string }
 
@@ -66,6 +81,28 @@ func (e *methodNotFoundError) Error() string {
 	return fmt.Sprintf("the method %s does not exist/is not available", e.method)
 }
 
+type accessDeniedError struct{ method string }
+
+func (e *accessDeniedError) ErrorCode() int { return -32601 }
+
+func (e *accessDeniedError) Error() string {
+	return fmt.Sprintf("the method %s is not allowed", e.method)
+}
+
+type responseTooLargeError struct{}
+
+func (e *responseTooLargeError) ErrorCode() int { return ErrcodeResponseTooLarge }
+
+func (e *responseTooLargeError) Error() string { return "response too large" }
+
+type rateLimitedError struct{ method string }
+
+func (e *rateLimitedError) ErrorCode() int { return ErrcodeRateLimited }
+
+func (e *rateLimitedError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for %s", e.method)
+}
+
 type subscriptionNotFoundError struct{ namespace, subscription string }
 
 func (e *subscriptionNotFoundError) ErrorCode() int { return -32601 }
@@ -61,6 +61,7 @@ type handler struct {
 	conn           jsonWriter                     // where responses will be sent
 	log            log.Logger
 	allowSubscribe bool
+	limits         *limiter // batch and rate limits of the server, if any
 
 	subLock    sync.Mutex
 	serverSubs map[ID]*Subscription
@@ -101,6 +102,13 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 		})
 		return
 	}
+	if h.limits != nil && h.limits.BatchItems > 0 && len(msgs) > h.limits.BatchItems {
+		batchTooLargeMeter.Mark(1)
+		h.startCallProc(func(cp *callProc) {
+			h.conn.writeJSON(cp.ctx, errorMessage(&invalidRequestError{errMsgBatchTooLarge}))
+		})
+		return
+	}
 
 	// Handle non-call messages first:
 	calls := make([]*jsonrpcMessage, 0, len(msgs))
@@ -114,10 +122,29 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 	}
 	// Process calls on a goroutine because they may block indefinitely:
 	h.startCallProc(func(cp *callProc) {
-		answers := make([]*jsonrpcMessage, 0, len(msgs))
-		for _, msg := range calls {
-			if answer := h.handleCallMsg(cp, msg); answer != nil {
-				answers = append(answers, answer)
+		var (
+			answers = make([]*jsonrpcMessage, 0, len(msgs))
+			size    int
+		)
+		for i, msg := range calls {
+			answer := h.handleCallMsg(cp, msg)
+			if answer == nil {
+				continue
+			}
+			answers = append(answers, answer)
+
+			// Answer the remaining calls with an error once the response is too large
+			if h.limits == nil || h.limits.BatchResponseSize == 0 {
+				continue
+			}
+			if size += len(answer.Result); size > h.limits.BatchResponseSize {
+				responseTooLargeMeter.Mark(1)
+				for _, msg := range calls[i+1:] {
+					if msg.isCall() {
+						answers = append(answers, msg.errorResponse(&responseTooLargeError{}))
+					}
+				}
+				break
 			}
 		}
 		h.addSubscriptions(cp.notifiers)
@@ -317,6 +344,13 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 
 // handleCall processes method calls.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	if filter := accessFilterFrom(cp.ctx); filter != nil && !filter(msg.Method) {
+		return msg.errorResponse(&accessDeniedError{method: msg.Method})
+	}
+	if h.limits != nil && !h.limits.allow(h.conn.remoteAddr(), msg.Method) {
+		rateLimitedMeter.Mark(1)
+		return msg.errorResponse(&rateLimitedError{method: msg.Method})
+	}
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg)
 	}
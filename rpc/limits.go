@@ -0,0 +1,130 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/time/rate"
+)
+
+// maxRateLimitBuckets is the number of client rate limit buckets tracked. When
+// exceeded, the least recently used buckets are dropped, refilling them.
+const maxRateLimitBuckets = 65536
+
+// RateLimit is a token bucket rate limit.
+type RateLimit struct {
+	Rate  float64 // Calls per second, zero disables the limit
+	Burst int     // Calls allowed at once, defaults to the rate
+}
+
+// Limits are the limits the server applies to the requests of its clients. The
+// rate limits are tracked per client IP, and thus only apply to the HTTP and
+// WebSocket connections.
+type Limits struct {
+	BatchItems        int                  // Maximum number of calls in a batch, zero for unlimited
+	BatchResponseSize int                  // Maximum byte size of a batch response, zero for unlimited
+	PerIP             RateLimit            // Rate of all the calls of a client IP
+	PerMethod         map[string]RateLimit // Rate of the calls of a client IP to a method
+}
+
+// limiter enforces the limits of a server across all of its connections.
+type limiter struct {
+	Limits
+
+	mu      sync.Mutex
+	buckets *lru.Cache[string, *rate.Limiter] // Buckets keyed by IP and IP/method
+}
+
+// newLimiter creates a limiter enforcing the given limits, or nil if there are
+// none to enforce.
+func newLimiter(limits Limits) *limiter {
+	if limits.BatchItems == 0 && limits.BatchResponseSize == 0 && limits.PerIP.Rate == 0 && len(limits.PerMethod) == 0 {
+		return nil
+	}
+	buckets, _ := lru.New[string, *rate.Limiter](maxRateLimitBuckets)
+	return &limiter{Limits: limits, buckets: buckets}
+}
+
+// allow consumes a token of the client IP and of its bucket for the method,
+// reporting whether the call may proceed. Both buckets are checked before any
+// token is consumed, so a rejected call does not use up the other bucket.
+func (l *limiter) allow(remote string, method string) bool {
+	ip := remoteIP(remote)
+	if ip == "" {
+		return true
+	}
+	now := time.Now()
+	var methodReservation *rate.Reservation
+	if limit, ok := l.PerMethod[method]; ok && limit.Rate > 0 {
+		if methodReservation = reserve(l.bucket(ip+"/"+method, limit), now); methodReservation == nil {
+			newRateLimitedMeter(method).Mark(1)
+			return false
+		}
+	}
+	if l.PerIP.Rate > 0 && reserve(l.bucket(ip, l.PerIP), now) == nil {
+		if methodReservation != nil {
+			methodReservation.CancelAt(now)
+		}
+		ipRateLimitedMeter.Mark(1)
+		return false
+	}
+	return true
+}
+
+// reserve takes a token of the bucket if one is available right away, returning
+// the reservation to cancel for giving it back, or nil if the bucket is empty.
+func reserve(bucket *rate.Limiter, now time.Time) *rate.Reservation {
+	reservation := bucket.ReserveN(now, 1)
+	if !reservation.OK() {
+		return nil
+	}
+	if reservation.DelayFrom(now) > 0 {
+		reservation.CancelAt(now)
+		return nil
+	}
+	return reservation
+}
+
+// bucket retrieves the token bucket of the given key, creating it if needed.
+func (l *limiter) bucket(key string, limit RateLimit) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if bucket, ok := l.buckets.Get(key); ok {
+		return bucket
+	}
+	burst := limit.Burst
+	if burst < 1 {
+		burst = int(math.Ceil(limit.Rate))
+	}
+	bucket := rate.NewLimiter(rate.Limit(limit.Rate), burst)
+	l.buckets.Add(key, bucket)
+	return bucket
+}
+
+// remoteIP strips the port from the remote address of a connection.
+func remoteIP(remote string) string {
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		return host
+	}
+	return remote
+}
@@ -0,0 +1,139 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newLimitedTestServer starts an HTTP server enforcing the given limits.
+func newLimitedTestServer(t *testing.T, limits Limits) (*httptest.Server, *Client) {
+	t.Helper()
+
+	server := newTestServer()
+	server.SetLimits(limits)
+	if err := server.RegisterName("large", largeRespService{100}); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(server)
+	client, err := DialHTTP(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+		ts.Close()
+		server.Stop()
+	})
+	return ts, client
+}
+
+// checkErrorCode fails the test if err is not an RPC error with the given code.
+func checkErrorCode(t *testing.T, err error, code int) {
+	t.Helper()
+
+	rpcErr, ok := err.(Error)
+	if !ok {
+		t.Fatalf("expected RPC error with code %d, got %v", code, err)
+	}
+	if rpcErr.ErrorCode() != code {
+		t.Fatalf("error code mismatch: have %d, want %d (%v)", rpcErr.ErrorCode(), code, err)
+	}
+}
+
+func TestBatchItemLimit(t *testing.T) {
+	ts, _ := newLimitedTestServer(t, Limits{BatchItems: 2})
+
+	batch := `[{"jsonrpc":"2.0","id":1,"method":"test_rets"},{"jsonrpc":"2.0","id":2,"method":"test_rets"},{"jsonrpc":"2.0","id":3,"method":"test_rets"}]`
+	resp, err := http.Post(ts.URL, contentType, strings.NewReader(batch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), `"code":-32600`) || !strings.Contains(string(body), errMsgBatchTooLarge) {
+		t.Fatalf("oversized batch not rejected: %s", body)
+	}
+}
+
+func TestBatchResponseSizeLimit(t *testing.T) {
+	_, client := newLimitedTestServer(t, Limits{BatchResponseSize: 150})
+
+	batch := make([]BatchElem, 3)
+	for i := range batch {
+		batch[i] = BatchElem{Method: "large_largeResp", Result: new(string)}
+	}
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatal(err)
+	}
+	// The second response exceeds the limit, the remaining call is not answered
+	for i := 0; i < 2; i++ {
+		if batch[i].Error != nil {
+			t.Fatalf("call %d failed: %v", i, batch[i].Error)
+		}
+	}
+	checkErrorCode(t, batch[2].Error, ErrcodeResponseTooLarge)
+}
+
+func TestRateLimits(t *testing.T) {
+	_, client := newLimitedTestServer(t, Limits{
+		PerIP:     RateLimit{Rate: 0.001, Burst: 3},
+		PerMethod: map[string]RateLimit{"test_rets": {Rate: 0.001, Burst: 1}},
+	})
+	var res string
+
+	// The method limit applies on top of the IP one
+	if err := client.Call(&res, "test_rets"); err != nil {
+		t.Fatal(err)
+	}
+	checkErrorCode(t, client.Call(&res, "test_rets"), ErrcodeRateLimited)
+
+	// The rejected call does not consume a token of the IP
+	for i := 0; i < 2; i++ {
+		if err := client.Call(nil, "test_noArgsRets"); err != nil {
+			t.Fatalf("call %d failed: %v", i, err)
+		}
+	}
+	checkErrorCode(t, client.Call(nil, "test_noArgsRets"), ErrcodeRateLimited)
+}
+
+func TestRateLimitsRejectedByIP(t *testing.T) {
+	l := newLimiter(Limits{
+		PerIP:     RateLimit{Rate: 0.001, Burst: 1},
+		PerMethod: map[string]RateLimit{"test_rets": {Rate: 0.001, Burst: 1}},
+	})
+	remote := "127.0.0.1:1234"
+
+	if !l.allow(remote, "test_noArgsRets") {
+		t.Fatal("first call rejected")
+	}
+	// The call rejected by the IP limit does not consume a token of the method
+	if l.allow(remote, "test_rets") {
+		t.Fatal("call over the IP limit allowed")
+	}
+	if tokens := l.bucket("127.0.0.1/test_rets", l.PerMethod["test_rets"]).Tokens(); tokens < 1 {
+		t.Fatalf("method token consumed by rejected call, %f left", tokens)
+	}
+}
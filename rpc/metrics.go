@@ -27,6 +27,11 @@ var (
 	successfulRequestGauge = metrics.NewRegisteredGauge("rpc/success", nil)
 	failedReqeustGauge     = metrics.NewRegisteredGauge("rpc/failure", nil)
 	rpcServingTimer        = metrics.NewRegisteredTimer("rpc/duration/all", nil)
+
+	batchTooLargeMeter    = metrics.NewRegisteredMeter("rpc/limit/batch", nil)
+	responseTooLargeMeter = metrics.NewRegisteredMeter("rpc/limit/response", nil)
+	rateLimitedMeter      = metrics.NewRegisteredMeter("rpc/limit/rate", nil)
+	ipRateLimitedMeter    = metrics.NewRegisteredMeter("rpc/limit/iprate", nil)
 )
 
 func newRPCServingTimer(method string, valid bool) metrics.Timer {
@@ -46,3 +51,7 @@ func newRPCMethodCounter(method string, valid bool) metrics.Counter {
 	m := fmt.Sprintf("rpc/count/%s/%s", method, flag)
 	return metrics.GetOrRegisterCounter(m, nil)
 }
+
+func newRateLimitedMeter(method string) metrics.Meter {
+	return metrics.GetOrRegisterMeter("rpc/limit/rate/"+method, nil)
+}
@@ -60,7 +60,9 @@ func (s *Server) WebsocketHandler(allowedOrigins []string, wsReadBufferCfg int,
 			log.Debug("WebSocket upgrade failed", "err", err)
 			return
 		}
-		codec := newWebsocketCodec(conn)
+		codec := newWebsocketCodec(conn).(*websocketCodec)
+		codec.remote = r.RemoteAddr
+		codec.access = accessFilterFrom(r.Context())
 		s.ServeCodec(codec, 0)
 	})
 }
@@ -238,6 +240,8 @@ type websocketCodec struct {
 
 	wg        sync.WaitGroup
 	pingReset chan struct{}
+
+	access AccessFilter // Methods callable over the connection, set by the server
 }
 
 func newWebsocketCodec(conn *websocket.Conn) ServerCodec {
@@ -24,6 +24,7 @@ import (
 	"math/big"
 	"os"
 	"reflect"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
@@ -31,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/usbwallet"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
@@ -41,9 +43,9 @@ const (
 	// numberOfAccountsToDerive For hardware wallets, the number of accounts to derive
 	numberOfAccountsToDerive = 10
 	// ExternalAPIVersion -- see extapi_changelog.md
-	ExternalAPIVersion = "6.1.0"
+	ExternalAPIVersion = "6.2.0"
 	// InternalAPIVersion -- see intapi_changelog.md
-	InternalAPIVersion = "7.0.1"
+	InternalAPIVersion = "7.1.0"
 )
 
 // ExternalAPI defines the external API through which signing requests are made.
@@ -54,6 +56,8 @@ type ExternalAPI interface {
 	New(ctx context.Context) (common.Address, error)
 	// SignTransaction request to sign the specified transaction
 	SignTransaction(ctx context.Context, args apitypes.SendTxArgs, methodSelector *string) (*ethapi.SignTransactionResult, error)
+	// SignSponsoredTxAsPayer request to sign the specified sponsored transaction as its payer
+	SignSponsoredTxAsPayer(ctx context.Context, args apitypes.SendTxArgs, methodSelector *string) (*ethapi.SignTransactionResult, error)
 	// SignData - request to sign the given data (plus prefix)
 	SignData(ctx context.Context, contentType string, addr common.MixedcaseAddress, data interface{}) (hexutil.Bytes, error)
 	// SignTypedData - request to sign the given structured data (plus prefix)
@@ -71,6 +75,8 @@ type ExternalAPI interface {
 type UIClientAPI interface {
 	// ApproveTx prompt the user for confirmation to request to sign Transaction
 	ApproveTx(request *SignTxRequest) (SignTxResponse, error)
+	// ApprovePayerTx prompt the user for confirmation to request to sign sponsored Transaction as its payer
+	ApprovePayerTx(request *SignPayerTxRequest) (SignPayerTxResponse, error)
 	// ApproveSignData prompt the user for confirmation to request to sign data
 	ApproveSignData(request *SignDataRequest) (SignDataResponse, error)
 	// ApproveListing prompt the user for confirmation to list accounts
@@ -85,6 +91,9 @@ type UIClientAPI interface {
 	// OnApprovedTx notifies the UI about a transaction having been successfully signed.
 	// This method can be used by a UI to keep track of e.g. how much has been sent to a particular recipient.
 	OnApprovedTx(tx ethapi.SignTransactionResult)
+	// OnApprovedPayerTx notifies the UI about a sponsored transaction having been successfully signed by its payer.
+	// This method can be used by a UI to keep track of e.g. how much gas fee has been sponsored for a particular sender.
+	OnApprovedPayerTx(result SignPayerTxResult)
 	// OnSignerStartup is invoked when the signer boots, and tells the UI info about external API location and version
 	// information
 	OnSignerStartup(info StartupInfo)
@@ -231,6 +240,24 @@ type (
 		Transaction apitypes.SendTxArgs `json:"transaction"`
 		Approved    bool                `json:"approved"`
 	}
+	// SignPayerTxRequest contains info about a sponsored Transaction to sign as its payer
+	SignPayerTxRequest struct {
+		Transaction apitypes.SendTxArgs       `json:"transaction"`
+		Callinfo    []apitypes.ValidationInfo `json:"call_info"`
+		MaxFee      *hexutil.Big              `json:"max_fee"`
+		Time        hexutil.Uint64            `json:"time"`
+		Meta        Metadata                  `json:"meta"`
+	}
+	// SignPayerTxResponse result from SignPayerTxRequest
+	SignPayerTxResponse struct {
+		Approved bool `json:"approved"`
+	}
+	// SignPayerTxResult contains a sponsored Transaction signed by its payer
+	SignPayerTxResult struct {
+		Sender common.Address `json:"sender"`
+		MaxFee *hexutil.Big   `json:"max_fee"`
+		ethapi.SignTransactionResult
+	}
 	SignDataRequest struct {
 		ContentType string                    `json:"content_type"`
 		Address     common.MixedcaseAddress   `json:"address"`
@@ -613,6 +640,100 @@ func (api *SignerAPI) SignTransaction(ctx context.Context, args apitypes.SendTxA
 
 }
 
+// SignSponsoredTxAsPayer signs the given sponsored Transaction as its payer and returns it both as json and
+// rlp-encoded form. The returned transaction still needs to be signed by the sender.
+func (api *SignerAPI) SignSponsoredTxAsPayer(ctx context.Context, args apitypes.SendTxArgs, methodSelector *string) (*ethapi.SignTransactionResult, error) {
+	if args.Payer == nil {
+		return nil, errors.New("payer not specified")
+	}
+	if args.Payer.Address() == args.From.Address() {
+		return nil, types.ErrSamePayerSenderSponsoredTx
+	}
+	if args.AccessList != nil {
+		return nil, errors.New("access list is not supported in sponsored transaction")
+	}
+	if args.MaxFeePerGas == nil && args.GasPrice == nil {
+		return nil, errors.New("missing gasPrice or maxFeePerGas/maxPriorityFeePerGas")
+	}
+	msgs, err := api.validator.ValidateTransaction(methodSelector, &args)
+	if err != nil {
+		return nil, err
+	}
+	// If we are in 'rejectMode', then reject rather than show the user warnings
+	if api.rejectMode {
+		if err := msgs.GetWarnings(); err != nil {
+			return nil, err
+		}
+	}
+	if args.ChainID == nil {
+		args.ChainID = (*hexutil.Big)(api.chainID)
+	} else if requestedChainId := (*big.Int)(args.ChainID); api.chainID.Cmp(requestedChainId) != 0 {
+		log.Error("Signing request with wrong chain id", "requested", requestedChainId, "configured", api.chainID)
+		return nil, fmt.Errorf("requested chainid %d does not match the configuration of the signer",
+			requestedChainId)
+	}
+	now := uint64(time.Now().Unix())
+	if args.ExpiredTime != nil && uint64(*args.ExpiredTime) != 0 && uint64(*args.ExpiredTime) <= now {
+		return nil, fmt.Errorf("payer signature expired at %d", uint64(*args.ExpiredTime))
+	}
+	req := SignPayerTxRequest{
+		Transaction: args,
+		Callinfo:    msgs.Messages,
+		MaxFee:      (*hexutil.Big)(args.MaxFee()),
+		Time:        hexutil.Uint64(now),
+		Meta:        MetadataFromContext(ctx),
+	}
+	// Process approval
+	result, err := api.UI.ApprovePayerTx(&req)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Approved {
+		return nil, ErrRequestDenied
+	}
+	acc := accounts.Account{Address: args.Payer.Address()}
+	wallet, err := api.am.Find(acc)
+	if err != nil {
+		return nil, err
+	}
+	// The payer signs over the sender and the transaction fields
+	txdata := args.ToSponsoredTx()
+	payload, err := types.PayerSigningData(types.LatestSignerForChainID(api.chainID), args.From.Address(), txdata)
+	if err != nil {
+		return nil, err
+	}
+	pw, err := api.lookupOrQueryPassword(acc.Address, "Account password",
+		fmt.Sprintf("Please enter the password for account %s", acc.Address.String()))
+	if err != nil {
+		return nil, err
+	}
+	signature, err := wallet.SignDataWithPassphrase(acc, pw, accounts.MimetypeSponsoredTxPayer, payload)
+	if err != nil {
+		api.UI.ShowError(err.Error())
+		return nil, err
+	}
+	// V in payer signature is {0, 1}
+	txdata.PayerR = new(big.Int).SetBytes(signature[:32])
+	txdata.PayerS = new(big.Int).SetBytes(signature[32:64])
+	txdata.PayerV = new(big.Int).SetUint64(uint64(signature[64]))
+	signedTx := types.NewTx(txdata)
+
+	data, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	response := ethapi.SignTransactionResult{Raw: data, Tx: signedTx}
+
+	// Finally, send the signed tx to the UI
+	api.UI.OnApprovedPayerTx(SignPayerTxResult{
+		Sender:                args.From.Address(),
+		MaxFee:                req.MaxFee,
+		SignTransactionResult: response,
+	})
+	// ...and to the external caller
+	return &response, nil
+}
+
 func (api *SignerAPI) SignGnosisSafeTx(ctx context.Context, signerAddress common.MixedcaseAddress, gnosisTx GnosisSafeTx, methodSelector *string) (*GnosisSafeTx, error) {
 	// Do the usual validations, but on the last-stage transaction
 	args := gnosisTx.ArgsForValidation()
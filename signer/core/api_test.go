@@ -32,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/signer/core"
@@ -51,9 +52,10 @@ func (ui *headlessUi) OnInputRequired(info core.UserInputRequest) (core.UserInpu
 	return core.UserInputResponse{Text: input}, nil
 }
 
-func (ui *headlessUi) OnSignerStartup(info core.StartupInfo)        {}
-func (ui *headlessUi) RegisterUIServer(api *core.UIServerAPI)       {}
-func (ui *headlessUi) OnApprovedTx(tx ethapi.SignTransactionResult) {}
+func (ui *headlessUi) OnSignerStartup(info core.StartupInfo)           {}
+func (ui *headlessUi) RegisterUIServer(api *core.UIServerAPI)          {}
+func (ui *headlessUi) OnApprovedTx(tx ethapi.SignTransactionResult)    {}
+func (ui *headlessUi) OnApprovedPayerTx(result core.SignPayerTxResult) {}
 
 func (ui *headlessUi) ApproveTx(request *core.SignTxRequest) (core.SignTxResponse, error) {
 
@@ -71,6 +73,11 @@ func (ui *headlessUi) ApproveTx(request *core.SignTxRequest) (core.SignTxRespons
 	}
 }
 
+func (ui *headlessUi) ApprovePayerTx(request *core.SignPayerTxRequest) (core.SignPayerTxResponse, error) {
+	approved := (<-ui.approveCh == "Y")
+	return core.SignPayerTxResponse{approved}, nil
+}
+
 func (ui *headlessUi) ApproveSignData(request *core.SignDataRequest) (core.SignDataResponse, error) {
 	approved := (<-ui.approveCh == "Y")
 	return core.SignDataResponse{approved}, nil
@@ -224,6 +231,70 @@ func TestNewAcc(t *testing.T) {
 	}
 }
 
+func TestSignSponsoredTxAsPayer(t *testing.T) {
+	api, control := setup(t)
+	createAccount(control, api, t)
+	control.approveCh <- "A"
+	list, err := api.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	payer := common.NewMixedcaseAddress(list[0])
+
+	senderKey, _ := crypto.GenerateKey()
+	sender := common.NewMixedcaseAddress(crypto.PubkeyToAddress(senderKey.PublicKey))
+	expiredTime := hexutil.Uint64(time.Now().Add(time.Hour).Unix())
+
+	methodSig := "test(uint)"
+	tx := mkTestTx(sender)
+	tx.Payer = &payer
+	tx.ExpiredTime = &expiredTime
+
+	// The payer can not sponsor its own transaction
+	selfTx := mkTestTx(payer)
+	selfTx.Payer = &payer
+	if _, err := api.SignSponsoredTxAsPayer(context.Background(), selfTx, &methodSig); err != types.ErrSamePayerSenderSponsoredTx {
+		t.Errorf("Expected ErrSamePayerSenderSponsoredTx! %v", err)
+	}
+	control.approveCh <- "No way"
+	res, err := api.SignSponsoredTxAsPayer(context.Background(), tx, &methodSig)
+	if res != nil {
+		t.Errorf("Expected nil-response, got %v", res)
+	}
+	if err != core.ErrRequestDenied {
+		t.Errorf("Expected ErrRequestDenied! %v", err)
+	}
+	control.approveCh <- "Y"
+	control.inputCh <- "a_long_password"
+	res, err = api.SignSponsoredTxAsPayer(context.Background(), tx, &methodSig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedTx := &types.Transaction{}
+	if err := parsedTx.UnmarshalBinary(res.Raw); err != nil {
+		t.Fatal(err)
+	}
+	if parsedTx.Type() != types.SponsoredTxType {
+		t.Fatalf("Expected sponsored transaction, got type %d", parsedTx.Type())
+	}
+	if parsedTx.ExpiredTime() != uint64(expiredTime) {
+		t.Errorf("Expected expired time %d, got %d", expiredTime, parsedTx.ExpiredTime())
+	}
+	// The sender signs the transaction signed by the payer
+	signer := types.LatestSignerForChainID(big.NewInt(1337))
+	signedTx, err := types.SignTx(parsedTx, signer, senderKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered, err := types.Payer(signer, signedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered != payer.Address() {
+		t.Errorf("Expected payer %v, got %v", payer.Address(), recovered)
+	}
+}
+
 func mkTestTx(from common.MixedcaseAddress) apitypes.SendTxArgs {
 	to := common.NewMixedcaseAddress(common.HexToAddress("0x1337"))
 	gas := hexutil.Uint64(21000)
@@ -87,6 +87,10 @@ type SendTxArgs struct {
 	// For non-legacy transactions
 	AccessList *types.AccessList `json:"accessList,omitempty"`
 	ChainID    *hexutil.Big      `json:"chainId,omitempty"`
+
+	// For sponsored transactions
+	Payer       *common.MixedcaseAddress `json:"payer,omitempty"`
+	ExpiredTime *hexutil.Uint64          `json:"expiredTime,omitempty"`
 }
 
 func (args SendTxArgs) String() string {
@@ -97,24 +101,75 @@ func (args SendTxArgs) String() string {
 	return err.Error()
 }
 
-// ToTransaction converts the arguments to a transaction.
-func (args *SendTxArgs) ToTransaction() *types.Transaction {
-	// Add the To-field, if specified
-	var to *common.Address
-	if args.To != nil {
-		dstAddr := args.To.Address()
-		to = &dstAddr
+// to returns the recipient of the transaction, nil for contract creation.
+func (args *SendTxArgs) to() *common.Address {
+	if args.To == nil {
+		return nil
 	}
+	dstAddr := args.To.Address()
+	return &dstAddr
+}
 
-	var input []byte
+// input returns the transaction calldata. Input field is preferred.
+func (args *SendTxArgs) input() []byte {
 	if args.Input != nil {
-		input = *args.Input
+		return *args.Input
 	} else if args.Data != nil {
-		input = *args.Data
+		return *args.Data
+	}
+	return nil
+}
+
+// MaxFee returns the maximum gas fee the transaction can cost.
+func (args *SendTxArgs) MaxFee() *big.Int {
+	feeCap := args.MaxFeePerGas
+	if feeCap == nil {
+		feeCap = args.GasPrice
+	}
+	if feeCap == nil {
+		return new(big.Int)
 	}
+	return new(big.Int).Mul(feeCap.ToInt(), new(big.Int).SetUint64(uint64(args.Gas)))
+}
 
+// ToSponsoredTx converts the arguments to a sponsored transaction without any
+// signature. The legacy gas price is used as both fee caps if the 1559 fee
+// fields are not specified.
+func (args *SendTxArgs) ToSponsoredTx() *types.SponsoredTx {
+	gasFeeCap, gasTipCap := args.MaxFeePerGas, args.MaxPriorityFeePerGas
+	if gasFeeCap == nil {
+		gasFeeCap = args.GasPrice
+	}
+	if gasTipCap == nil {
+		gasTipCap = gasFeeCap
+	}
+	var expiredTime uint64
+	if args.ExpiredTime != nil {
+		expiredTime = uint64(*args.ExpiredTime)
+	}
+	return &types.SponsoredTx{
+		ChainID:     (*big.Int)(args.ChainID),
+		Nonce:       uint64(args.Nonce),
+		GasTipCap:   (*big.Int)(gasTipCap),
+		GasFeeCap:   (*big.Int)(gasFeeCap),
+		Gas:         uint64(args.Gas),
+		To:          args.to(),
+		Value:       (*big.Int)(&args.Value),
+		Data:        args.input(),
+		ExpiredTime: expiredTime,
+	}
+}
+
+// ToTransaction converts the arguments to a transaction.
+func (args *SendTxArgs) ToTransaction() *types.Transaction {
+	var (
+		to    = args.to()
+		input = args.input()
+	)
 	var data types.TxData
 	switch {
+	case args.Payer != nil:
+		data = args.ToSponsoredTx()
 	case args.MaxFeePerGas != nil:
 		al := types.AccessList{}
 		if args.AccessList != nil {
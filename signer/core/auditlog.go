@@ -62,6 +62,24 @@ func (l *AuditLogger) SignTransaction(ctx context.Context, args apitypes.SendTxA
 	return res, e
 }
 
+func (l *AuditLogger) SignSponsoredTxAsPayer(ctx context.Context, args apitypes.SendTxArgs, methodSelector *string) (*ethapi.SignTransactionResult, error) {
+	sel := "<nil>"
+	if methodSelector != nil {
+		sel = *methodSelector
+	}
+	l.log.Info("SignSponsoredTxAsPayer", "type", "request", "metadata", MetadataFromContext(ctx).String(),
+		"tx", args.String(),
+		"methodSelector", sel)
+
+	res, e := l.api.SignSponsoredTxAsPayer(ctx, args, methodSelector)
+	if res != nil {
+		l.log.Info("SignSponsoredTxAsPayer", "type", "response", "data", common.Bytes2Hex(res.Raw), "error", e)
+	} else {
+		l.log.Info("SignSponsoredTxAsPayer", "type", "response", "data", res, "error", e)
+	}
+	return res, e
+}
+
 func (l *AuditLogger) SignData(ctx context.Context, contentType string, addr common.MixedcaseAddress, data interface{}) (hexutil.Bytes, error) {
 	marshalledData, _ := json.Marshal(data) // can ignore error, marshalling what we just unmarshalled
 	l.log.Info("SignData", "type", "request", "metadata", MetadataFromContext(ctx).String(),
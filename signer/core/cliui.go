@@ -23,6 +23,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/console/prompt"
@@ -158,6 +159,60 @@ func (ui *CommandlineUI) ApproveTx(request *SignTxRequest) (SignTxResponse, erro
 	return SignTxResponse{request.Transaction, true}, nil
 }
 
+// ApprovePayerTx prompt the user for confirmation to request to sign sponsored Transaction as its payer
+func (ui *CommandlineUI) ApprovePayerTx(request *SignPayerTxRequest) (SignPayerTxResponse, error) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	fmt.Printf("--------- Sponsored transaction payer request-------------\n")
+	if payer := request.Transaction.Payer; payer != nil {
+		fmt.Printf("payer:  %v\n", payer.String())
+	}
+	fmt.Printf("sender: %v\n", request.Transaction.From.String())
+	if to := request.Transaction.To; to != nil {
+		fmt.Printf("to:     %v\n", to.Original())
+		if !to.ValidChecksum() {
+			fmt.Printf("\nWARNING: Invalid checksum on to-address!\n\n")
+		}
+	} else {
+		fmt.Printf("to:     <contact creation>\n")
+	}
+	fmt.Printf("value:  %v wei (paid by sender)\n", request.Transaction.Value.ToInt())
+	fmt.Printf("gas:    %v (%v)\n", request.Transaction.Gas, uint64(request.Transaction.Gas))
+	if request.Transaction.MaxFeePerGas != nil {
+		fmt.Printf("maxFeePerGas:          %v wei\n", request.Transaction.MaxFeePerGas.ToInt())
+		if request.Transaction.MaxPriorityFeePerGas != nil {
+			fmt.Printf("maxPriorityFeePerGas:  %v wei\n", request.Transaction.MaxPriorityFeePerGas.ToInt())
+		}
+	} else {
+		fmt.Printf("gasprice: %v wei\n", request.Transaction.GasPrice.ToInt())
+	}
+	fmt.Printf("max fee:  %v wei (paid by payer)\n", request.MaxFee.ToInt())
+	fmt.Printf("nonce:    %v (%v)\n", request.Transaction.Nonce, uint64(request.Transaction.Nonce))
+	if expiredTime := request.Transaction.ExpiredTime; expiredTime != nil && *expiredTime != 0 {
+		fmt.Printf("expires:  %v (in %v)\n", time.Unix(int64(*expiredTime), 0),
+			time.Duration(uint64(*expiredTime)-uint64(request.Time))*time.Second)
+	} else {
+		fmt.Printf("expires:  never\n")
+	}
+	if request.Transaction.Data != nil {
+		d := *request.Transaction.Data
+		if len(d) > 0 {
+			fmt.Printf("data:     %v\n", hexutil.Encode(d))
+		}
+	}
+	if request.Callinfo != nil {
+		fmt.Printf("\nTransaction validation:\n")
+		for _, m := range request.Callinfo {
+			fmt.Printf("  * %s : %s\n", m.Typ, m.Message)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("\n")
+	showMetadata(request.Meta)
+	fmt.Printf("-------------------------------------------\n")
+	return SignPayerTxResponse{ui.confirm()}, nil
+}
+
 // ApproveSignData prompt the user for confirmation to request to sign data
 func (ui *CommandlineUI) ApproveSignData(request *SignDataRequest) (SignDataResponse, error) {
 	ui.mu.Lock()
@@ -244,6 +299,15 @@ func (ui *CommandlineUI) OnApprovedTx(tx ethapi.SignTransactionResult) {
 	}
 }
 
+func (ui *CommandlineUI) OnApprovedPayerTx(result SignPayerTxResult) {
+	fmt.Printf("Sponsored transaction of %v signed by payer:\n ", result.Sender)
+	if jsn, err := json.MarshalIndent(result.Tx, "  ", "  "); err != nil {
+		fmt.Printf("WARN: marshalling error %v\n", err)
+	} else {
+		fmt.Println(string(jsn))
+	}
+}
+
 func (ui *CommandlineUI) OnSignerStartup(info StartupInfo) {
 
 	fmt.Printf("------- Signer info -------\n")
@@ -66,6 +66,12 @@ func (ui *StdIOUI) ApproveTx(request *SignTxRequest) (SignTxResponse, error) {
 	return result, err
 }
 
+func (ui *StdIOUI) ApprovePayerTx(request *SignPayerTxRequest) (SignPayerTxResponse, error) {
+	var result SignPayerTxResponse
+	err := ui.dispatch("ui_approvePayerTx", request, &result)
+	return result, err
+}
+
 func (ui *StdIOUI) ApproveSignData(request *SignDataRequest) (SignDataResponse, error) {
 	var result SignDataResponse
 	err := ui.dispatch("ui_approveSignData", request, &result)
@@ -104,6 +110,13 @@ func (ui *StdIOUI) OnApprovedTx(tx ethapi.SignTransactionResult) {
 	}
 }
 
+func (ui *StdIOUI) OnApprovedPayerTx(result SignPayerTxResult) {
+	err := ui.notify("ui_onApprovedPayerTx", result)
+	if err != nil {
+		log.Info("Error calling 'ui_onApprovedPayerTx'", "exc", err.Error(), "tx", result.Tx)
+	}
+}
+
 func (ui *StdIOUI) OnSignerStartup(info StartupInfo) {
 	err := ui.notify("ui_onSignerStartup", info)
 	if err != nil {
@@ -170,6 +170,21 @@ func (r *rulesetUI) ApproveTx(request *core.SignTxRequest) (core.SignTxResponse,
 	return core.SignTxResponse{Approved: false}, err
 }
 
+// ApprovePayerTx evaluates the ApprovePayerTx rule for a request to sign a
+// sponsored transaction as its payer. The request carries the sender, the call
+// target and decoded calldata, the max fee charged to the payer and the current
+// time, so the rules can approve by contract allowlist, per-sender budget (kept
+// in the storage by OnApprovedPayerTx) and expiry window.
+func (r *rulesetUI) ApprovePayerTx(request *core.SignPayerTxRequest) (core.SignPayerTxResponse, error) {
+	jsonreq, err := json.Marshal(request)
+	approved, err := r.checkApproval("ApprovePayerTx", jsonreq, err)
+	if err != nil {
+		log.Info("Rule-based approval error, going to manual", "error", err)
+		return r.next.ApprovePayerTx(request)
+	}
+	return core.SignPayerTxResponse{Approved: approved}, nil
+}
+
 func (r *rulesetUI) ApproveSignData(request *core.SignDataRequest) (core.SignDataResponse, error) {
 	jsonreq, err := json.Marshal(request)
 	approved, err := r.checkApproval("ApproveSignData", jsonreq, err)
@@ -230,6 +245,18 @@ func (r *rulesetUI) OnSignerStartup(info core.StartupInfo) {
 	}
 }
 
+func (r *rulesetUI) OnApprovedPayerTx(result core.SignPayerTxResult) {
+	jsonTx, err := json.Marshal(result)
+	if err != nil {
+		log.Warn("failed marshalling transaction", "tx", result.Tx)
+		return
+	}
+	_, err = r.execute("OnApprovedPayerTx", string(jsonTx))
+	if err != nil {
+		log.Info("error occurred during execution", "error", err)
+	}
+}
+
 func (r *rulesetUI) OnApprovedTx(tx ethapi.SignTransactionResult) {
 	jsonTx, err := json.Marshal(tx)
 	if err != nil {
@@ -19,8 +19,10 @@ package rules
 import (
 	"fmt"
 	"math/big"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
@@ -88,6 +90,10 @@ func (alwaysDenyUI) ApproveTx(request *core.SignTxRequest) (core.SignTxResponse,
 	return core.SignTxResponse{Transaction: request.Transaction, Approved: false}, nil
 }
 
+func (alwaysDenyUI) ApprovePayerTx(request *core.SignPayerTxRequest) (core.SignPayerTxResponse, error) {
+	return core.SignPayerTxResponse{Approved: false}, nil
+}
+
 func (alwaysDenyUI) ApproveSignData(request *core.SignDataRequest) (core.SignDataResponse, error) {
 	return core.SignDataResponse{Approved: false}, nil
 }
@@ -112,6 +118,10 @@ func (alwaysDenyUI) OnApprovedTx(tx ethapi.SignTransactionResult) {
 	panic("implement me")
 }
 
+func (alwaysDenyUI) OnApprovedPayerTx(result core.SignPayerTxResult) {
+	panic("implement me")
+}
+
 func initRuleEngine(js string) (*rulesetUI, error) {
 	r, err := NewRuleEvaluator(&alwaysDenyUI{}, storage.NewEphemeralStorage())
 	if err != nil {
@@ -213,6 +223,11 @@ func (d *dummyUI) ApproveTx(request *core.SignTxRequest) (core.SignTxResponse, e
 	return core.SignTxResponse{}, core.ErrRequestDenied
 }
 
+func (d *dummyUI) ApprovePayerTx(request *core.SignPayerTxRequest) (core.SignPayerTxResponse, error) {
+	d.calls = append(d.calls, "ApprovePayerTx")
+	return core.SignPayerTxResponse{}, core.ErrRequestDenied
+}
+
 func (d *dummyUI) ApproveSignData(request *core.SignDataRequest) (core.SignDataResponse, error) {
 	d.calls = append(d.calls, "ApproveSignData")
 	return core.SignDataResponse{}, core.ErrRequestDenied
@@ -240,6 +255,10 @@ func (d *dummyUI) OnApprovedTx(tx ethapi.SignTransactionResult) {
 	d.calls = append(d.calls, "OnApprovedTx")
 }
 
+func (d *dummyUI) OnApprovedPayerTx(result core.SignPayerTxResult) {
+	d.calls = append(d.calls, "OnApprovedPayerTx")
+}
+
 func (d *dummyUI) OnSignerStartup(info core.StartupInfo) {
 }
 
@@ -258,6 +277,7 @@ func TestForwarding(t *testing.T) {
 	}
 	r.ApproveSignData(nil)
 	r.ApproveTx(nil)
+	r.ApprovePayerTx(nil)
 	r.ApproveNewAccount(nil)
 	r.ApproveListing(nil)
 	r.ShowError("test")
@@ -265,8 +285,9 @@ func TestForwarding(t *testing.T) {
 
 	//This one is not forwarded
 	r.OnApprovedTx(ethapi.SignTransactionResult{})
+	r.OnApprovedPayerTx(core.SignPayerTxResult{})
 
-	expCalls := 6
+	expCalls := 7
 	if len(ui.calls) != expCalls {
 
 		t.Errorf("Expected %d forwarded calls, got %d: %s", expCalls, len(ui.calls), strings.Join(ui.calls, ","))
@@ -517,6 +538,11 @@ func (d *dontCallMe) ApproveTx(request *core.SignTxRequest) (core.SignTxResponse
 	return core.SignTxResponse{}, core.ErrRequestDenied
 }
 
+func (d *dontCallMe) ApprovePayerTx(request *core.SignPayerTxRequest) (core.SignPayerTxResponse, error) {
+	d.t.Fatalf("Did not expect next-handler to be called")
+	return core.SignPayerTxResponse{}, core.ErrRequestDenied
+}
+
 func (d *dontCallMe) ApproveSignData(request *core.SignDataRequest) (core.SignDataResponse, error) {
 	d.t.Fatalf("Did not expect next-handler to be called")
 	return core.SignDataResponse{}, core.ErrRequestDenied
@@ -544,6 +570,10 @@ func (d *dontCallMe) OnApprovedTx(tx ethapi.SignTransactionResult) {
 	d.t.Fatalf("Did not expect next-handler to be called")
 }
 
+func (d *dontCallMe) OnApprovedPayerTx(result core.SignPayerTxResult) {
+	d.t.Fatalf("Did not expect next-handler to be called")
+}
+
 //TestContextIsCleared tests that the rule-engine does not retain variables over several requests.
 // if it does, that would be bad since developers may rely on that to store data,
 // instead of using the disk-based data storage
@@ -626,3 +656,91 @@ function ApproveSignData(r){
 		t.Fatalf("Expected approved")
 	}
 }
+
+// payerRules extracts the ruleset of the payer example from the clef rules
+// documentation, so the example is kept working.
+func payerRules(t *testing.T) string {
+	doc, err := os.ReadFile("../../cmd/clef/rules.md")
+	if err != nil {
+		t.Fatalf("failed to read rules documentation: %v", err)
+	}
+	_, example, ok := strings.Cut(string(doc), "## Example 4: sponsoring transactions as payer")
+	if !ok {
+		t.Fatal("payer example not found in the rules documentation")
+	}
+	_, example, _ = strings.Cut(example, "```js\n")
+	example, _, ok = strings.Cut(example, "```")
+	if !ok {
+		t.Fatal("payer ruleset not found in the rules documentation")
+	}
+	return example
+}
+
+func TestPayerRules(t *testing.T) {
+	r, err := NewRuleEvaluator(&dontCallMe{t}, storage.NewEphemeralStorage())
+	if err != nil {
+		t.Fatalf("failed to create js engine: %v", err)
+	}
+	if err := r.Init(payerRules(t)); err != nil {
+		t.Fatalf("failed to load payer rules: %v", err)
+	}
+
+	var (
+		now     = uint64(time.Now().Unix())
+		allowed = common.NewMixedcaseAddress(common.HexToAddress("0x07a565b7ed7d7a678680a4c162885bedbb695fe0"))
+		other   = common.NewMixedcaseAddress(common.HexToAddress("0x000000000000000000000000000000000000dead"))
+		sender  = common.HexToAddress("0x694267f14675d7e1b9494fd8d72fefe1755710fa")
+		fee     = big.NewInt(4e16) // 0.04 ether, the daily budget covers 2 transactions
+	)
+	request := func(to common.MixedcaseAddress, expiry uint64) *core.SignPayerTxRequest {
+		expiredTime := hexutil.Uint64(expiry)
+		return &core.SignPayerTxRequest{
+			Transaction: apitypes.SendTxArgs{
+				From:        common.NewMixedcaseAddress(sender),
+				To:          &to,
+				Gas:         hexutil.Uint64(21000),
+				ExpiredTime: &expiredTime,
+			},
+			MaxFee: (*hexutil.Big)(fee),
+			Time:   hexutil.Uint64(now),
+			Meta:   core.Metadata{Remote: "remoteip", Local: "localip", Scheme: "inproc"},
+		}
+	}
+	approve := func(req *core.SignPayerTxRequest) bool {
+		resp, err := r.ApprovePayerTx(req)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		return resp.Approved
+	}
+
+	// Calls outside the allowlist and over the expiry window are rejected
+	if approve(request(other, now+60)) {
+		t.Error("expected call to a contract outside the allowlist to be rejected")
+	}
+	if approve(request(allowed, 0)) {
+		t.Error("expected transaction without expiry to be rejected")
+	}
+	if approve(request(allowed, now+3600)) {
+		t.Error("expected transaction over the expiry window to be rejected")
+	}
+
+	// The approved transactions are charged to the daily budget of the sender
+	for i := 0; i < 2; i++ {
+		req := request(allowed, now+60)
+		if !approve(req) {
+			t.Fatalf("expected transaction %d to be approved", i)
+		}
+		r.OnApprovedPayerTx(core.SignPayerTxResult{
+			Sender: sender,
+			MaxFee: req.MaxFee,
+			SignTransactionResult: ethapi.SignTransactionResult{
+				Tx:  dummySigned(big.NewInt(0)),
+				Raw: common.Hex2Bytes("deadbeef"),
+			},
+		})
+	}
+	if approve(request(allowed, now+60)) {
+		t.Error("expected transaction over the daily budget to be rejected")
+	}
+}